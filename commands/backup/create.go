@@ -0,0 +1,82 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup holds commands for backup command.
+package backup
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/backup"
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewCreateCmd returns a new create command.
+func NewCreateCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create NAME",
+		Args:    cobra.ExactArgs(1),
+		Example: "everestctl backup create mysql-1-backup --namespace dev --cluster mysql-1 --backup-storage s3",
+		Run: func(cmd *cobra.Command, args []string) {
+			initCreateViperFlags(cmd)
+
+			c := &backup.CreateConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.Name = args[0]
+
+			command, err := backup.NewCreate(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initCreateFlags(cmd)
+
+	return cmd
+}
+
+func initCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("namespace", "", "Namespace of the database cluster")
+	cmd.Flags().String("cluster", "", "Name of the database cluster to back up")
+	cmd.Flags().String("backup-storage", "", "Name of the backup storage to store the backup in")
+}
+
+func initCreateViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                                 //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))             //nolint:errcheck,gosec
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))               //nolint:errcheck,gosec
+	viper.BindPFlag("cluster", cmd.Flags().Lookup("cluster"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("backup-storage", cmd.Flags().Lookup("backup-storage"))     //nolint:errcheck,gosec
+}
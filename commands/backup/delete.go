@@ -0,0 +1,73 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/backup"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewDeleteCmd returns a new delete command.
+func NewDeleteCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete NAME",
+		Args:    cobra.ExactArgs(1),
+		Example: "everestctl backup delete mysql-1-backup --namespace dev",
+		Run: func(cmd *cobra.Command, args []string) {
+			initDeleteViperFlags(cmd)
+
+			c := &backup.DeleteConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.Name = args[0]
+
+			command, err := backup.NewDelete(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			if err := command.Run(cmd.Context()); err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+		},
+	}
+
+	initDeleteFlags(cmd)
+
+	return cmd
+}
+
+func initDeleteFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("namespace", "", "Namespace of the backup")
+	cmd.Flags().BoolP("assume-yes", "y", false, "Assume yes to all questions")
+}
+
+func initDeleteViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                     //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))   //nolint:errcheck,gosec
+	viper.BindPFlag("assume-yes", cmd.Flags().Lookup("assume-yes")) //nolint:errcheck,gosec
+}
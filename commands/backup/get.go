@@ -0,0 +1,74 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/backup"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewGetCmd returns a new get command.
+func NewGetCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get NAME",
+		Args:    cobra.ExactArgs(1),
+		Example: "everestctl backup get mysql-1-backup --namespace dev",
+		Run: func(cmd *cobra.Command, args []string) {
+			initGetViperFlags(cmd)
+
+			c := &backup.GetConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.Name = args[0]
+
+			command, err := backup.NewGet(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initGetFlags(cmd)
+
+	return cmd
+}
+
+func initGetFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("namespace", "", "Namespace of the backup")
+}
+
+func initGetViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                     //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))   //nolint:errcheck,gosec
+}
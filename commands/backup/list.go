@@ -0,0 +1,78 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/backup"
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewListCmd returns a new list command.
+func NewListCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Args:    cobra.NoArgs,
+		Example: "everestctl backup list --namespace dev",
+		Run: func(cmd *cobra.Command, args []string) {
+			initListViperFlags(cmd)
+
+			c := &backup.ListConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+
+			command, err := backup.NewList(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initListFlags(cmd)
+
+	return cmd
+}
+
+func initListFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("namespace", "", "List the backups of the namespace only. Defaults to all namespaces managed by Everest")
+	cmd.Flags().String("cluster", "", "List the backups of the database cluster only")
+}
+
+func initListViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                                 //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))             //nolint:errcheck,gosec
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))               //nolint:errcheck,gosec
+	viper.BindPFlag("cluster", cmd.Flags().Lookup("cluster"))                   //nolint:errcheck,gosec
+}
@@ -0,0 +1,100 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backupstorage holds commands for backup-storage command.
+package backupstorage
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/backupstorage"
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewAddCmd returns a new add command.
+func NewAddCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  "add NAME",
+		Args: cobra.ExactArgs(1),
+		Example: "everestctl backup-storage add s3 --type s3 --bucket backups --region us-east-1 " +
+			"--access-key KEY --secret-key SECRET --allowed-namespaces dev,prod",
+		Run: func(cmd *cobra.Command, args []string) {
+			initAddViperFlags(cmd)
+
+			c := &backupstorage.AddConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.Name = args[0]
+
+			command, err := backupstorage.NewAdd(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initAddFlags(cmd)
+
+	return cmd
+}
+
+func initAddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("type", "", "Type of the backup storage: s3 or azure")
+	cmd.Flags().String("bucket", "", "Name of the S3 bucket or the Azure container")
+	cmd.Flags().String("region", "", "Region of the bucket. Required for S3")
+	cmd.Flags().String("endpoint-url", "", "Endpoint of the storage. Defaults to the AWS or Azure endpoint")
+	cmd.Flags().String("description", "", "Description of the backup storage")
+	cmd.Flags().String("access-key", "", "Access key ID for S3 or account name for Azure. "+
+		"Can be set with the "+backupstorage.AccessKeyEnvVar+" environment variable")
+	cmd.Flags().String("secret-key", "", "Secret access key for S3 or account key for Azure. "+
+		"Can be set with the "+backupstorage.SecretKeyEnvVar+" environment variable")
+	cmd.Flags().Bool("secret-key-stdin", false, "Read the secret key from the standard input")
+	cmd.Flags().String("allowed-namespaces", "", "Comma-separated namespaces allowed to use the storage. "+
+		"Defaults to all namespaces managed by Everest")
+}
+
+func initAddViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                                     //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace"))     //nolint:errcheck,gosec
+	viper.BindPFlag("type", cmd.Flags().Lookup("type"))                             //nolint:errcheck,gosec
+	viper.BindPFlag("bucket", cmd.Flags().Lookup("bucket"))                         //nolint:errcheck,gosec
+	viper.BindPFlag("region", cmd.Flags().Lookup("region"))                         //nolint:errcheck,gosec
+	viper.BindPFlag("endpoint-url", cmd.Flags().Lookup("endpoint-url"))             //nolint:errcheck,gosec
+	viper.BindPFlag("description", cmd.Flags().Lookup("description"))               //nolint:errcheck,gosec
+	viper.BindPFlag("access-key", cmd.Flags().Lookup("access-key"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("secret-key", cmd.Flags().Lookup("secret-key"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("secret-key-stdin", cmd.Flags().Lookup("secret-key-stdin"))     //nolint:errcheck,gosec
+	viper.BindEnv("access-key", backupstorage.AccessKeyEnvVar)                      //nolint:errcheck,gosec
+	viper.BindEnv("secret-key", backupstorage.SecretKeyEnvVar)                      //nolint:errcheck,gosec
+	viper.BindPFlag("allowed-namespaces", cmd.Flags().Lookup("allowed-namespaces")) //nolint:errcheck,gosec
+}
@@ -0,0 +1,73 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle holds commands for bundle command.
+package bundle
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/bundle"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewCreateCmd returns a new create command.
+func NewCreateCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create",
+		Args:    cobra.NoArgs,
+		Example: "everestctl bundle create --output everest-bundle.tar.gz",
+		Run: func(cmd *cobra.Command, args []string) {
+			initCreateViperFlags(cmd)
+
+			c := &bundle.CreateConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+
+			res, err := bundle.NewCreate(*c, l).Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initCreateFlags(cmd)
+
+	return cmd
+}
+
+func initCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "everest-bundle.tar.gz", "Path the bundle is written to")
+	cmd.Flags().String("catalog-image", "", "Everest catalog image. Defaults to the image matching the CLI version")
+	cmd.Flags().String("manifest-file", "", "Path to a local Everest manifest used instead of downloading it")
+	cmd.Flags().String("catalog-file", "", "Path to the catalog rendered with opm render used instead of pulling the catalog image")
+	cmd.Flags().StringSlice("image", []string{}, "Additional image to be mirrored. Can be repeated")
+}
+
+func initCreateViperFlags(cmd *cobra.Command) {
+	viper.BindPFlag("output", cmd.Flags().Lookup("output"))               //nolint:errcheck,gosec
+	viper.BindPFlag("catalog-image", cmd.Flags().Lookup("catalog-image")) //nolint:errcheck,gosec
+	viper.BindPFlag("manifest-file", cmd.Flags().Lookup("manifest-file")) //nolint:errcheck,gosec
+	viper.BindPFlag("catalog-file", cmd.Flags().Lookup("catalog-file"))   //nolint:errcheck,gosec
+	viper.BindPFlag("image", cmd.Flags().Lookup("image"))                 //nolint:errcheck,gosec
+}
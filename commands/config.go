@@ -0,0 +1,45 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+)
+
+// initConfigFileFlag adds the --config flag to the command.
+func initConfigFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("config", "", "Path to a YAML or JSON file describing the Everest deployment. Command line flags take precedence over the file")
+}
+
+// readConfigFile validates the file passed via --config and loads it into viper.
+// It does nothing if the flag is not set.
+func readConfigFile(cmd *cobra.Command) error {
+	path, err := cmd.Flags().GetString("config")
+	if err != nil || path == "" {
+		return err
+	}
+
+	if _, err := install.ValidateConfigFile(path); err != nil {
+		return err
+	}
+
+	viper.SetConfigFile(path)
+	viper.SetConfigType("yaml")
+	return viper.ReadInConfig()
+}
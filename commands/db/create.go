@@ -0,0 +1,114 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db holds commands for db command.
+package db
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/db"
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// defaultWaitTimeout is the default time to wait for a database cluster to become ready.
+const defaultWaitTimeout = 15 * time.Minute
+
+// NewCreateCmd returns a new create command.
+func NewCreateCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create NAME",
+		Args:    cobra.ExactArgs(1),
+		Example: "everestctl db create mysql-1 --namespace dev --engine pxc --replicas 3 --storage-size 10Gi --wait",
+		Run: func(cmd *cobra.Command, args []string) {
+			initCreateViperFlags(cmd)
+
+			c := &db.CreateConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.Name = args[0]
+
+			command, err := db.NewCreate(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initCreateFlags(cmd)
+
+	return cmd
+}
+
+func initCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("namespace", "", "Namespace managed by Everest to create the database cluster in")
+	cmd.Flags().String("engine", "", "Database engine type: pxc, psmdb or postgresql")
+	cmd.Flags().String("version", "", "Database engine version. Defaults to the recommended version")
+	cmd.Flags().Int32("replicas", 1, "Number of database replicas")
+	cmd.Flags().String("storage-size", "25Gi", "Size of the volume of every replica")
+	cmd.Flags().String("storage-class", "", "Storage class of the volumes. Defaults to the default storage class")
+	cmd.Flags().String("cpu", "", "CPU of every replica, e.g. 1 or 500m")
+	cmd.Flags().String("memory", "", "Memory of every replica, e.g. 2Gi")
+	cmd.Flags().String("proxy-type", "", "Proxy type. Defaults to haproxy for pxc, mongos for psmdb and pgbouncer for postgresql")
+	cmd.Flags().Int32("proxy-replicas", 0, "Number of proxy replicas. Defaults to the number of database replicas")
+	cmd.Flags().Bool("expose-external", false, "Expose the database cluster outside of Kubernetes")
+	cmd.Flags().String("ip-source-ranges", "", "Comma-separated list of the IP ranges allowed to access the exposed database cluster")
+	cmd.Flags().String("backup-schedule", "", "Cron schedule of the backups, e.g. '0 0 * * *'. Backups are disabled if empty")
+	cmd.Flags().String("backup-storage", "", "Name of the backup storage the scheduled backups are stored in")
+	cmd.Flags().Int32("backup-retention", 0, "Number of the scheduled backups to keep. All backups are kept if 0")
+	cmd.Flags().Bool("wait", false, "Wait until the database cluster is ready")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "Time to wait for the database cluster to become ready")
+}
+
+func initCreateViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                                 //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))             //nolint:errcheck,gosec
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))               //nolint:errcheck,gosec
+	viper.BindPFlag("engine", cmd.Flags().Lookup("engine"))                     //nolint:errcheck,gosec
+	viper.BindPFlag("version", cmd.Flags().Lookup("version"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("replicas", cmd.Flags().Lookup("replicas"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("storage-size", cmd.Flags().Lookup("storage-size"))         //nolint:errcheck,gosec
+	viper.BindPFlag("storage-class", cmd.Flags().Lookup("storage-class"))       //nolint:errcheck,gosec
+	viper.BindPFlag("cpu", cmd.Flags().Lookup("cpu"))                           //nolint:errcheck,gosec
+	viper.BindPFlag("memory", cmd.Flags().Lookup("memory"))                     //nolint:errcheck,gosec
+	viper.BindPFlag("proxy-type", cmd.Flags().Lookup("proxy-type"))             //nolint:errcheck,gosec
+	viper.BindPFlag("proxy-replicas", cmd.Flags().Lookup("proxy-replicas"))     //nolint:errcheck,gosec
+	viper.BindPFlag("expose-external", cmd.Flags().Lookup("expose-external"))   //nolint:errcheck,gosec
+	viper.BindPFlag("ip-source-ranges", cmd.Flags().Lookup("ip-source-ranges")) //nolint:errcheck,gosec
+	viper.BindPFlag("backup-schedule", cmd.Flags().Lookup("backup-schedule"))   //nolint:errcheck,gosec
+	viper.BindPFlag("backup-storage", cmd.Flags().Lookup("backup-storage"))     //nolint:errcheck,gosec
+	viper.BindPFlag("backup-retention", cmd.Flags().Lookup("backup-retention")) //nolint:errcheck,gosec
+	viper.BindPFlag("wait", cmd.Flags().Lookup("wait"))                         //nolint:errcheck,gosec
+	viper.BindPFlag("wait-timeout", cmd.Flags().Lookup("wait-timeout"))         //nolint:errcheck,gosec
+}
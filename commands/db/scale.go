@@ -0,0 +1,86 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/db"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewScaleCmd returns a new scale command.
+func NewScaleCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "scale NAME",
+		Args:    cobra.ExactArgs(1),
+		Example: "everestctl db scale mysql-1 --namespace dev --replicas 5 --wait",
+		Run: func(cmd *cobra.Command, args []string) {
+			initScaleViperFlags(cmd)
+
+			c := &db.ScaleConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.Name = args[0]
+
+			command, err := db.NewScale(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initScaleFlags(cmd)
+
+	return cmd
+}
+
+func initScaleFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("namespace", "", "Namespace of the database cluster")
+	cmd.Flags().Int32("replicas", 0, "Number of database replicas")
+	cmd.Flags().Int32("proxy-replicas", 0, "Number of proxy replicas")
+	cmd.Flags().String("cpu", "", "CPU of every replica, e.g. 1 or 500m")
+	cmd.Flags().String("memory", "", "Memory of every replica, e.g. 2Gi")
+	cmd.Flags().Bool("wait", false, "Wait until the database cluster is ready")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "Time to wait for the database cluster to become ready")
+}
+
+func initScaleViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                             //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))         //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))           //nolint:errcheck,gosec
+	viper.BindPFlag("replicas", cmd.Flags().Lookup("replicas"))             //nolint:errcheck,gosec
+	viper.BindPFlag("proxy-replicas", cmd.Flags().Lookup("proxy-replicas")) //nolint:errcheck,gosec
+	viper.BindPFlag("cpu", cmd.Flags().Lookup("cpu"))                       //nolint:errcheck,gosec
+	viper.BindPFlag("memory", cmd.Flags().Lookup("memory"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("wait", cmd.Flags().Lookup("wait"))                     //nolint:errcheck,gosec
+	viper.BindPFlag("wait-timeout", cmd.Flags().Lookup("wait-timeout"))     //nolint:errcheck,gosec
+}
@@ -24,6 +24,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
 	"github.com/percona/percona-everest-cli/pkg/output"
 )
 
@@ -35,10 +36,20 @@ func newInstallCmd(l *zap.SugaredLogger) *cobra.Command {
 		//       ./everestctl install --namespaces=aaa, a
 		// it will return
 		//        Error: unknown command "a" for "everestctl install"
-		Args:    cobra.NoArgs,
-		Example: "everestctl install --namespaces dev,staging,prod --operator.mongodb=true --operator.postgresql=true --operator.xtradb-cluster=true --skip-wizard",
+		Args: cobra.NoArgs,
+		Example: "everestctl install --namespaces dev,staging,prod --operator.mongodb=true --operator.postgresql=true --operator.xtradb-cluster=true --skip-wizard\n" +
+			"everestctl install --config everest.yaml --skip-wizard\n" +
+			"everestctl install --namespaces dev --skip-wizard --render-only ./manifests\n" +
+			"everestctl install --namespaces dev --skip-wizard --bundle everest-bundle.tar.gz\n" +
+			"everestctl install --namespaces dev --skip-wizard --operator-version.postgresql=2.3.1\n" +
+			"everestctl install --namespace-operators 'dev=pg,mongodb;prod=pxc' --skip-wizard",
 		Run: func(cmd *cobra.Command, args []string) {
 			initInstallViperFlags(cmd)
+			if err := readConfigFile(cmd); err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
 			c := &install.Config{}
 			err := viper.Unmarshal(c)
 			if err != nil {
@@ -51,7 +62,12 @@ func newInstallCmd(l *zap.SugaredLogger) *cobra.Command {
 				os.Exit(1)
 			}
 
-			if err := op.Run(cmd.Context()); err != nil {
+			err = op.Run(cmd.Context())
+			// The changes planned before a failure are printed too.
+			if c.DryRun {
+				output.PrintOutput(cmd, l, op.Plan())
+			}
+			if err != nil {
 				output.PrintError(err, l)
 				os.Exit(1)
 			}
@@ -64,22 +80,94 @@ func newInstallCmd(l *zap.SugaredLogger) *cobra.Command {
 
 func initInstallFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("monitoring-namespace", install.MonitoringNamespace, "Namespace where the monitoring stack is installed")
+	cmd.Flags().String("olm-namespace", kubernetes.OLMNamespace, "Namespace where OLM is installed unless the existing OLM is used")
 	cmd.Flags().String("namespaces", "", "Comma-separated namespaces list Percona Everest can manage")
 	cmd.Flags().Bool("skip-wizard", false, "Skip installation wizard")
+	cmd.Flags().String("catalog-image", "", "Everest catalog image. Defaults to the image matching the CLI version")
+	cmd.Flags().Bool("disable-telemetry", false, "Disable telemetry in the installed operators")
+	cmd.Flags().String("manifest-file", "", "Path to a local Everest manifest used instead of downloading it")
+	cmd.Flags().String("bundle", "", "Path to an offline installation bundle created by `everestctl bundle create`")
+	cmd.MarkFlagsMutuallyExclusive("manifest-file", "bundle")
+	cmd.Flags().StringSlice("registry-mirror", []string{}, "Registry mirror in the source=mirror format, e.g. docker.io=harbor.example.com/dockerhub. Can be repeated")
+	cmd.Flags().String("image-pull-secret", "", "Name of the image pull secret attached to the catalog source and the deployments. "+
+		"The secret is copied from the system namespace into the other namespaces")
+	initConfigFileFlag(cmd)
+	cmd.Flags().Bool("dry-run", false, "Print the changes instead of applying them to the cluster")
+	cmd.Flags().String("render-only", "", "Write the manifests to the directory instead of applying them to the cluster")
+	cmd.Flags().Bool("resume", false, "Resume a failed installation skipping the completed steps. "+
+		"The operators and channels of the failed installation are used")
+	cmd.Flags().Bool("skip-preflight", false, "Skip the checks of the cluster run before the installation")
+	cmd.Flags().String("install-plan-approval", install.InstallPlanApprovalManual,
+		"Install plan approval policy: automatic, manual or prompt. Upgrades have to be approved with `everestctl operators pending` unless automatic")
+	cmd.Flags().Bool("use-existing-olm", false,
+		"Use the OLM already installed in the cluster instead of installing it. Enabled automatically on OpenShift")
+	cmd.Flags().Bool("set-default-storage-class", false,
+		"Make a storage class suitable for the detected cluster default if the cluster has no default storage class. Reverted on uninstall")
+	cmd.Flags().String("everest-service-type", "",
+		"Type of the everest service: ClusterIP, NodePort or LoadBalancer. Defaults to the type suitable for the detected cluster")
 
 	cmd.Flags().Bool("operator.mongodb", true, "Install MongoDB operator")
 	cmd.Flags().Bool("operator.postgresql", true, "Install PostgreSQL operator")
 	cmd.Flags().Bool("operator.xtradb-cluster", true, "Install XtraDB Cluster operator")
+	cmd.Flags().String("namespace-operators", "",
+		"Operators installed per namespace, e.g. dev=pg,mongodb;prod=pxc. Namespaces not listed get the operators selected with --operator.*")
+
+	cmd.Flags().String("channel.everest", "", "Channel for the Everest operator")
+	cmd.Flags().String("channel.mongodb", "", "Channel for the MongoDB operator")
+	cmd.Flags().String("channel.postgresql", "", "Channel for the PostgreSQL operator")
+	cmd.Flags().String("channel.xtradb-cluster", "", "Channel for the XtraDB Cluster operator")
+	cmd.Flags().String("channel.victoria-metrics", "", "Channel for the VictoriaMetrics operator")
+
+	cmd.Flags().String("operator-version.everest", "", "Version of the Everest operator. Defaults to the head of the channel")
+	cmd.Flags().String("operator-version.mongodb", "", "Version of the MongoDB operator. Defaults to the head of the channel")
+	cmd.Flags().String("operator-version.postgresql", "", "Version of the PostgreSQL operator. Defaults to the head of the channel")
+	cmd.Flags().String("operator-version.xtradb-cluster", "", "Version of the XtraDB Cluster operator. Defaults to the head of the channel")
+	cmd.Flags().String("operator-version.victoria-metrics", "", "Version of the VictoriaMetrics operator. Defaults to the head of the channel")
 }
 
 func initInstallViperFlags(cmd *cobra.Command) {
-	viper.BindPFlag("skip-wizard", cmd.Flags().Lookup("skip-wizard")) //nolint:errcheck,gosec
+	viper.BindPFlag("skip-wizard", cmd.Flags().Lookup("skip-wizard"))                             //nolint:errcheck,gosec
+	viper.BindPFlag("dry-run", cmd.Flags().Lookup("dry-run"))                                     //nolint:errcheck,gosec
+	viper.BindPFlag("render-only", cmd.Flags().Lookup("render-only"))                             //nolint:errcheck,gosec
+	viper.BindPFlag("resume", cmd.Flags().Lookup("resume"))                                       //nolint:errcheck,gosec
+	viper.BindPFlag("skip-preflight", cmd.Flags().Lookup("skip-preflight"))                       //nolint:errcheck,gosec
+	viper.BindPFlag("install-plan-approval", cmd.Flags().Lookup("install-plan-approval"))         //nolint:errcheck,gosec
+	viper.BindPFlag("everest-service-type", cmd.Flags().Lookup("everest-service-type"))           //nolint:errcheck,gosec
+	viper.BindPFlag("use-existing-olm", cmd.Flags().Lookup("use-existing-olm"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("set-default-storage-class", cmd.Flags().Lookup("set-default-storage-class")) //nolint:errcheck,gosec
 
 	viper.BindEnv("kubeconfig")                                     //nolint:errcheck,gosec
 	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig")) //nolint:errcheck,gosec
 	viper.BindPFlag("namespaces", cmd.Flags().Lookup("namespaces")) //nolint:errcheck,gosec
 
+	viper.BindPFlag("catalog-image", cmd.Flags().Lookup("catalog-image"))         //nolint:errcheck,gosec
+	viper.BindEnv("disable-telemetry", install.DisableTelemetryEnvVar)            //nolint:errcheck,gosec
+	viper.BindPFlag("disable-telemetry", cmd.Flags().Lookup("disable-telemetry")) //nolint:errcheck,gosec
+	viper.BindPFlag("manifest-file", cmd.Flags().Lookup("manifest-file"))         //nolint:errcheck,gosec
+	viper.BindPFlag("bundle", cmd.Flags().Lookup("bundle"))                       //nolint:errcheck,gosec
+	viper.BindPFlag("registry-mirror", cmd.Flags().Lookup("registry-mirror"))     //nolint:errcheck,gosec
+	viper.BindPFlag("image-pull-secret", cmd.Flags().Lookup("image-pull-secret")) //nolint:errcheck,gosec
+
 	viper.BindPFlag("operator.mongodb", cmd.Flags().Lookup("operator.mongodb"))               //nolint:errcheck,gosec
 	viper.BindPFlag("operator.postgresql", cmd.Flags().Lookup("operator.postgresql"))         //nolint:errcheck,gosec
 	viper.BindPFlag("operator.xtradb-cluster", cmd.Flags().Lookup("operator.xtradb-cluster")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespace-operators", cmd.Flags().Lookup("namespace-operators"))         //nolint:errcheck,gosec
+
+	viper.BindPFlag("channel.everest", cmd.Flags().Lookup("channel.everest"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("channel.mongodb", cmd.Flags().Lookup("channel.mongodb"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("channel.postgresql", cmd.Flags().Lookup("channel.postgresql"))             //nolint:errcheck,gosec
+	viper.BindPFlag("channel.xtradb-cluster", cmd.Flags().Lookup("channel.xtradb-cluster"))     //nolint:errcheck,gosec
+	viper.BindPFlag("channel.victoria-metrics", cmd.Flags().Lookup("channel.victoria-metrics")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("operator-version.everest", cmd.Flags().Lookup("operator-version.everest"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("operator-version.mongodb", cmd.Flags().Lookup("operator-version.mongodb"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("operator-version.postgresql", cmd.Flags().Lookup("operator-version.postgresql"))             //nolint:errcheck,gosec
+	viper.BindPFlag("operator-version.xtradb-cluster", cmd.Flags().Lookup("operator-version.xtradb-cluster"))     //nolint:errcheck,gosec
+	viper.BindPFlag("operator-version.victoria-metrics", cmd.Flags().Lookup("operator-version.victoria-metrics")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace"))         //nolint:errcheck,gosec
+	viper.BindPFlag("monitoring-namespace", cmd.Flags().Lookup("monitoring-namespace")) //nolint:errcheck,gosec
+	viper.BindPFlag("olm-namespace", cmd.Flags().Lookup("olm-namespace"))               //nolint:errcheck,gosec
 }
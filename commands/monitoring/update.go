@@ -0,0 +1,88 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/monitoring"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewUpdateCmd returns a new update command.
+func NewUpdateCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "update NAME",
+		Args:    cobra.ExactArgs(1),
+		Example: "everestctl monitoring update pmm --api-key KEY",
+		Run: func(cmd *cobra.Command, args []string) {
+			initUpdateViperFlags(cmd)
+
+			c := &monitoring.UpdateConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.Name = args[0]
+
+			command, err := monitoring.NewUpdate(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initUpdateFlags(cmd)
+
+	return cmd
+}
+
+// initUpdateFlags defines the flags of the update command. The flags which are not set keep the current values.
+func initUpdateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("url", "", "URL of the PMM instance")
+	cmd.Flags().String("api-key", "", "PMM API key")
+	cmd.Flags().String("username", "", "PMM username used to create an API key")
+	cmd.Flags().String("password", "", "PMM password used to create an API key")
+	cmd.Flags().Bool("skip-tls-verify", false, "Skip the verification of the PMM certificate")
+	cmd.Flags().String("allowed-namespaces", "", "Comma-separated namespaces allowed to use the instance")
+}
+
+func initUpdateViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                                     //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace"))     //nolint:errcheck,gosec
+	viper.BindPFlag("url", cmd.Flags().Lookup("url"))                               //nolint:errcheck,gosec
+	viper.BindPFlag("api-key", cmd.Flags().Lookup("api-key"))                       //nolint:errcheck,gosec
+	viper.BindPFlag("username", cmd.Flags().Lookup("username"))                     //nolint:errcheck,gosec
+	viper.BindPFlag("password", cmd.Flags().Lookup("password"))                     //nolint:errcheck,gosec
+	viper.BindPFlag("skip-tls-verify", cmd.Flags().Lookup("skip-tls-verify"))       //nolint:errcheck,gosec
+	viper.BindPFlag("allowed-namespaces", cmd.Flags().Lookup("allowed-namespaces")) //nolint:errcheck,gosec
+}
@@ -0,0 +1,113 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package namespaces holds commands for namespaces command.
+package namespaces
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/namespaces"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewAddCmd returns a new add command.
+func NewAddCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add",
+		Args:    cobra.NoArgs,
+		Example: "everestctl namespaces add --namespaces dev,staging --operator.mongodb=false",
+		Run: func(cmd *cobra.Command, args []string) {
+			initAddViperFlags(cmd)
+
+			c := &namespaces.AddConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+
+			command, err := namespaces.NewAdd(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			err = command.Run(cmd.Context())
+			// The changes planned before a failure are printed too.
+			if c.DryRun {
+				output.PrintOutput(cmd, l, command.Plan())
+			}
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+		},
+	}
+
+	initAddFlags(cmd)
+
+	return cmd
+}
+
+func initAddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("namespaces", "", "Comma-separated namespaces list Percona Everest shall manage additionally")
+	cmd.Flags().Bool("disable-telemetry", false, "Disable telemetry in the installed operators")
+	cmd.Flags().Bool("dry-run", false, "Print the changes instead of applying them to the cluster")
+	cmd.Flags().String("install-plan-approval", install.InstallPlanApprovalManual,
+		"Install plan approval policy: automatic, manual or prompt")
+	cmd.Flags().StringSlice("registry-mirror", []string{}, "Registry mirror in the source=mirror format, e.g. docker.io=harbor.example.com/dockerhub. "+
+		"Can be repeated. Defaults to the registry mirrors of the installation")
+	cmd.Flags().String("image-pull-secret", "", "Name of the image pull secret attached to the catalog source and the deployments. "+
+		"Defaults to the image pull secret of the installation. The secret is copied from the system namespace")
+
+	cmd.Flags().Bool("operator.mongodb", true, "Install MongoDB operator")
+	cmd.Flags().Bool("operator.postgresql", true, "Install PostgreSQL operator")
+	cmd.Flags().Bool("operator.xtradb-cluster", true, "Install XtraDB Cluster operator")
+	cmd.Flags().String("namespace-operators", "",
+		"Operators installed per namespace, e.g. dev=pg,mongodb;prod=pxc. Namespaces not listed get the operators selected with --operator.*")
+
+	cmd.Flags().String("channel.mongodb", "", "Channel for the MongoDB operator")
+	cmd.Flags().String("channel.postgresql", "", "Channel for the PostgreSQL operator")
+	cmd.Flags().String("channel.xtradb-cluster", "", "Channel for the XtraDB Cluster operator")
+}
+
+func initAddViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                                           //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))                       //nolint:errcheck,gosec
+	viper.BindPFlag("namespaces", cmd.Flags().Lookup("namespaces"))                       //nolint:errcheck,gosec
+	viper.BindEnv("disable-telemetry", install.DisableTelemetryEnvVar)                    //nolint:errcheck,gosec
+	viper.BindPFlag("disable-telemetry", cmd.Flags().Lookup("disable-telemetry"))         //nolint:errcheck,gosec
+	viper.BindPFlag("dry-run", cmd.Flags().Lookup("dry-run"))                             //nolint:errcheck,gosec
+	viper.BindPFlag("install-plan-approval", cmd.Flags().Lookup("install-plan-approval")) //nolint:errcheck,gosec
+	viper.BindPFlag("registry-mirror", cmd.Flags().Lookup("registry-mirror"))             //nolint:errcheck,gosec
+	viper.BindPFlag("image-pull-secret", cmd.Flags().Lookup("image-pull-secret"))         //nolint:errcheck,gosec
+
+	viper.BindPFlag("operator.mongodb", cmd.Flags().Lookup("operator.mongodb"))               //nolint:errcheck,gosec
+	viper.BindPFlag("operator.postgresql", cmd.Flags().Lookup("operator.postgresql"))         //nolint:errcheck,gosec
+	viper.BindPFlag("operator.xtradb-cluster", cmd.Flags().Lookup("operator.xtradb-cluster")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespace-operators", cmd.Flags().Lookup("namespace-operators"))         //nolint:errcheck,gosec
+
+	viper.BindPFlag("channel.mongodb", cmd.Flags().Lookup("channel.mongodb"))               //nolint:errcheck,gosec
+	viper.BindPFlag("channel.postgresql", cmd.Flags().Lookup("channel.postgresql"))         //nolint:errcheck,gosec
+	viper.BindPFlag("channel.xtradb-cluster", cmd.Flags().Lookup("channel.xtradb-cluster")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
+}
@@ -0,0 +1,83 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespaces
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/namespaces"
+	"github.com/percona/percona-everest-cli/pkg/output"
+)
+
+// NewRemoveCmd returns a new remove command.
+func NewRemoveCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove",
+		Args:    cobra.NoArgs,
+		Example: "everestctl namespaces remove --namespaces staging",
+		Run: func(cmd *cobra.Command, args []string) {
+			initRemoveViperFlags(cmd)
+
+			c := &namespaces.RemoveConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+
+			command, err := namespaces.NewRemove(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			err = command.Run(cmd.Context())
+			// The changes planned before a failure are printed too.
+			if c.DryRun {
+				output.PrintOutput(cmd, l, command.Plan())
+			}
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+		},
+	}
+
+	initRemoveFlags(cmd)
+
+	return cmd
+}
+
+func initRemoveFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("namespaces", "", "Comma-separated namespaces list Percona Everest shall stop managing")
+	cmd.Flags().BoolP("assume-yes", "y", false, "Assume yes to all questions")
+	cmd.Flags().Bool("dry-run", false, "Print the changes instead of applying them to the cluster")
+}
+
+func initRemoveViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                     //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig")) //nolint:errcheck,gosec
+	viper.BindPFlag("namespaces", cmd.Flags().Lookup("namespaces")) //nolint:errcheck,gosec
+	viper.BindPFlag("assume-yes", cmd.Flags().Lookup("assume-yes")) //nolint:errcheck,gosec
+	viper.BindPFlag("dry-run", cmd.Flags().Lookup("dry-run"))       //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
+}
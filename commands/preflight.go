@@ -0,0 +1,90 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package commands ...
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/output"
+	"github.com/percona/percona-everest-cli/pkg/preflight"
+)
+
+func newPreflightCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  "preflight",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			initPreflightViperFlags(cmd)
+
+			c := &preflight.Config{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			c.ReservedNamespaces = install.ReservedNamespaces(
+				viper.GetString("system-namespace"),
+				viper.GetString("monitoring-namespace"),
+				c.OLMNamespace,
+			)
+
+			command, err := preflight.NewPreflight(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+			if res.Failed() {
+				os.Exit(1)
+			}
+		},
+	}
+
+	initPreflightFlags(cmd)
+
+	return cmd
+}
+
+func initPreflightFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("monitoring-namespace", install.MonitoringNamespace, "Namespace where the monitoring stack is installed")
+	cmd.Flags().String("olm-namespace", kubernetes.OLMNamespace, "Namespace where OLM is installed unless the existing OLM is used")
+	cmd.Flags().Bool("use-existing-olm", false, "Check the cluster for an installation using the existing OLM")
+}
+
+func initPreflightViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                     //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("use-existing-olm", cmd.Flags().Lookup("use-existing-olm")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace"))         //nolint:errcheck,gosec
+	viper.BindPFlag("monitoring-namespace", cmd.Flags().Lookup("monitoring-namespace")) //nolint:errcheck,gosec
+	viper.BindPFlag("olm-namespace", cmd.Flags().Lookup("olm-namespace"))               //nolint:errcheck,gosec
+}
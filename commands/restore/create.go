@@ -0,0 +1,94 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package restore holds commands for restore command.
+package restore
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/output"
+	"github.com/percona/percona-everest-cli/pkg/restore"
+)
+
+// defaultWaitTimeout is the default time to wait for a restore to complete.
+const defaultWaitTimeout = 30 * time.Minute
+
+// NewCreateCmd returns a new create command.
+func NewCreateCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "create",
+		Args:    cobra.NoArgs,
+		Example: "everestctl restore create --namespace dev --backup mysql-1-backup --pitr-date 2024-03-01T12:00:00Z --wait",
+		Run: func(cmd *cobra.Command, args []string) {
+			initCreateViperFlags(cmd)
+
+			c := &restore.CreateConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+
+			command, err := restore.NewCreate(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			output.PrintOutput(cmd, l, res)
+		},
+	}
+
+	initCreateFlags(cmd)
+
+	return cmd
+}
+
+func initCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("namespace", "", "Namespace of the backup")
+	cmd.Flags().String("backup", "", "Name of the backup to restore")
+	cmd.Flags().String("new-cluster", "", "Restore into a new database cluster with this name. "+
+		"Defaults to the database cluster the backup was taken from")
+	cmd.Flags().String("storage-size", "", "Storage size of the new database cluster, e.g. 15Gi. "+
+		"Defaults to the storage size of the database cluster the backup was taken from")
+	cmd.Flags().String("pitr-type", "", "Point-in-time recovery type: date or latest")
+	cmd.Flags().String("pitr-date", "", "UTC date to recover to, e.g. 2024-03-01T12:00:00Z")
+	cmd.Flags().Bool("wait", false, "Wait until the restore succeeds or fails")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "Time to wait for the restore to complete")
+}
+
+func initCreateViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                         //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))     //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))       //nolint:errcheck,gosec
+	viper.BindPFlag("backup", cmd.Flags().Lookup("backup"))             //nolint:errcheck,gosec
+	viper.BindPFlag("new-cluster", cmd.Flags().Lookup("new-cluster"))   //nolint:errcheck,gosec
+	viper.BindPFlag("storage-size", cmd.Flags().Lookup("storage-size")) //nolint:errcheck,gosec
+	viper.BindPFlag("pitr-type", cmd.Flags().Lookup("pitr-type"))       //nolint:errcheck,gosec
+	viper.BindPFlag("pitr-date", cmd.Flags().Lookup("pitr-date"))       //nolint:errcheck,gosec
+	viper.BindPFlag("wait", cmd.Flags().Lookup("wait"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("wait-timeout", cmd.Flags().Lookup("wait-timeout")) //nolint:errcheck,gosec
+}
@@ -41,6 +41,18 @@ func NewRootCmd(l *zap.SugaredLogger) *cobra.Command {
 	rootCmd.AddCommand(newVersionCmd(l))
 	rootCmd.AddCommand(newUpgradeCmd(l))
 	rootCmd.AddCommand(newUninstallCmd(l))
+	rootCmd.AddCommand(newBundleCmd(l))
+	rootCmd.AddCommand(newOperatorsCmd(l))
+	rootCmd.AddCommand(newNamespacesCmd(l))
+	rootCmd.AddCommand(newPreflightCmd(l))
+	rootCmd.AddCommand(newStatusCmd(l))
+	rootCmd.AddCommand(newSupportBundleCmd(l))
+	rootCmd.AddCommand(newListCmd(l))
+	rootCmd.AddCommand(newDBCmd(l))
+	rootCmd.AddCommand(newBackupCmd(l))
+	rootCmd.AddCommand(newRestoreCmd(l))
+	rootCmd.AddCommand(newBackupStorageCmd(l))
+	rootCmd.AddCommand(newMonitoringCmd(l))
 
 	return rootCmd
 }
@@ -40,7 +40,7 @@ func NewResetCmd(l *zap.SugaredLogger) *cobra.Command {
 				os.Exit(1)
 			}
 
-			c.Namespace = install.SystemNamespace
+			c.Namespace = viper.GetString("system-namespace")
 			command, err := token.NewReset(*c, l)
 			if err != nil {
 				output.PrintError(err, l)
@@ -64,11 +64,14 @@ func NewResetCmd(l *zap.SugaredLogger) *cobra.Command {
 
 func initResetFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
 }
 
 func initResetViperFlags(cmd *cobra.Command) {
 	viper.BindEnv("kubeconfig")                                     //nolint:errcheck,gosec
 	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
 }
 
 func parseResetConfig() (*token.ResetConfig, error) {
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/percona/percona-everest-cli/pkg/install"
 	"github.com/percona/percona-everest-cli/pkg/output"
 	"github.com/percona/percona-everest-cli/pkg/uninstall"
 )
@@ -44,7 +45,12 @@ func newUninstallCmd(l *zap.SugaredLogger) *cobra.Command {
 				os.Exit(1)
 			}
 
-			if err := op.Run(cmd.Context()); err != nil {
+			err = op.Run(cmd.Context())
+			// The changes planned before a failure are printed too.
+			if c.DryRun {
+				output.PrintOutput(cmd, l, op.Plan())
+			}
+			if err != nil {
 				output.PrintError(err, l)
 				os.Exit(1)
 			}
@@ -58,8 +64,10 @@ func newUninstallCmd(l *zap.SugaredLogger) *cobra.Command {
 
 func initUninstallFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
 	cmd.Flags().BoolP("assume-yes", "y", false, "Assume yes to all questions")
 	cmd.Flags().BoolP("force", "f", false, "Force removal in case there are database clusters running")
+	cmd.Flags().Bool("dry-run", false, "Print the changes instead of applying them to the cluster")
 }
 
 func initUninstallViperFlags(cmd *cobra.Command) {
@@ -67,6 +75,9 @@ func initUninstallViperFlags(cmd *cobra.Command) {
 	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig")) //nolint:errcheck,gosec
 	viper.BindPFlag("assume-yes", cmd.Flags().Lookup("assume-yes")) //nolint:errcheck,gosec
 	viper.BindPFlag("force", cmd.Flags().Lookup("force"))           //nolint:errcheck,gosec
+	viper.BindPFlag("dry-run", cmd.Flags().Lookup("dry-run"))       //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
 }
 
 func parseClusterConfig() (*uninstall.Config, error) {
@@ -23,6 +23,8 @@ import (
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	upgradecmd "github.com/percona/percona-everest-cli/commands/upgrade"
+	"github.com/percona/percona-everest-cli/pkg/install"
 	"github.com/percona/percona-everest-cli/pkg/output"
 	"github.com/percona/percona-everest-cli/pkg/upgrade"
 )
@@ -39,6 +41,10 @@ func newUpgradeCmd(l *zap.SugaredLogger) *cobra.Command {
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			initUpgradeViperFlags(cmd)
+			if err := readConfigFile(cmd); err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
 
 			c, err := parseConfig()
 			if err != nil {
@@ -51,7 +57,12 @@ func newUpgradeCmd(l *zap.SugaredLogger) *cobra.Command {
 				os.Exit(1)
 			}
 
-			if err := op.Run(cmd.Context()); err != nil {
+			err = op.Run(cmd.Context())
+			// The changes planned before a failure are printed too.
+			if c.DryRun {
+				output.PrintOutput(cmd, l, op.Plan())
+			}
+			if err != nil {
 				output.PrintError(err, l)
 				os.Exit(1)
 			}
@@ -60,14 +71,39 @@ func newUpgradeCmd(l *zap.SugaredLogger) *cobra.Command {
 
 	initUpgradeFlags(cmd)
 
+	cmd.AddCommand(upgradecmd.NewOperatorsCmd(l))
+	cmd.AddCommand(upgradecmd.NewRollbackCmd(l))
+
 	return cmd
 }
 
 func initUpgradeFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
 	cmd.Flags().String("namespaces", "", "Comma-separated namespaces list Percona Everest can manage")
 	cmd.Flags().Bool("upgrade-olm", false, "Upgrade OLM distribution")
 	cmd.Flags().Bool("skip-wizard", false, "Skip installation wizard")
+	cmd.Flags().String("catalog-image", "", "Everest catalog image. Defaults to the image matching the CLI version")
+	cmd.Flags().Bool("disable-telemetry", false, "Disable telemetry in the upgraded operators")
+	cmd.Flags().String("manifest-file", "", "Path to a local Everest manifest used instead of downloading it")
+	cmd.Flags().String("bundle", "", "Path to an offline installation bundle created by `everestctl bundle create`")
+	cmd.MarkFlagsMutuallyExclusive("manifest-file", "bundle")
+	cmd.Flags().String("to-version", "", "Everest release to upgrade to, e.g. 0.7.0. Defaults to the release matching the CLI version")
+	cmd.MarkFlagsMutuallyExclusive("to-version", "manifest-file")
+	cmd.MarkFlagsMutuallyExclusive("to-version", "bundle")
+	cmd.Flags().StringSlice("registry-mirror", []string{}, "Registry mirror in the source=mirror format, e.g. docker.io=harbor.example.com/dockerhub. Can be repeated")
+	cmd.Flags().String("image-pull-secret", "", "Name of the image pull secret attached to the catalog source and the deployments. "+
+		"The secret is copied from the system namespace into the other namespaces")
+	cmd.Flags().String("namespace-operators", "",
+		"Operators upgraded per namespace, e.g. dev=pg,mongodb;prod=pxc. Defaults to the mapping used by the installation")
+
+	cmd.Flags().String("channel.everest", "", "Channel to switch the Everest operator to")
+	cmd.Flags().String("channel.mongodb", "", "Channel to switch the MongoDB operator to")
+	cmd.Flags().String("channel.postgresql", "", "Channel to switch the PostgreSQL operator to")
+	cmd.Flags().String("channel.xtradb-cluster", "", "Channel to switch the XtraDB Cluster operator to")
+	cmd.Flags().String("channel.victoria-metrics", "", "Channel to switch the VictoriaMetrics operator to")
+	initConfigFileFlag(cmd)
+	cmd.Flags().Bool("dry-run", false, "Print the changes instead of applying them to the cluster")
 }
 
 func initUpgradeViperFlags(cmd *cobra.Command) {
@@ -76,6 +112,26 @@ func initUpgradeViperFlags(cmd *cobra.Command) {
 	viper.BindPFlag("namespaces", cmd.Flags().Lookup("namespaces"))   //nolint:errcheck,gosec
 	viper.BindPFlag("upgrade-olm", cmd.Flags().Lookup("upgrade-olm")) //nolint:errcheck,gosec
 	viper.BindPFlag("skip-wizard", cmd.Flags().Lookup("skip-wizard")) //nolint:errcheck,gosec
+	viper.BindPFlag("dry-run", cmd.Flags().Lookup("dry-run"))         //nolint:errcheck,gosec
+
+	viper.BindPFlag("catalog-image", cmd.Flags().Lookup("catalog-image"))         //nolint:errcheck,gosec
+	viper.BindEnv("disable-telemetry", install.DisableTelemetryEnvVar)            //nolint:errcheck,gosec
+	viper.BindPFlag("disable-telemetry", cmd.Flags().Lookup("disable-telemetry")) //nolint:errcheck,gosec
+	viper.BindPFlag("manifest-file", cmd.Flags().Lookup("manifest-file"))         //nolint:errcheck,gosec
+	viper.BindPFlag("bundle", cmd.Flags().Lookup("bundle"))                       //nolint:errcheck,gosec
+	viper.BindPFlag("registry-mirror", cmd.Flags().Lookup("registry-mirror"))     //nolint:errcheck,gosec
+	viper.BindPFlag("image-pull-secret", cmd.Flags().Lookup("image-pull-secret")) //nolint:errcheck,gosec
+	viper.BindPFlag("to-version", cmd.Flags().Lookup("to-version"))               //nolint:errcheck,gosec
+
+	viper.BindPFlag("namespace-operators", cmd.Flags().Lookup("namespace-operators")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("channel.everest", cmd.Flags().Lookup("channel.everest"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("channel.mongodb", cmd.Flags().Lookup("channel.mongodb"))                   //nolint:errcheck,gosec
+	viper.BindPFlag("channel.postgresql", cmd.Flags().Lookup("channel.postgresql"))             //nolint:errcheck,gosec
+	viper.BindPFlag("channel.xtradb-cluster", cmd.Flags().Lookup("channel.xtradb-cluster"))     //nolint:errcheck,gosec
+	viper.BindPFlag("channel.victoria-metrics", cmd.Flags().Lookup("channel.victoria-metrics")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
 }
 
 func parseConfig() (*upgrade.Config, error) {
@@ -0,0 +1,91 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upgrade holds commands for upgrade command.
+package upgrade
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/output"
+	"github.com/percona/percona-everest-cli/pkg/upgrade"
+)
+
+const defaultWaitTimeout = 10 * time.Minute
+
+// NewOperatorsCmd returns a new operators command.
+func NewOperatorsCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "operators [OPERATOR]",
+		Args:    cobra.MaximumNArgs(1),
+		Example: "everestctl upgrade operators percona-xtradb-cluster-operator --namespace dev",
+		Run: func(cmd *cobra.Command, args []string) {
+			initOperatorsViperFlags(cmd)
+
+			c := &upgrade.OperatorsConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+			if len(args) == 1 {
+				c.Operator = args[0]
+			}
+
+			command, err := upgrade.NewOperators(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			res, err := command.Run(cmd.Context())
+			if res != nil {
+				output.PrintOutput(cmd, l, res)
+			}
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+		},
+	}
+
+	initOperatorsFlags(cmd)
+
+	return cmd
+}
+
+func initOperatorsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().String("namespace", "", "Upgrade the operators in this namespace only. Defaults to all the namespaces managed by Everest")
+	cmd.Flags().Bool("list", false, "List the pending upgrades without upgrading the operators")
+	cmd.Flags().BoolP("assume-yes", "y", false, "Assume yes to all questions")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "How long to wait for an upgraded operator to become ready")
+}
+
+func initOperatorsViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                         //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))     //nolint:errcheck,gosec
+	viper.BindPFlag("namespace", cmd.Flags().Lookup("namespace"))       //nolint:errcheck,gosec
+	viper.BindPFlag("list", cmd.Flags().Lookup("list"))                 //nolint:errcheck,gosec
+	viper.BindPFlag("assume-yes", cmd.Flags().Lookup("assume-yes"))     //nolint:errcheck,gosec
+	viper.BindPFlag("wait-timeout", cmd.Flags().Lookup("wait-timeout")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
+}
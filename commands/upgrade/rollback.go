@@ -0,0 +1,78 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/output"
+	"github.com/percona/percona-everest-cli/pkg/upgrade"
+)
+
+// NewRollbackCmd returns a new rollback command.
+func NewRollbackCmd(l *zap.SugaredLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rollback",
+		Args:    cobra.NoArgs,
+		Example: "everestctl upgrade rollback",
+		Run: func(cmd *cobra.Command, args []string) {
+			initRollbackViperFlags(cmd)
+
+			c := &upgrade.RollbackConfig{}
+			if err := viper.Unmarshal(c); err != nil {
+				os.Exit(1)
+			}
+
+			command, err := upgrade.NewRollback(*c, l)
+			if err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+
+			if err := command.Run(cmd.Context()); err != nil {
+				output.PrintError(err, l)
+				os.Exit(1)
+			}
+		},
+	}
+
+	initRollbackFlags(cmd)
+
+	return cmd
+}
+
+func initRollbackFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("kubeconfig", "k", "~/.kube/config", "Path to a kubeconfig")
+	cmd.Flags().String("system-namespace", install.SystemNamespace, "Namespace where Everest is installed")
+	cmd.Flags().BoolP("assume-yes", "y", false, "Assume yes to all questions")
+	cmd.Flags().Bool("force", false, "Roll back the upgrade even if it has completed")
+	cmd.Flags().Duration("wait-timeout", defaultWaitTimeout, "How long to wait for the previous deployments to become ready")
+}
+
+func initRollbackViperFlags(cmd *cobra.Command) {
+	viper.BindEnv("kubeconfig")                                         //nolint:errcheck,gosec
+	viper.BindPFlag("kubeconfig", cmd.Flags().Lookup("kubeconfig"))     //nolint:errcheck,gosec
+	viper.BindPFlag("assume-yes", cmd.Flags().Lookup("assume-yes"))     //nolint:errcheck,gosec
+	viper.BindPFlag("force", cmd.Flags().Lookup("force"))               //nolint:errcheck,gosec
+	viper.BindPFlag("wait-timeout", cmd.Flags().Lookup("wait-timeout")) //nolint:errcheck,gosec
+
+	viper.BindPFlag("system-namespace", cmd.Flags().Lookup("system-namespace")) //nolint:errcheck,gosec
+}
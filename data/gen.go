@@ -25,3 +25,8 @@ var OLMCRDs embed.FS
 
 // OLMVersion indicates the OLM version shipped with the CLI.
 const OLMVersion = "0.25.0"
+
+// Compatibility stores the matrix of the Everest releases and their upgrade paths.
+//
+//go:embed compatibility.yaml
+var Compatibility []byte
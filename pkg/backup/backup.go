@@ -0,0 +1,98 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup holds the logic of the backup commands managing database cluster backups.
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type (
+	// Backup describes a backup of a database cluster.
+	Backup struct {
+		Name          string     `json:"name"`
+		Namespace     string     `json:"namespace"`
+		Cluster       string     `json:"cluster"`
+		BackupStorage string     `json:"backupStorage"`
+		State         string     `json:"state"`
+		Created       *time.Time `json:"created,omitempty"`
+		Completed     *time.Time `json:"completed,omitempty"`
+		Destination   string     `json:"destination,omitempty"`
+	}
+
+	// BackupResponse is a response from the backup commands describing a single backup.
+	BackupResponse struct {
+		Backup Backup `json:"backup"`
+	}
+)
+
+func (r BackupResponse) String() string {
+	bk := r.Backup
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", bk.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", bk.Namespace)
+	fmt.Fprintf(w, "Cluster:\t%s\n", bk.Cluster)
+	fmt.Fprintf(w, "Backup storage:\t%s\n", bk.BackupStorage)
+	fmt.Fprintf(w, "State:\t%s\n", bk.State)
+	if bk.Created != nil {
+		fmt.Fprintf(w, "Created:\t%s\n", bk.Created.Format(time.RFC3339))
+	}
+	if bk.Completed != nil {
+		fmt.Fprintf(w, "Completed:\t%s\n", bk.Completed.Format(time.RFC3339))
+	}
+	if bk.Destination != "" {
+		fmt.Fprintf(w, "Destination:\t%s\n", bk.Destination)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// ValidateName validates the name of a backup.
+func ValidateName(name string) error {
+	if errs := validation.IsDNS1035Label(name); len(errs) != 0 {
+		return fmt.Errorf("invalid backup name %q: %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// newBackup returns the description of the backup.
+func newBackup(backup *everestv1alpha1.DatabaseClusterBackup) Backup {
+	b := Backup{
+		Name:          backup.Name,
+		Namespace:     backup.Namespace,
+		Cluster:       backup.Spec.DBClusterName,
+		BackupStorage: backup.Spec.BackupStorageName,
+		State:         string(backup.Status.State),
+	}
+	if backup.Status.CreatedAt != nil {
+		b.Created = &backup.Status.CreatedAt.Time
+	}
+	if backup.Status.CompletedAt != nil {
+		b.Completed = &backup.Status.CompletedAt.Time
+	}
+	if backup.Status.Destination != nil {
+		b.Destination = *backup.Status.Destination
+	}
+	return b
+}
@@ -0,0 +1,35 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := CreateConfig{Name: "mysql-1-backup", Namespace: "dev", Cluster: "mysql-1", BackupStorage: "s3"}
+	require.NoError(t, valid.validate())
+
+	type tcase struct {
+		name   string
+		modify func(c *CreateConfig)
+	}
+	tcases := []tcase{
+		{name: "invalid name", modify: func(c *CreateConfig) { c.Name = "Backup_1" }},
+		{name: "no namespace", modify: func(c *CreateConfig) { c.Namespace = "" }},
+		{name: "no cluster", modify: func(c *CreateConfig) { c.Cluster = "" }},
+		{name: "no backup storage", modify: func(c *CreateConfig) { c.BackupStorage = "" }},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := valid
+			tc.modify(&c)
+			assert.Error(t, c.validate())
+		})
+	}
+}
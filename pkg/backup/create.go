@@ -0,0 +1,136 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Create implements the main logic for the backup create command.
+type Create struct {
+	config CreateConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// CreateConfig stores configuration for the backup create command.
+type CreateConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// Name is the name of the backup.
+	Name string `mapstructure:"-"`
+	// Namespace is the namespace of the database cluster.
+	Namespace string `mapstructure:"namespace"`
+	// Cluster is the name of the database cluster to back up.
+	Cluster string `mapstructure:"cluster"`
+	// BackupStorage is the name of the backup storage the backup is stored in.
+	BackupStorage string `mapstructure:"backup-storage"`
+}
+
+// NewCreate returns a new Create struct.
+func NewCreate(c CreateConfig, l *zap.SugaredLogger) (*Create, error) {
+	cli := &Create{
+		config: c,
+		l:      l.With("component", "backup/create"),
+	}
+	if err := cli.config.validate(); err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// validate validates the config without contacting the cluster.
+func (c CreateConfig) validate() error {
+	if err := ValidateName(c.Name); err != nil {
+		return err
+	}
+	if c.Namespace == "" {
+		return errors.New("namespace is required")
+	}
+	if c.Cluster == "" {
+		return errors.New("cluster is required")
+	}
+	if c.BackupStorage == "" {
+		return errors.New("backup storage is required")
+	}
+	return nil
+}
+
+// Run runs the backup create command.
+func (c *Create) Run(ctx context.Context) (*BackupResponse, error) {
+	db, err := c.kubeClient.GetDatabaseCluster(ctx, c.config.Namespace, c.config.Cluster)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s database cluster", c.config.Cluster))
+	}
+	storage, err := c.kubeClient.GetBackupStorage(ctx, c.config.SystemNamespace, c.config.BackupStorage)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s backup storage", c.config.BackupStorage))
+	}
+	if !storage.IsNamespaceAllowed(c.config.Namespace) {
+		return nil, fmt.Errorf("backup storage %s is not allowed in %s namespace", c.config.BackupStorage, c.config.Namespace)
+	}
+
+	backup := &everestv1alpha1.DatabaseClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        c.config.Name,
+			Namespace:   c.config.Namespace,
+			Annotations: sourceAnnotations(db),
+		},
+		Spec: everestv1alpha1.DatabaseClusterBackupSpec{
+			DBClusterName:     c.config.Cluster,
+			BackupStorageName: c.config.BackupStorage,
+		},
+	}
+	c.l.Infof("Creating backup %s of %s database cluster", c.config.Name, c.config.Cluster)
+	err = c.kubeClient.CreateDatabaseClusterBackup(backup)
+	if k8serrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("backup %s already exists in %s namespace", c.config.Name, c.config.Namespace)
+	}
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not create %s backup", c.config.Name))
+	}
+	return &BackupResponse{Backup: newBackup(backup)}, nil
+}
+
+// sourceAnnotations returns the annotations recording the engine and storage of the database
+// cluster the backup is taken from. They allow to restore the backup into a new cluster
+// after the source cluster is deleted.
+func sourceAnnotations(db *everestv1alpha1.DatabaseCluster) map[string]string {
+	return map[string]string{
+		kubernetes.EngineTypeAnnotationKey:    string(db.Spec.Engine.Type),
+		kubernetes.EngineVersionAnnotationKey: db.Spec.Engine.Version,
+		kubernetes.StorageSizeAnnotationKey:   db.Spec.Engine.Storage.Size.String(),
+	}
+}
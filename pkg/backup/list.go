@@ -0,0 +1,124 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// List implements the main logic for the backup list command.
+type List struct {
+	config ListConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// ListConfig stores configuration for the backup list command.
+	ListConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// Namespace limits the output to a single namespace.
+		// All the namespaces managed by Everest are listed if empty.
+		Namespace string `mapstructure:"namespace"`
+		// Cluster limits the output to the backups of a single database cluster.
+		Cluster string `mapstructure:"cluster"`
+	}
+
+	// ListResponse is a response from the backup list command.
+	ListResponse struct {
+		Backups []Backup `json:"backups"`
+	}
+)
+
+func (r ListResponse) String() string {
+	if len(r.Backups) == 0 {
+		return "There are no backups"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tCLUSTER\tBACKUP STORAGE\tSTATE\tCOMPLETED")
+	for _, bk := range r.Backups {
+		completed := ""
+		if bk.Completed != nil {
+			completed = bk.Completed.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", bk.Namespace, bk.Name, bk.Cluster, bk.BackupStorage, bk.State, completed)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewList returns a new List struct.
+func NewList(c ListConfig, l *zap.SugaredLogger) (*List, error) {
+	cli := &List{
+		config: c,
+		l:      l.With("component", "backup/list"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the backup list command.
+func (l *List) Run(ctx context.Context) (*ListResponse, error) {
+	namespaces := []string{l.config.Namespace}
+	if l.config.Namespace == "" {
+		ns, err := l.kubeClient.GetDBNamespaces(ctx, l.config.SystemNamespace)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+		}
+		sort.Strings(ns)
+		namespaces = ns
+	}
+
+	res := &ListResponse{Backups: []Backup{}}
+	for _, ns := range namespaces {
+		backups, err := l.kubeClient.ListDatabaseClusterBackups(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not list backups in %s namespace", ns))
+		}
+		sort.Slice(backups.Items, func(i, j int) bool { return backups.Items[i].Name < backups.Items[j].Name })
+		for i := range backups.Items {
+			if l.config.Cluster != "" && backups.Items[i].Spec.DBClusterName != l.config.Cluster {
+				continue
+			}
+			res.Backups = append(res.Backups, newBackup(&backups.Items[i]))
+		}
+	}
+	return res, nil
+}
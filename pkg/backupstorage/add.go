@@ -0,0 +1,182 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Add implements the main logic for the backup-storage add command.
+type Add struct {
+	config AddConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// AddConfig stores configuration for the backup-storage add command.
+type AddConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// Name is the name of the backup storage.
+	Name string `mapstructure:"-"`
+
+	// Type is the type of the storage, s3 or azure.
+	Type string `mapstructure:"type"`
+	// Bucket is the name of the S3 bucket or the Azure container.
+	Bucket string `mapstructure:"bucket"`
+	// Region is the region of the bucket. It is required for S3.
+	Region string `mapstructure:"region"`
+	// EndpointURL is the endpoint of the storage. The default endpoint
+	// of the storage type is used if empty.
+	EndpointURL string `mapstructure:"endpoint-url"`
+	// Description is a free-form description of the storage.
+	Description string `mapstructure:"description"`
+
+	// AccessKey is the access key ID for S3 and the account name for Azure.
+	AccessKey string `mapstructure:"access-key"`
+	// SecretKey is the secret access key for S3 and the account key for Azure.
+	SecretKey string `mapstructure:"secret-key"`
+	// SecretKeyStdin reads the secret key from the standard input.
+	SecretKeyStdin bool `mapstructure:"secret-key-stdin"`
+
+	// AllowedNamespaces is a comma-separated list of the namespaces allowed to use the storage.
+	// All the namespaces managed by Everest are allowed if empty.
+	AllowedNamespaces string `mapstructure:"allowed-namespaces"`
+}
+
+// NewAdd returns a new Add struct.
+func NewAdd(c AddConfig, l *zap.SugaredLogger) (*Add, error) {
+	cli := &Add{
+		config: c,
+		l:      l.With("component", "backup-storage/add"),
+	}
+	if c.SecretKeyStdin {
+		key, err := readSecretKey(os.Stdin, c.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+		cli.config.SecretKey = key
+	}
+	if err := cli.config.validate(); err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// validate validates the config without contacting the cluster.
+func (c AddConfig) validate() error {
+	if errs := validation.IsDNS1035Label(c.Name); len(errs) != 0 {
+		return fmt.Errorf("invalid backup storage name %q: %s", c.Name, strings.Join(errs, ", "))
+	}
+	switch everestv1alpha1.BackupStorageType(c.Type) {
+	case everestv1alpha1.BackupStorageTypeS3:
+		if c.Region == "" {
+			return errors.New("region is required for s3 backup storage")
+		}
+	case everestv1alpha1.BackupStorageTypeAzure:
+	default:
+		return fmt.Errorf("invalid backup storage type %q. Allowed values are %s and %s",
+			c.Type, everestv1alpha1.BackupStorageTypeS3, everestv1alpha1.BackupStorageTypeAzure)
+	}
+	if c.Bucket == "" {
+		return errors.New("bucket is required")
+	}
+	if c.AccessKey == "" || c.SecretKey == "" {
+		return fmt.Errorf("access key and secret key are required. Set them with the flags or the %s and %s environment variables",
+			AccessKeyEnvVar, SecretKeyEnvVar)
+	}
+	return nil
+}
+
+// Run runs the backup-storage add command.
+func (a *Add) Run(ctx context.Context) (*StorageResponse, error) {
+	_, err := a.kubeClient.GetBackupStorage(ctx, a.config.SystemNamespace, a.config.Name)
+	if err == nil {
+		return nil, fmt.Errorf("backup storage %s already exists", a.config.Name)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s backup storage", a.config.Name))
+	}
+
+	namespaces, err := install.AllowedNamespaces(ctx, a.kubeClient, a.config.SystemNamespace, a.config.AllowedNamespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := &everestv1alpha1.BackupStorage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      a.config.Name,
+			Namespace: a.config.SystemNamespace,
+		},
+		Spec: everestv1alpha1.BackupStorageSpec{
+			Type:                  everestv1alpha1.BackupStorageType(a.config.Type),
+			Bucket:                a.config.Bucket,
+			Region:                a.config.Region,
+			EndpointURL:           a.config.EndpointURL,
+			Description:           a.config.Description,
+			CredentialsSecretName: a.config.Name,
+			AllowedNamespaces:     namespaces,
+		},
+	}
+
+	a.l.Infof("Checking access to %s bucket", a.config.Bucket)
+	if err := checkBucket(ctx, storage.Spec, a.config.AccessKey, a.config.SecretKey); err != nil {
+		return nil, err
+	}
+
+	a.l.Infof("Creating %s backup storage", a.config.Name)
+	secret := newSecret(a.config.SystemNamespace, a.config.Name, storage.Spec.Type, a.config.AccessKey, a.config.SecretKey)
+	err = a.kubeClient.CreateSecret(secret)
+	if apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("secret %s already exists in %s namespace. Delete it or choose another name for the backup storage",
+			a.config.Name, a.config.SystemNamespace)
+	}
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not create the credentials secret"))
+	}
+	if err := a.kubeClient.CreateBackupStorage(ctx, storage); err != nil {
+		err = errors.Join(err, fmt.Errorf("could not create %s backup storage", a.config.Name))
+		if dErr := a.kubeClient.DeleteSecret(a.config.SystemNamespace, a.config.Name); dErr != nil {
+			err = errors.Join(err, dErr, errors.New("could not delete the credentials secret"))
+		}
+		return nil, err
+	}
+
+	return &StorageResponse{Storage: newStorage(storage)}, nil
+}
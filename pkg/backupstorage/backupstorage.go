@@ -0,0 +1,151 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backupstorage holds the logic of the backup-storage commands.
+package backupstorage
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The environment variables holding the credentials of the backup storage.
+// They allow to keep the credentials out of the shell history and the process list.
+const (
+	// AccessKeyEnvVar is the name of the environment variable holding the access key.
+	AccessKeyEnvVar = "BACKUP_STORAGE_ACCESS_KEY"
+	// SecretKeyEnvVar is the name of the environment variable holding the secret key.
+	SecretKeyEnvVar = "BACKUP_STORAGE_SECRET_KEY" //nolint:gosec
+)
+
+// The keys of the credentials in the secret of a backup storage expected by the operators.
+const (
+	s3AccessKeyID       = "AWS_ACCESS_KEY_ID"
+	s3SecretAccessKey   = "AWS_SECRET_ACCESS_KEY" //nolint:gosec
+	azureStorageAccount = "AZURE_STORAGE_ACCOUNT_NAME"
+	azureStorageKey     = "AZURE_STORAGE_ACCOUNT_KEY" //nolint:gosec
+)
+
+type (
+	// Storage describes a backup storage.
+	Storage struct {
+		Name              string   `json:"name"`
+		Type              string   `json:"type"`
+		Bucket            string   `json:"bucket"`
+		Region            string   `json:"region,omitempty"`
+		EndpointURL       string   `json:"endpointURL,omitempty"`
+		Description       string   `json:"description,omitempty"`
+		AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	}
+
+	// StorageResponse is a response from the backup-storage commands describing a single storage.
+	StorageResponse struct {
+		Storage Storage `json:"storage"`
+	}
+)
+
+func (r StorageResponse) String() string {
+	s := r.Storage
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", s.Name)
+	fmt.Fprintf(w, "Type:\t%s\n", s.Type)
+	fmt.Fprintf(w, "Bucket:\t%s\n", s.Bucket)
+	if s.Region != "" {
+		fmt.Fprintf(w, "Region:\t%s\n", s.Region)
+	}
+	if s.EndpointURL != "" {
+		fmt.Fprintf(w, "Endpoint:\t%s\n", s.EndpointURL)
+	}
+	if s.Description != "" {
+		fmt.Fprintf(w, "Description:\t%s\n", s.Description)
+	}
+	allowed := "all"
+	if len(s.AllowedNamespaces) != 0 {
+		allowed = strings.Join(s.AllowedNamespaces, ", ")
+	}
+	fmt.Fprintf(w, "Allowed namespaces:\t%s\n", allowed)
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// newStorage returns the description of the backup storage.
+func newStorage(storage *everestv1alpha1.BackupStorage) Storage {
+	return Storage{
+		Name:              storage.Name,
+		Type:              string(storage.Spec.Type),
+		Bucket:            storage.Spec.Bucket,
+		Region:            storage.Spec.Region,
+		EndpointURL:       storage.Spec.EndpointURL,
+		Description:       storage.Spec.Description,
+		AllowedNamespaces: storage.Spec.AllowedNamespaces,
+	}
+}
+
+// credentialKeys returns the keys of the access key and the secret key
+// in the secret of the storage type.
+func credentialKeys(storageType everestv1alpha1.BackupStorageType) (string, string) {
+	if storageType == everestv1alpha1.BackupStorageTypeAzure {
+		return azureStorageAccount, azureStorageKey
+	}
+	return s3AccessKeyID, s3SecretAccessKey
+}
+
+// readSecretKey reads the secret key from the first line of the reader.
+// The secret key must not be set otherwise.
+func readSecretKey(r io.Reader, secretKey string) (string, error) {
+	if secretKey != "" {
+		return "", errors.New("secret key is set already. Do not combine --secret-key-stdin with --secret-key or " + SecretKeyEnvVar)
+	}
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", errors.Join(err, errors.New("could not read the secret key from the standard input"))
+	}
+	key := strings.TrimSpace(line)
+	if key == "" {
+		return "", errors.New("secret key read from the standard input is empty")
+	}
+	return key, nil
+}
+
+// newSecret returns the secret with the credentials of the backup storage.
+// The operator expects the secret to be named after the storage.
+func newSecret(namespace, name string, storageType everestv1alpha1.BackupStorageType, accessKey, secretKey string) *corev1.Secret {
+	accessKeyName, secretKeyName := credentialKeys(storageType)
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			accessKeyName: []byte(accessKey),
+			secretKeyName: []byte(secretKey),
+		},
+	}
+}
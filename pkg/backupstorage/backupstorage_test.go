@@ -0,0 +1,113 @@
+package backupstorage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := AddConfig{Name: "s3", Type: "s3", Bucket: "backups", Region: "us-east-1", AccessKey: "key", SecretKey: "secret"}
+	require.NoError(t, valid.validate())
+
+	type tcase struct {
+		name   string
+		modify func(c *AddConfig)
+	}
+	tcases := []tcase{
+		{name: "invalid name", modify: func(c *AddConfig) { c.Name = "S3_storage" }},
+		{name: "invalid type", modify: func(c *AddConfig) { c.Type = "gcs" }},
+		{name: "no region", modify: func(c *AddConfig) { c.Region = "" }},
+		{name: "no bucket", modify: func(c *AddConfig) { c.Bucket = "" }},
+		{name: "no secret key", modify: func(c *AddConfig) { c.SecretKey = "" }},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := valid
+			tc.modify(&c)
+			assert.Error(t, c.validate())
+		})
+	}
+
+	azure := AddConfig{Name: "azure", Type: "azure", Bucket: "backups", AccessKey: "account", SecretKey: "key"}
+	assert.NoError(t, azure.validate())
+}
+
+func TestNewSecret(t *testing.T) {
+	t.Parallel()
+
+	s := newSecret("everest-system", "s3", everestv1alpha1.BackupStorageTypeS3, "key", "secret")
+	assert.Equal(t, "s3", s.Name)
+	assert.Equal(t, "key", string(s.Data["AWS_ACCESS_KEY_ID"]))
+	assert.Equal(t, "secret", string(s.Data["AWS_SECRET_ACCESS_KEY"]))
+
+	s = newSecret("everest-system", "azure", everestv1alpha1.BackupStorageTypeAzure, "account", "key")
+	assert.Equal(t, "account", string(s.Data["AZURE_STORAGE_ACCOUNT_NAME"]))
+	assert.Equal(t, "key", string(s.Data["AZURE_STORAGE_ACCOUNT_KEY"]))
+}
+
+func TestCheckS3Bucket(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && r.URL.Path == "/backups" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	spec := everestv1alpha1.BackupStorageSpec{
+		Type:        everestv1alpha1.BackupStorageTypeS3,
+		Bucket:      "backups",
+		Region:      "us-east-1",
+		EndpointURL: srv.URL,
+	}
+	require.NoError(t, checkBucket(context.Background(), spec, "key", "secret"))
+
+	spec.Bucket = "missing"
+	require.Error(t, checkBucket(context.Background(), spec, "key", "secret"))
+}
+
+func TestReadSecretKey(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name      string
+		input     string
+		secretKey string
+		want      string
+		wantErr   bool
+	}
+	tcases := []tcase{
+		{name: "line", input: "secret\n", want: "secret"},
+		{name: "no newline", input: "secret", want: "secret"},
+		{name: "first line", input: "secret\nother\n", want: "secret"},
+		{name: "empty", input: "\n", wantErr: true},
+		{name: "set already", input: "secret\n", secretKey: "flag", wantErr: true},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			key, err := readSecretKey(strings.NewReader(tc.input), tc.secretKey)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, key)
+		})
+	}
+}
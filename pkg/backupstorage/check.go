@@ -0,0 +1,86 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+)
+
+// azureEndpointTmpl is the default endpoint of the Azure blob storage of an account.
+const azureEndpointTmpl = "https://%s.blob.core.windows.net/"
+
+// checkBucket checks the bucket of the storage is accessible with the credentials.
+func checkBucket(ctx context.Context, spec everestv1alpha1.BackupStorageSpec, accessKey, secretKey string) error {
+	var err error
+	switch spec.Type {
+	case everestv1alpha1.BackupStorageTypeS3:
+		err = checkS3Bucket(ctx, spec, accessKey, secretKey)
+	case everestv1alpha1.BackupStorageTypeAzure:
+		err = checkAzureContainer(ctx, spec, accessKey, secretKey)
+	default:
+		err = fmt.Errorf("unsupported backup storage type %q", spec.Type)
+	}
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not access %s bucket", spec.Bucket))
+	}
+	return nil
+}
+
+func checkS3Bucket(ctx context.Context, spec everestv1alpha1.BackupStorageSpec, accessKey, secretKey string) error {
+	cfg := &aws.Config{
+		Region:      aws.String(spec.Region),
+		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, ""),
+	}
+	if spec.EndpointURL != "" {
+		// S3 compatible storages usually do not support virtual hosted buckets.
+		cfg.Endpoint = aws.String(spec.EndpointURL)
+		cfg.S3ForcePathStyle = aws.Bool(true)
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = s3.New(sess).HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(spec.Bucket)})
+	return err
+}
+
+func checkAzureContainer(ctx context.Context, spec everestv1alpha1.BackupStorageSpec, account, key string) error {
+	cred, err := azblob.NewSharedKeyCredential(account, key)
+	if err != nil {
+		return err
+	}
+	endpoint := spec.EndpointURL
+	if endpoint == "" {
+		endpoint = fmt.Sprintf(azureEndpointTmpl, account)
+	}
+	client, err := azblob.NewClientWithSharedKeyCredential(endpoint, cred, nil)
+	if err != nil {
+		return err
+	}
+	maxResults := int32(1)
+	pager := client.NewListBlobsFlatPager(spec.Bucket, &azblob.ListBlobsFlatOptions{MaxResults: &maxResults})
+	_, err = pager.NextPage(ctx)
+	return err
+}
@@ -0,0 +1,102 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Delete implements the main logic for the backup-storage delete command.
+type Delete struct {
+	config DeleteConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// DeleteConfig stores configuration for the backup-storage delete command.
+type DeleteConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// Name is the name of the backup storage.
+	Name string `mapstructure:"-"`
+	// AssumeYes is true when all questions can be skipped.
+	AssumeYes bool `mapstructure:"assume-yes"`
+}
+
+// NewDelete returns a new Delete struct.
+func NewDelete(c DeleteConfig, l *zap.SugaredLogger) (*Delete, error) {
+	cli := &Delete{
+		config: c,
+		l:      l.With("component", "backup-storage/delete"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the backup-storage delete command.
+func (d *Delete) Run(ctx context.Context) error {
+	storage, err := d.kubeClient.GetBackupStorage(ctx, d.config.SystemNamespace, d.config.Name)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not get %s backup storage", d.config.Name))
+	}
+	used, err := d.kubeClient.IsBackupStorageUsed(ctx, d.config.SystemNamespace, d.config.Name)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not check if %s backup storage is used", d.config.Name))
+	}
+	if used {
+		return fmt.Errorf("backup storage %s is used by database clusters, backups or restores. "+
+			"Delete them before deleting the backup storage", d.config.Name)
+	}
+
+	if !d.config.AssumeYes {
+		confirm := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to delete %s backup storage?", d.config.Name),
+		}
+		prompt := false
+		if err := survey.AskOne(confirm, &prompt); err != nil {
+			return err
+		}
+		if !prompt {
+			d.l.Info("Exiting")
+			return nil
+		}
+	}
+
+	if err := d.kubeClient.DeleteBackupStorage(ctx, d.config.SystemNamespace, d.config.Name); err != nil {
+		return errors.Join(err, fmt.Errorf("could not delete %s backup storage", d.config.Name))
+	}
+	if err := d.kubeClient.DeleteSecret(d.config.SystemNamespace, storage.Spec.CredentialsSecretName); err != nil {
+		return errors.Join(err, fmt.Errorf("could not delete the credentials secret of %s backup storage", d.config.Name))
+	}
+	d.l.Infof("Backup storage %s has been deleted", d.config.Name)
+	return nil
+}
@@ -0,0 +1,103 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupstorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// List implements the main logic for the backup-storage list command.
+type List struct {
+	config ListConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// ListConfig stores configuration for the backup-storage list command.
+	ListConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+	}
+
+	// ListResponse is a response from the backup-storage list command.
+	ListResponse struct {
+		Storages []Storage `json:"storages"`
+	}
+)
+
+func (r ListResponse) String() string {
+	if len(r.Storages) == 0 {
+		return "There are no backup storages"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAME\tTYPE\tBUCKET\tREGION\tENDPOINT\tALLOWED NAMESPACES")
+	for _, s := range r.Storages {
+		allowed := "all"
+		if len(s.AllowedNamespaces) != 0 {
+			allowed = strings.Join(s.AllowedNamespaces, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", s.Name, s.Type, s.Bucket, s.Region, s.EndpointURL, allowed)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewList returns a new List struct.
+func NewList(c ListConfig, l *zap.SugaredLogger) (*List, error) {
+	cli := &List{
+		config: c,
+		l:      l.With("component", "backup-storage/list"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the backup-storage list command.
+func (l *List) Run(ctx context.Context) (*ListResponse, error) {
+	storages, err := l.kubeClient.ListBackupStorages(ctx, l.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not list backup storages"))
+	}
+	sort.Slice(storages.Items, func(i, j int) bool { return storages.Items[i].Name < storages.Items[j].Name })
+
+	res := &ListResponse{Storages: make([]Storage, 0, len(storages.Items))}
+	for i := range storages.Items {
+		res.Storages = append(res.Storages, newStorage(&storages.Items[i]))
+	}
+	return res, nil
+}
@@ -0,0 +1,155 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Update implements the main logic for the backup-storage update command.
+type Update struct {
+	config UpdateConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// UpdateConfig stores configuration for the backup-storage update command.
+// The empty fields keep the current values.
+type UpdateConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// Name is the name of the backup storage.
+	Name string `mapstructure:"-"`
+
+	// Bucket is the name of the S3 bucket or the Azure container.
+	Bucket string `mapstructure:"bucket"`
+	// Region is the region of the bucket.
+	Region string `mapstructure:"region"`
+	// EndpointURL is the endpoint of the storage.
+	EndpointURL string `mapstructure:"endpoint-url"`
+	// Description is a free-form description of the storage.
+	Description string `mapstructure:"description"`
+
+	// AccessKey is the access key ID for S3 and the account name for Azure.
+	AccessKey string `mapstructure:"access-key"`
+	// SecretKey is the secret access key for S3 and the account key for Azure.
+	SecretKey string `mapstructure:"secret-key"`
+	// SecretKeyStdin reads the secret key from the standard input.
+	SecretKeyStdin bool `mapstructure:"secret-key-stdin"`
+
+	// AllowedNamespaces is a comma-separated list of the namespaces allowed to use the storage.
+	AllowedNamespaces string `mapstructure:"allowed-namespaces"`
+}
+
+// NewUpdate returns a new Update struct.
+func NewUpdate(c UpdateConfig, l *zap.SugaredLogger) (*Update, error) {
+	cli := &Update{
+		config: c,
+		l:      l.With("component", "backup-storage/update"),
+	}
+	if c.SecretKeyStdin {
+		key, err := readSecretKey(os.Stdin, c.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+		cli.config.SecretKey = key
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the backup-storage update command.
+func (u *Update) Run(ctx context.Context) (*StorageResponse, error) {
+	storage, err := u.kubeClient.GetBackupStorage(ctx, u.config.SystemNamespace, u.config.Name)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s backup storage", u.config.Name))
+	}
+	secret, err := u.kubeClient.GetSecret(ctx, storage.Spec.CredentialsSecretName, u.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get the credentials secret of %s backup storage", u.config.Name))
+	}
+
+	if u.config.AllowedNamespaces != "" {
+		namespaces, err := install.AllowedNamespaces(ctx, u.kubeClient, u.config.SystemNamespace, u.config.AllowedNamespaces)
+		if err != nil {
+			return nil, err
+		}
+		storage.Spec.AllowedNamespaces = namespaces
+	}
+	u.applySpec(&storage.Spec)
+
+	accessKeyName, secretKeyName := credentialKeys(storage.Spec.Type)
+	accessKey, secretKey := string(secret.Data[accessKeyName]), string(secret.Data[secretKeyName])
+	credentialsChanged := u.config.AccessKey != "" || u.config.SecretKey != ""
+	if u.config.AccessKey != "" {
+		accessKey = u.config.AccessKey
+	}
+	if u.config.SecretKey != "" {
+		secretKey = u.config.SecretKey
+	}
+
+	u.l.Infof("Checking access to %s bucket", storage.Spec.Bucket)
+	if err := checkBucket(ctx, storage.Spec, accessKey, secretKey); err != nil {
+		return nil, err
+	}
+
+	u.l.Infof("Updating %s backup storage", u.config.Name)
+	if credentialsChanged {
+		secret := newSecret(u.config.SystemNamespace, storage.Spec.CredentialsSecretName, storage.Spec.Type, accessKey, secretKey)
+		if err := u.kubeClient.SetSecret(secret); err != nil {
+			return nil, errors.Join(err, errors.New("could not update the credentials secret"))
+		}
+	}
+	if err := u.kubeClient.UpdateBackupStorage(ctx, storage); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not update %s backup storage", u.config.Name))
+	}
+
+	return &StorageResponse{Storage: newStorage(storage)}, nil
+}
+
+// applySpec sets the non-empty fields of the config to the spec.
+func (u *Update) applySpec(spec *everestv1alpha1.BackupStorageSpec) {
+	if u.config.Bucket != "" {
+		spec.Bucket = u.config.Bucket
+	}
+	if u.config.Region != "" {
+		spec.Region = u.config.Region
+	}
+	if u.config.EndpointURL != "" {
+		spec.EndpointURL = u.config.EndpointURL
+	}
+	if u.config.Description != "" {
+		spec.Description = u.config.Description
+	}
+}
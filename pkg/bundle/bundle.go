@@ -0,0 +1,208 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle holds the logic of the offline installation bundles.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// ManifestFileName is the name of the Everest manifest inside of a bundle.
+	ManifestFileName = "manifest.yaml"
+	// MetadataFileName is the name of the metadata file inside of a bundle.
+	MetadataFileName = "bundle.yaml"
+
+	maxFileSize = 64 << 20
+)
+
+// ErrManifestAndBundle appears when both a manifest file and a bundle are provided.
+var ErrManifestAndBundle = errors.New("manifest file and bundle cannot be used together")
+
+type (
+	// Bundle holds everything needed to install Everest without network access.
+	Bundle struct {
+		// Manifest is the Everest quickstart manifest.
+		Manifest []byte
+		// Metadata describes the bundle.
+		Metadata Metadata
+	}
+
+	// Metadata describes the content of a bundle.
+	Metadata struct {
+		// Version is the version of everestctl which created the bundle.
+		Version string `yaml:"version,omitempty" json:"version,omitempty"`
+		// CatalogImage is the image of the Everest OLM catalog.
+		CatalogImage string `yaml:"catalogImage,omitempty" json:"catalogImage,omitempty"`
+		// Images lists the images which shall be mirrored to the local registry.
+		Images []string `yaml:"images,omitempty" json:"images,omitempty"`
+	}
+)
+
+// Read reads a bundle from a tar.gz file.
+func Read(path string) (*Bundle, error) {
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not open bundle"))
+	}
+	defer f.Close() //nolint:errcheck
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("bundle is not a gzip archive"))
+	}
+
+	b := &Bundle{}
+	var meta []byte
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not read bundle"))
+		}
+
+		switch h.Name {
+		case ManifestFileName:
+			b.Manifest, err = io.ReadAll(io.LimitReader(tr, maxFileSize))
+		case MetadataFileName:
+			meta, err = io.ReadAll(io.LimitReader(tr, maxFileSize))
+		}
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not read %s from bundle", h.Name))
+		}
+	}
+
+	if len(b.Manifest) == 0 {
+		return nil, fmt.Errorf("bundle does not contain %s", ManifestFileName)
+	}
+	if meta != nil {
+		if err := yaml.Unmarshal(meta, &b.Metadata); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("invalid %s in bundle", MetadataFileName))
+		}
+	}
+
+	return b, nil
+}
+
+// Load returns a bundle from either a bundle file or a manifest file.
+// It returns nil if both paths are empty.
+func Load(bundlePath, manifestPath string) (*Bundle, error) {
+	switch {
+	case bundlePath != "" && manifestPath != "":
+		return nil, ErrManifestAndBundle
+	case bundlePath != "":
+		return Read(bundlePath)
+	case manifestPath != "":
+		m, err := os.ReadFile(manifestPath) //nolint:gosec
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not read manifest file"))
+		}
+		return &Bundle{Manifest: m}, nil
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// Write writes the bundle to a tar.gz file.
+func (b *Bundle) Write(path string) error {
+	meta, err := yaml.Marshal(b.Metadata)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, f := range []struct {
+		name    string
+		content []byte
+	}{
+		{MetadataFileName, meta},
+		{ManifestFileName, b.Manifest},
+	} {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    f.name,
+			Mode:    0o644, //nolint:gomnd
+			Size:    int64(len(f.content)),
+			ModTime: now,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644) //nolint:gosec,gomnd
+}
+
+// ManifestImages returns the sorted list of the images used in the manifest.
+func ManifestImages(manifest []byte) ([]string, error) {
+	images := make(map[string]struct{})
+	dec := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 100) //nolint:gomnd
+	for {
+		u := &unstructured.Unstructured{}
+		err := dec.Decode(&u.Object)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not decode manifest"))
+		}
+		collectImages(u.Object, images)
+	}
+
+	return sortedImages(images), nil
+}
+
+// collectImages walks the object and collects the values of all "image" fields.
+func collectImages(obj interface{}, images map[string]struct{}) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range o {
+			if s, ok := v.(string); ok && k == "image" && s != "" {
+				images[s] = struct{}{}
+				continue
+			}
+			collectImages(v, images)
+		}
+	case []interface{}:
+		for _, v := range o {
+			collectImages(v, images)
+		}
+	}
+}
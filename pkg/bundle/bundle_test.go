@@ -0,0 +1,63 @@
+package bundle
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: percona-everest
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox:1.36
+      containers:
+      - name: everest
+        image: percona/percona-everest:0.8.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: everest
+`
+
+func TestManifestImages(t *testing.T) {
+	t.Parallel()
+
+	images, err := ManifestImages([]byte(testManifest))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"busybox:1.36", "percona/percona-everest:0.8.0"}, images)
+}
+
+func TestWriteRead(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	b := &Bundle{
+		Manifest: []byte(testManifest),
+		Metadata: Metadata{
+			Version:      "0.8.0",
+			CatalogImage: "registry.local/percona/everest-catalog:0.8.0",
+			Images:       []string{"percona/percona-everest:0.8.0"},
+		},
+	}
+	require.NoError(t, b.Write(path))
+
+	got, err := Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, b, got)
+
+	got, err = Load(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, b, got)
+
+	_, err = Load(path, "manifest.yaml")
+	assert.ErrorIs(t, err, ErrManifestAndBundle)
+}
@@ -0,0 +1,364 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/percona/percona-everest-cli/data"
+)
+
+const (
+	// catalogConfigsDir is the directory of the file-based catalog in the catalog image.
+	catalogConfigsDir = "configs/"
+	// fbcBundleSchema is the schema of the bundle entries in a file-based catalog.
+	fbcBundleSchema = "olm.bundle"
+
+	dockerHubRegistry = "registry-1.docker.io"
+	registryTimeout   = 5 * time.Minute
+)
+
+// manifestMediaTypes are the media types of the image manifests and indexes the registry client accepts.
+var manifestMediaTypes = []string{ //nolint:gochecknoglobals
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+type (
+	// fbcBundle is a bundle entry of a file-based catalog.
+	fbcBundle struct {
+		Schema        string `json:"schema"`
+		Image         string `json:"image"`
+		RelatedImages []struct {
+			Image string `json:"image"`
+		} `json:"relatedImages"`
+	}
+
+	// imageManifest is an image manifest or an image index.
+	imageManifest struct {
+		Manifests []struct {
+			Digest   string `json:"digest"`
+			Platform struct {
+				OS           string `json:"os"`
+				Architecture string `json:"architecture"`
+			} `json:"platform"`
+		} `json:"manifests"`
+		Layers []struct {
+			Digest    string `json:"digest"`
+			MediaType string `json:"mediaType"`
+		} `json:"layers"`
+	}
+
+	// registryClient reads images from a registry using the distribution API.
+	// Only anonymous access is supported.
+	registryClient struct {
+		httpClient *http.Client
+		scheme     string
+		token      string
+	}
+)
+
+func newRegistryClient() *registryClient {
+	return &registryClient{
+		httpClient: &http.Client{Timeout: registryTimeout},
+		scheme:     "https",
+	}
+}
+
+// OLMImages returns the images of the OLM manifests shipped with the CLI.
+func OLMImages() ([]string, error) {
+	m, err := fs.ReadFile(data.OLMCRDs, "crds/olm/olm.yaml")
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to read OLM manifest"))
+	}
+	return ManifestImages(m)
+}
+
+// CatalogImages returns the bundle images and the related images
+// of the bundles in a rendered file-based catalog.
+func CatalogImages(catalog []byte) ([]string, error) {
+	images := make(map[string]struct{})
+	if err := collectCatalogImages(catalog, images); err != nil {
+		return nil, err
+	}
+	return sortedImages(images), nil
+}
+
+func collectCatalogImages(catalog []byte, images map[string]struct{}) error {
+	dec := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(catalog), 100) //nolint:gomnd
+	for {
+		b := fbcBundle{}
+		err := dec.Decode(&b)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Join(err, errors.New("could not decode catalog"))
+		}
+		if b.Schema != fbcBundleSchema {
+			continue
+		}
+		if b.Image != "" {
+			images[b.Image] = struct{}{}
+		}
+		for _, r := range b.RelatedImages {
+			if r.Image != "" {
+				images[r.Image] = struct{}{}
+			}
+		}
+	}
+}
+
+// catalogImages pulls the catalog image and returns the images of its bundles.
+func (c *registryClient) catalogImages(ctx context.Context, image string) ([]string, error) {
+	registry, repository, reference := parseImageReference(image)
+	m, err := c.manifest(ctx, registry, repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Manifests) != 0 {
+		digest := m.Manifests[0].Digest
+		for _, d := range m.Manifests {
+			if d.Platform.OS == "linux" && d.Platform.Architecture == "amd64" {
+				digest = d.Digest
+				break
+			}
+		}
+		if m, err = c.manifest(ctx, registry, repository, digest); err != nil {
+			return nil, err
+		}
+	}
+
+	images := make(map[string]struct{})
+	for _, l := range m.Layers {
+		if err := c.readLayer(ctx, registry, repository, l.Digest, l.MediaType, images); err != nil {
+			return nil, err
+		}
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no bundles found in catalog image %s", image)
+	}
+	return sortedImages(images), nil
+}
+
+func (c *registryClient) manifest(ctx context.Context, registry, repository, reference string) (*imageManifest, error) {
+	resp, err := c.get(ctx, registry, repository, "manifests/"+reference, manifestMediaTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	m := &imageManifest{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxFileSize)).Decode(m); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not decode manifest %s of %s", reference, repository))
+	}
+	return m, nil
+}
+
+// readLayer collects the images of the catalog files in the layer.
+func (c *registryClient) readLayer(
+	ctx context.Context,
+	registry, repository, digest, mediaType string,
+	images map[string]struct{},
+) error {
+	resp, err := c.get(ctx, registry, repository, "blobs/"+digest, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var r io.Reader = resp.Body
+	if strings.Contains(mediaType, "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not read layer %s", digest))
+		}
+		defer gz.Close() //nolint:errcheck
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not read layer %s", digest))
+		}
+		name := strings.TrimPrefix(path.Clean(h.Name), "/")
+		if h.Typeflag != tar.TypeReg || !strings.HasPrefix(name, catalogConfigsDir) {
+			continue
+		}
+		switch path.Ext(name) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+		b, err := io.ReadAll(io.LimitReader(tr, maxFileSize))
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not read %s", name))
+		}
+		if err := collectCatalogImages(b, images); err != nil {
+			return errors.Join(err, fmt.Errorf("invalid catalog file %s", name))
+		}
+	}
+}
+
+// get requests the registry API and authenticates with an anonymous token if asked to.
+func (c *registryClient) get(ctx context.Context, registry, repository, p string, accept []string) (*http.Response, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", c.scheme, registry, repository, p)
+	resp, err := c.do(ctx, u, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close() //nolint:errcheck,gosec
+		if err := c.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(ctx, u, accept); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close() //nolint:errcheck,gosec
+		return nil, fmt.Errorf("could not get %s: %s", u, resp.Status)
+	}
+	return resp, nil
+}
+
+func (c *registryClient) do(ctx context.Context, u string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) != 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// authenticate gets an anonymous token for the Bearer challenge of the registry.
+func (c *registryClient) authenticate(ctx context.Context, challenge string) error {
+	params := parseChallenge(challenge)
+	realm := params["realm"]
+	if realm == "" {
+		return fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+	q := url.Values{}
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			q.Set(k, params[k])
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not get registry token from %s: %s", realm, resp.Status)
+	}
+
+	t := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return errors.Join(err, errors.New("could not decode registry token"))
+	}
+	c.token = t.Token
+	if c.token == "" {
+		c.token = t.AccessToken
+	}
+	return nil
+}
+
+// parseChallenge returns the parameters of a Bearer WWW-Authenticate challenge.
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+	scheme, rest, ok := strings.Cut(challenge, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return params
+	}
+	for _, p := range strings.Split(rest, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+		if ok {
+			params[k] = strings.Trim(v, `"`)
+		}
+	}
+	return params
+}
+
+// parseImageReference splits the image into the registry, the repository
+// and the tag or digest following the Docker conventions.
+func parseImageReference(image string) (string, string, string) {
+	name, reference := image, "latest"
+	if n, digest, ok := strings.Cut(image, "@"); ok {
+		name, reference = n, digest
+	} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, reference = image[:i], image[i+1:]
+	}
+
+	registry := "docker.io"
+	if first, rest, ok := strings.Cut(name, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, name = first, rest
+	}
+	if registry == "docker.io" {
+		registry = dockerHubRegistry
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	return registry, name, reference
+}
+
+func sortedImages(images map[string]struct{}) []string {
+	res := make([]string, 0, len(images))
+	for i := range images {
+		res = append(res, i)
+	}
+	sort.Strings(res)
+	return res
+}
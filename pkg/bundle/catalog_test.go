@@ -0,0 +1,165 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testCatalog = `{
+  "schema": "olm.package",
+  "name": "percona-xtradb-cluster-operator"
+}
+{
+  "schema": "olm.bundle",
+  "name": "percona-xtradb-cluster-operator.v1.13.0",
+  "image": "docker.io/percona/percona-xtradb-cluster-operator:1.13.0-bundle",
+  "relatedImages": [
+    {"name": "operator", "image": "docker.io/percona/percona-xtradb-cluster-operator:1.13.0"},
+    {"name": "", "image": "docker.io/percona/percona-xtradb-cluster-operator:1.13.0-bundle"}
+  ]
+}
+`
+
+func TestCatalogImages(t *testing.T) {
+	t.Parallel()
+
+	images, err := CatalogImages([]byte(testCatalog))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"docker.io/percona/percona-xtradb-cluster-operator:1.13.0",
+		"docker.io/percona/percona-xtradb-cluster-operator:1.13.0-bundle",
+	}, images)
+}
+
+func TestCreateIncludesOLMAndOperatorImages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(testManifest), 0o600))
+	catalog := filepath.Join(dir, "catalog.json")
+	require.NoError(t, os.WriteFile(catalog, []byte(testCatalog), 0o600))
+
+	res, err := NewCreate(CreateConfig{
+		Output:       filepath.Join(dir, "bundle.tar.gz"),
+		CatalogImage: "registry.local/percona/everest-catalog:0.8.0",
+		ManifestFile: manifest,
+		CatalogFile:  catalog,
+	}, zap.NewNop().Sugar()).Run(context.Background())
+	require.NoError(t, err)
+
+	olmImages, err := OLMImages()
+	require.NoError(t, err)
+	require.NotEmpty(t, olmImages)
+	for _, i := range append(olmImages,
+		"busybox:1.36",
+		"registry.local/percona/everest-catalog:0.8.0",
+		"docker.io/percona/percona-xtradb-cluster-operator:1.13.0",
+		"docker.io/percona/percona-xtradb-cluster-operator:1.13.0-bundle",
+	) {
+		assert.Contains(t, res.Metadata.Images, i)
+	}
+}
+
+func TestRegistryCatalogImages(t *testing.T) {
+	t.Parallel()
+
+	var layer bytes.Buffer
+	gz := gzip.NewWriter(&layer)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name:     "configs/percona-xtradb-cluster-operator/catalog.json",
+		Typeflag: tar.TypeReg,
+		Mode:     0o644,
+		Size:     int64(len(testCatalog)),
+	}))
+	_, err := tw.Write([]byte(testCatalog))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			assert.Equal(t, "repository:percona/everest-catalog:pull", r.URL.Query().Get("scope"))
+			json.NewEncoder(w).Encode(map[string]string{"token": "secret"}) //nolint:errcheck,errchkjson
+			return
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.Header().Set("WWW-Authenticate",
+				`Bearer realm="`+srv.URL+`/token",service="registry",scope="repository:percona/everest-catalog:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/percona/everest-catalog/manifests/0.8.0":
+			json.NewEncoder(w).Encode(map[string]interface{}{ //nolint:errcheck,errchkjson
+				"manifests": []map[string]interface{}{
+					{"digest": "sha256:arm", "platform": map[string]string{"os": "linux", "architecture": "arm64"}},
+					{"digest": "sha256:amd", "platform": map[string]string{"os": "linux", "architecture": "amd64"}},
+				},
+			})
+		case "/v2/percona/everest-catalog/manifests/sha256:amd":
+			json.NewEncoder(w).Encode(map[string]interface{}{ //nolint:errcheck,errchkjson
+				"layers": []map[string]string{
+					{"digest": "sha256:layer", "mediaType": "application/vnd.oci.image.layer.v1.tar+gzip"},
+				},
+			})
+		case "/v2/percona/everest-catalog/blobs/sha256:layer":
+			w.Write(layer.Bytes()) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := &registryClient{httpClient: srv.Client(), scheme: "https"}
+	host := strings.TrimPrefix(srv.URL, "https://")
+	images, err := c.catalogImages(context.Background(), host+"/percona/everest-catalog:0.8.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"docker.io/percona/percona-xtradb-cluster-operator:1.13.0",
+		"docker.io/percona/percona-xtradb-cluster-operator:1.13.0-bundle",
+	}, images)
+}
+
+func TestParseImageReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		image      string
+		registry   string
+		repository string
+		reference  string
+	}{
+		{image: "busybox", registry: dockerHubRegistry, repository: "library/busybox", reference: "latest"},
+		{image: "percona/everest-catalog:0.8.0", registry: dockerHubRegistry, repository: "percona/everest-catalog", reference: "0.8.0"},
+		{image: "docker.io/percona/everest-catalog:0.8.0", registry: dockerHubRegistry, repository: "percona/everest-catalog", reference: "0.8.0"},
+		{image: "localhost:5000/everest-catalog", registry: "localhost:5000", repository: "everest-catalog", reference: "latest"},
+		{image: "quay.io/operator-framework/olm@sha256:163b", registry: "quay.io", repository: "operator-framework/olm", reference: "sha256:163b"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.image, func(t *testing.T) {
+			t.Parallel()
+			registry, repository, reference := parseImageReference(tt.image)
+			assert.Equal(t, tt.registry, registry)
+			assert.Equal(t, tt.repository, repository)
+			assert.Equal(t, tt.reference, reference)
+		})
+	}
+}
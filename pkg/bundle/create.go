@@ -0,0 +1,189 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/version"
+)
+
+type (
+	// CreateConfig stores configuration for the create command.
+	CreateConfig struct {
+		// Output is a path the bundle is written to.
+		Output string `mapstructure:"output"`
+		// CatalogImage is the image of the Everest OLM catalog.
+		// If empty, the image matching the CLI version is used.
+		CatalogImage string `mapstructure:"catalog-image"`
+		// ManifestFile is a path to a local Everest manifest
+		// used instead of downloading it.
+		ManifestFile string `mapstructure:"manifest-file"`
+		// CatalogFile is a path to the rendered catalog, e.g. the output of
+		// `opm render`, used instead of pulling the catalog image.
+		CatalogFile string `mapstructure:"catalog-file"`
+		// Images is a list of additional images to be mirrored.
+		Images []string `mapstructure:"image"`
+	}
+
+	// CreateResponse is a response from the create command.
+	CreateResponse struct {
+		// Path is the path of the created bundle.
+		Path string `json:"path"`
+		// Metadata describes the created bundle.
+		Metadata Metadata `json:"metadata"`
+	}
+)
+
+func (r CreateResponse) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Bundle has been written to %s\n\n", r.Path)
+	b.WriteString("Mirror the following images to your registry before installing Everest:\n")
+	for _, i := range r.Metadata.Images {
+		fmt.Fprintf(&b, "  %s\n", i)
+	}
+	return b.String()
+}
+
+// Create implements the main logic for the create command.
+type Create struct {
+	config CreateConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+	registry   *registryClient
+}
+
+// NewCreate returns a new Create struct.
+func NewCreate(c CreateConfig, l *zap.SugaredLogger) *Create {
+	cli := &Create{
+		config: c,
+		l:      l.With("component", "bundle/create"),
+	}
+	// The cluster is not contacted when creating a bundle.
+	cli.kubeClient = kubernetes.NewEmpty(cli.l)
+	cli.registry = newRegistryClient()
+
+	return cli
+}
+
+// Run runs the create command.
+func (c *Create) Run(ctx context.Context) (*CreateResponse, error) {
+	if c.config.Output == "" {
+		return nil, errors.New("output path cannot be empty")
+	}
+
+	manifest, err := c.manifest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := ManifestImages(manifest)
+	if err != nil {
+		return nil, err
+	}
+	olmImages, err := OLMImages()
+	if err != nil {
+		return nil, err
+	}
+	images = mergeImages(images, olmImages)
+
+	catalogImage := c.config.CatalogImage
+	if catalogImage == "" {
+		catalogImage = version.CatalogImage()
+	}
+	operatorImages, err := c.catalogImages(ctx, catalogImage)
+	if err != nil {
+		return nil, err
+	}
+	images = mergeImages(images, operatorImages)
+
+	b := &Bundle{
+		Manifest: manifest,
+		Metadata: Metadata{
+			Version:      version.Version,
+			CatalogImage: catalogImage,
+			Images:       mergeImages(images, append([]string{catalogImage}, c.config.Images...)),
+		},
+	}
+
+	c.l.Infof("Writing bundle to %s", c.config.Output)
+	if err := b.Write(c.config.Output); err != nil {
+		return nil, errors.Join(err, errors.New("could not write bundle"))
+	}
+
+	return &CreateResponse{Path: c.config.Output, Metadata: b.Metadata}, nil
+}
+
+func (c *Create) manifest(ctx context.Context) ([]byte, error) {
+	if c.config.ManifestFile != "" {
+		m, err := os.ReadFile(c.config.ManifestFile) //nolint:gosec
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not read manifest file"))
+		}
+		return m, nil
+	}
+
+	c.l.Info("Downloading Everest manifest")
+	m, err := c.kubeClient.GetEverestManifest(ctx)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed downloading everest manifest file"))
+	}
+	return m, nil
+}
+
+// catalogImages returns the operator and bundle images of the catalog.
+func (c *Create) catalogImages(ctx context.Context, catalogImage string) ([]string, error) {
+	if c.config.CatalogFile != "" {
+		catalog, err := os.ReadFile(c.config.CatalogFile) //nolint:gosec
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not read catalog file"))
+		}
+		return CatalogImages(catalog)
+	}
+
+	c.l.Infof("Reading operator images from catalog %s", catalogImage)
+	images, err := c.registry.catalogImages(ctx, catalogImage)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not read catalog image %s. "+
+			"Provide the output of `opm render %s` with --catalog-file instead", catalogImage, catalogImage))
+	}
+	return images, nil
+}
+
+// mergeImages returns the sorted union of both lists without empty values.
+func mergeImages(a, b []string) []string {
+	set := make(map[string]struct{}, len(a)+len(b))
+	for _, i := range append(a, b...) {
+		if i = strings.TrimSpace(i); i != "" {
+			set[i] = struct{}{}
+		}
+	}
+	res := make([]string, 0, len(set))
+	for i := range set {
+		res = append(res, i)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,355 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// proxyTypes maps the engine types to the supported proxy types, the default one first.
+var proxyTypes = map[everestv1alpha1.EngineType][]everestv1alpha1.ProxyType{ //nolint:gochecknoglobals
+	everestv1alpha1.DatabaseEnginePXC:        {everestv1alpha1.ProxyTypeHAProxy, everestv1alpha1.ProxyTypeProxySQL},
+	everestv1alpha1.DatabaseEnginePSMDB:      {everestv1alpha1.ProxyTypeMongos},
+	everestv1alpha1.DatabaseEnginePostgresql: {everestv1alpha1.ProxyTypePGBouncer},
+}
+
+// Create implements the main logic for the db create command.
+type Create struct {
+	config CreateConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// CreateConfig stores configuration for the db create command.
+	CreateConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// Name is the name of the database cluster.
+		Name string `mapstructure:"-"`
+		// Namespace is the namespace managed by Everest the cluster is created in.
+		Namespace string `mapstructure:"namespace"`
+
+		// Engine is the type of the database engine.
+		Engine string `mapstructure:"engine"`
+		// Version is the version of the database engine.
+		// The recommended version of the engine is used if empty.
+		Version  string `mapstructure:"version"`
+		Replicas int32  `mapstructure:"replicas"`
+		// StorageSize is the size of the volume of every replica.
+		StorageSize string `mapstructure:"storage-size"`
+		// StorageClass is the storage class of the volumes.
+		// The default storage class is used if empty.
+		StorageClass string `mapstructure:"storage-class"`
+		// CPU and Memory are the resources of every replica. No limits are set if empty.
+		CPU    string `mapstructure:"cpu"`
+		Memory string `mapstructure:"memory"`
+
+		// ProxyType is the type of the proxy. The default proxy of the engine is used if empty.
+		ProxyType string `mapstructure:"proxy-type"`
+		// ProxyReplicas is the number of proxy replicas. It defaults to the number of replicas.
+		ProxyReplicas int32 `mapstructure:"proxy-replicas"`
+		// ExposeExternal exposes the cluster outside of Kubernetes.
+		ExposeExternal bool `mapstructure:"expose-external"`
+		// IPSourceRanges is a comma-separated list of the ranges allowed to access the exposed cluster.
+		IPSourceRanges string `mapstructure:"ip-source-ranges"`
+
+		// BackupSchedule is the cron schedule of the backups. Backups are disabled if empty.
+		BackupSchedule string `mapstructure:"backup-schedule"`
+		// BackupStorage is the name of the backup storage the scheduled backups are stored in.
+		BackupStorage string `mapstructure:"backup-storage"`
+		// BackupRetention is the number of the scheduled backups to keep.
+		BackupRetention int32 `mapstructure:"backup-retention"`
+
+		// Wait waits until the cluster is ready.
+		Wait bool `mapstructure:"wait"`
+		// WaitTimeout is the time to wait for the cluster to become ready.
+		WaitTimeout time.Duration `mapstructure:"wait-timeout"`
+	}
+
+	// ClusterResponse is a response from the db commands describing a single cluster.
+	ClusterResponse struct {
+		Cluster Cluster `json:"cluster"`
+	}
+)
+
+func (r ClusterResponse) String() string {
+	c := r.Cluster
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", c.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", c.Namespace)
+	fmt.Fprintf(w, "Engine:\t%s %s\n", c.Engine, c.Version)
+	fmt.Fprintf(w, "Replicas:\t%d\n", c.Replicas)
+	fmt.Fprintf(w, "Storage:\t%s %s\n", c.StorageSize, c.StorageClass)
+	if c.CPU != "" || c.Memory != "" {
+		fmt.Fprintf(w, "Resources:\tcpu=%s memory=%s\n", c.CPU, c.Memory)
+	}
+	if c.ProxyType != "" {
+		fmt.Fprintf(w, "Proxy:\t%s replicas=%d expose=%s\n", c.ProxyType, c.ProxyReplicas, c.Expose)
+	}
+	for _, s := range c.Schedules {
+		fmt.Fprintf(w, "Backup schedule:\t%s %q storage=%s retention=%d enabled=%t\n",
+			s.Name, s.Schedule, s.BackupStorage, s.RetentionCopies, s.Enabled)
+	}
+	fmt.Fprintf(w, "Status:\t%s %d/%d ready\n", c.Status, c.Ready, c.Size)
+	if c.Hostname != "" {
+		fmt.Fprintf(w, "Endpoint:\t%s:%d\n", c.Hostname, c.Port)
+	}
+	if c.Message != "" {
+		fmt.Fprintf(w, "Message:\t%s\n", c.Message)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewCreate returns a new Create struct.
+func NewCreate(c CreateConfig, l *zap.SugaredLogger) (*Create, error) {
+	cli := &Create{
+		config: c,
+		l:      l.With("component", "db/create"),
+	}
+	if err := cli.config.validate(); err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// validate validates the config without contacting the cluster.
+func (c CreateConfig) validate() error {
+	if err := ValidateName(c.Name); err != nil {
+		return err
+	}
+	if c.Namespace == "" {
+		return errors.New("namespace is required")
+	}
+	supported, ok := proxyTypes[everestv1alpha1.EngineType(c.Engine)]
+	if !ok {
+		return fmt.Errorf("invalid engine type %q. Allowed values are %s, %s and %s", c.Engine,
+			everestv1alpha1.DatabaseEnginePXC, everestv1alpha1.DatabaseEnginePSMDB, everestv1alpha1.DatabaseEnginePostgresql)
+	}
+	if c.ProxyType != "" && !slices.Contains(supported, everestv1alpha1.ProxyType(c.ProxyType)) {
+		return fmt.Errorf("proxy type %q is not supported by %s engine", c.ProxyType, c.Engine)
+	}
+	if c.Replicas < 1 {
+		return errors.New("replicas must be at least 1")
+	}
+	if c.ProxyReplicas < 0 {
+		return errors.New("proxy replicas must not be negative")
+	}
+	for flag, q := range map[string]string{"storage-size": c.StorageSize, "cpu": c.CPU, "memory": c.Memory} {
+		if q == "" && flag != "storage-size" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(q); err != nil {
+			return errors.Join(err, fmt.Errorf("invalid %s %q", flag, q))
+		}
+	}
+	if c.IPSourceRanges != "" && !c.ExposeExternal {
+		return errors.New("IP source ranges require the cluster to be exposed externally")
+	}
+	if c.BackupSchedule != "" && c.BackupStorage == "" {
+		return errors.New("backup storage is required for the backup schedule")
+	}
+	return nil
+}
+
+// Run runs the db create command.
+func (c *Create) Run(ctx context.Context) (*ClusterResponse, error) {
+	namespaces, err := c.kubeClient.GetDBNamespaces(ctx, c.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+	if !slices.Contains(namespaces, c.config.Namespace) {
+		return nil, fmt.Errorf("namespace %s is not managed by Everest", c.config.Namespace)
+	}
+
+	engine, err := c.findEngine(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := engineVersion(engine, c.config.Version)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.BackupStorage != "" {
+		if err := c.checkBackupStorage(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	db := c.databaseCluster(version)
+	c.l.Infof("Creating %s database cluster %s with version %s", c.config.Engine, c.config.Name, version)
+	err = c.kubeClient.CreateDatabaseCluster(db)
+	if k8serrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("database cluster %s already exists in %s namespace", c.config.Name, c.config.Namespace)
+	}
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not create %s database cluster", c.config.Name))
+	}
+
+	if c.config.Wait {
+		if db, err = waitForReady(ctx, c.kubeClient, c.l, c.config.Namespace, c.config.Name, c.config.WaitTimeout, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	return &ClusterResponse{Cluster: newCluster(db)}, nil
+}
+
+// findEngine returns the database engine of the configured type.
+func (c *Create) findEngine(ctx context.Context) (*everestv1alpha1.DatabaseEngine, error) {
+	engines, err := c.kubeClient.ListDatabaseEngines(ctx, c.config.Namespace)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not list database engines in %s namespace", c.config.Namespace))
+	}
+	for i, e := range engines.Items {
+		if string(e.Spec.Type) != c.config.Engine {
+			continue
+		}
+		if e.Status.State != everestv1alpha1.DBEngineStateInstalled {
+			return nil, fmt.Errorf("%s engine is %s in %s namespace", c.config.Engine, e.Status.State, c.config.Namespace)
+		}
+		return &engines.Items[i], nil
+	}
+	return nil, fmt.Errorf("%s engine is not available in %s namespace. Add the operator with `everestctl namespaces add`",
+		c.config.Engine, c.config.Namespace)
+}
+
+func (c *Create) checkBackupStorage(ctx context.Context) error {
+	storage, err := c.kubeClient.GetBackupStorage(ctx, c.config.SystemNamespace, c.config.BackupStorage)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not get %s backup storage", c.config.BackupStorage))
+	}
+	if !storage.IsNamespaceAllowed(c.config.Namespace) {
+		return fmt.Errorf("backup storage %s is not allowed in %s namespace", c.config.BackupStorage, c.config.Namespace)
+	}
+	return nil
+}
+
+// engineVersion validates the version against the versions available in the engine.
+// The recommended version is returned if the version is empty.
+func engineVersion(engine *everestv1alpha1.DatabaseEngine, version string) (string, error) {
+	allowed := engine.Status.AvailableVersions.Engine.GetAllowedVersionsSorted()
+	if len(engine.Spec.AllowedVersions) != 0 {
+		allowed = slices.DeleteFunc(allowed, func(v string) bool {
+			return !slices.Contains(engine.Spec.AllowedVersions, v)
+		})
+	}
+	if len(allowed) == 0 {
+		return "", fmt.Errorf("%s engine has no available versions", engine.Spec.Type)
+	}
+	if version == "" {
+		return allowed[0], nil
+	}
+	if !slices.Contains(allowed, version) {
+		return "", fmt.Errorf("version %s is not available for %s engine. Available versions are %s",
+			version, engine.Spec.Type, strings.Join(allowed, ", "))
+	}
+	return version, nil
+}
+
+func (c *Create) databaseCluster(version string) *everestv1alpha1.DatabaseCluster {
+	engineType := everestv1alpha1.EngineType(c.config.Engine)
+	db := &everestv1alpha1.DatabaseCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.config.Name,
+			Namespace: c.config.Namespace,
+		},
+		Spec: everestv1alpha1.DatabaseClusterSpec{
+			Engine: everestv1alpha1.Engine{
+				Type:     engineType,
+				Version:  version,
+				Replicas: c.config.Replicas,
+				Storage: everestv1alpha1.Storage{
+					Size: resource.MustParse(c.config.StorageSize),
+				},
+			},
+			Proxy: everestv1alpha1.Proxy{
+				Type: proxyTypes[engineType][0],
+				Expose: everestv1alpha1.Expose{
+					Type: everestv1alpha1.ExposeTypeInternal,
+				},
+			},
+		},
+	}
+
+	if c.config.StorageClass != "" {
+		db.Spec.Engine.Storage.Class = &c.config.StorageClass
+	}
+	if c.config.CPU != "" {
+		db.Spec.Engine.Resources.CPU = resource.MustParse(c.config.CPU)
+	}
+	if c.config.Memory != "" {
+		db.Spec.Engine.Resources.Memory = resource.MustParse(c.config.Memory)
+	}
+
+	if c.config.ProxyType != "" {
+		db.Spec.Proxy.Type = everestv1alpha1.ProxyType(c.config.ProxyType)
+	}
+	proxyReplicas := c.config.ProxyReplicas
+	if proxyReplicas == 0 {
+		proxyReplicas = c.config.Replicas
+	}
+	db.Spec.Proxy.Replicas = &proxyReplicas
+	if c.config.ExposeExternal {
+		db.Spec.Proxy.Expose.Type = everestv1alpha1.ExposeTypeExternal
+		for _, r := range strings.Split(c.config.IPSourceRanges, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				db.Spec.Proxy.Expose.IPSourceRanges = append(db.Spec.Proxy.Expose.IPSourceRanges, everestv1alpha1.IPSourceRange(r))
+			}
+		}
+	}
+
+	if c.config.BackupSchedule != "" {
+		db.Spec.Backup = everestv1alpha1.Backup{
+			Enabled: true,
+			Schedules: []everestv1alpha1.BackupSchedule{{
+				Enabled:           true,
+				Name:              c.config.Name + "-backup",
+				Schedule:          c.config.BackupSchedule,
+				BackupStorageName: c.config.BackupStorage,
+				RetentionCopies:   c.config.BackupRetention,
+			}},
+		}
+	}
+
+	return db
+}
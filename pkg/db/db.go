@@ -0,0 +1,192 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package db holds the logic of the db commands managing database clusters.
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+const (
+	// maxNameLength is the longest database cluster name the operators can handle.
+	maxNameLength = 22
+	// pollInterval is the interval between the checks of the cluster state while waiting.
+	pollInterval = 5 * time.Second
+)
+
+type (
+	// Cluster describes a database cluster.
+	Cluster struct {
+		Name          string     `json:"name"`
+		Namespace     string     `json:"namespace"`
+		Engine        string     `json:"engine"`
+		Version       string     `json:"version"`
+		Replicas      int32      `json:"replicas"`
+		StorageSize   string     `json:"storageSize"`
+		StorageClass  string     `json:"storageClass,omitempty"`
+		CPU           string     `json:"cpu,omitempty"`
+		Memory        string     `json:"memory,omitempty"`
+		ProxyType     string     `json:"proxyType,omitempty"`
+		ProxyReplicas int32      `json:"proxyReplicas,omitempty"`
+		Expose        string     `json:"expose,omitempty"`
+		Schedules     []Schedule `json:"backupSchedules,omitempty"`
+		Status        string     `json:"status"`
+		Ready         int32      `json:"ready"`
+		Size          int32      `json:"size"`
+		Hostname      string     `json:"hostname,omitempty"`
+		Port          int32      `json:"port,omitempty"`
+		Message       string     `json:"message,omitempty"`
+	}
+
+	// Schedule describes a backup schedule of a database cluster.
+	Schedule struct {
+		Name            string `json:"name"`
+		Schedule        string `json:"schedule"`
+		BackupStorage   string `json:"backupStorage"`
+		RetentionCopies int32  `json:"retentionCopies,omitempty"`
+		Enabled         bool   `json:"enabled"`
+	}
+)
+
+// ValidateName validates the name of a database cluster.
+func ValidateName(name string) error {
+	if errs := validation.IsDNS1035Label(name); len(errs) != 0 {
+		return fmt.Errorf("invalid database cluster name %q: %s", name, strings.Join(errs, ", "))
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("invalid database cluster name %q: must be no more than %d characters", name, maxNameLength)
+	}
+	return nil
+}
+
+// readiness tracks the status of a database cluster while waiting for it to become ready.
+type readiness struct {
+	// generation is the generation of the changed cluster. It is zero for new clusters.
+	generation int64
+	// before is the status observed before the changes. It is nil for new clusters.
+	before *everestv1alpha1.DatabaseClusterStatus
+	// changed is true once the operator has acted on the changes.
+	changed bool
+}
+
+// ready returns true if all the pods are ready and the operator has acted on the changes.
+// The operator has acted on the changes once it has observed the generation of the changed cluster.
+// For operators that do not report the observed generation, the status has to leave the ready
+// state or change its size instead.
+func (r *readiness) ready(status everestv1alpha1.DatabaseClusterStatus, observedGeneration int64) bool {
+	switch {
+	case r.before == nil:
+		r.changed = true
+	case observedGeneration != 0:
+		r.changed = observedGeneration >= r.generation
+	case status.Status != everestv1alpha1.AppStateReady || status.Size != r.before.Size:
+		r.changed = true
+	}
+	return r.changed && status.Status == everestv1alpha1.AppStateReady && status.Size != 0 && status.Ready == status.Size
+}
+
+// newCluster returns the description of the database cluster.
+func newCluster(db *everestv1alpha1.DatabaseCluster) Cluster {
+	c := Cluster{
+		Name:        db.Name,
+		Namespace:   db.Namespace,
+		Engine:      string(db.Spec.Engine.Type),
+		Version:     db.Spec.Engine.Version,
+		Replicas:    db.Spec.Engine.Replicas,
+		StorageSize: db.Spec.Engine.Storage.Size.String(),
+		ProxyType:   string(db.Spec.Proxy.Type),
+		Expose:      string(db.Spec.Proxy.Expose.Type),
+		Schedules:   make([]Schedule, 0, len(db.Spec.Backup.Schedules)),
+		Status:      string(db.Status.Status),
+		Ready:       db.Status.Ready,
+		Size:        db.Status.Size,
+		Hostname:    db.Status.Hostname,
+		Port:        db.Status.Port,
+		Message:     db.Status.Message,
+	}
+	if db.Spec.Engine.Storage.Class != nil {
+		c.StorageClass = *db.Spec.Engine.Storage.Class
+	}
+	if !db.Spec.Engine.Resources.CPU.IsZero() {
+		c.CPU = db.Spec.Engine.Resources.CPU.String()
+	}
+	if !db.Spec.Engine.Resources.Memory.IsZero() {
+		c.Memory = db.Spec.Engine.Resources.Memory.String()
+	}
+	if db.Spec.Proxy.Replicas != nil {
+		c.ProxyReplicas = *db.Spec.Proxy.Replicas
+	}
+	for _, s := range db.Spec.Backup.Schedules {
+		c.Schedules = append(c.Schedules, Schedule{
+			Name:            s.Name,
+			Schedule:        s.Schedule,
+			BackupStorage:   s.BackupStorageName,
+			RetentionCopies: s.RetentionCopies,
+			Enabled:         s.Enabled,
+		})
+	}
+	return c
+}
+
+// waitForReady waits until all the pods of the database cluster are ready.
+// The first check is delayed by the poll interval so that the operator
+// has time to pick up the changes of an existing cluster.
+// The changed cluster and its status observed before the changes are passed for existing clusters.
+// Their status is not updated until the operator acts on the changes.
+func waitForReady(
+	ctx context.Context,
+	k *kubernetes.Kubernetes,
+	l *zap.SugaredLogger,
+	namespace, name string,
+	timeout time.Duration,
+	changed *everestv1alpha1.DatabaseCluster,
+	before *everestv1alpha1.DatabaseClusterStatus,
+) (*everestv1alpha1.DatabaseCluster, error) {
+	l.Infof("Waiting for %s database cluster to become ready", name)
+	r := &readiness{before: before}
+	if changed != nil {
+		r.generation = changed.Generation
+	}
+	var db *everestv1alpha1.DatabaseCluster
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, false, func(ctx context.Context) (bool, error) {
+		var err error
+		db, err = k.GetDatabaseCluster(ctx, namespace, name)
+		if err != nil {
+			return false, err
+		}
+		observed, err := k.GetDatabaseClusterObservedGeneration(ctx, namespace, name)
+		if err != nil {
+			return false, err
+		}
+		l.Debugf("Database cluster %s is %s, %d/%d pods ready", name, db.Status.Status, db.Status.Ready, db.Status.Size)
+		return r.ready(db.Status, observed), nil
+	})
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("database cluster %s is not ready", name))
+	}
+	return db, nil
+}
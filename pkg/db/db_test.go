@@ -0,0 +1,156 @@
+package db
+
+import (
+	"testing"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateName(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateName("mysql-1"))
+	assert.Error(t, ValidateName("1mysql"))
+	assert.Error(t, ValidateName("MySQL"))
+	assert.Error(t, ValidateName("a-very-long-database-name"))
+}
+
+func TestEngineVersion(t *testing.T) {
+	t.Parallel()
+
+	engine := &everestv1alpha1.DatabaseEngine{
+		Spec: everestv1alpha1.DatabaseEngineSpec{Type: everestv1alpha1.DatabaseEnginePSMDB},
+		Status: everestv1alpha1.DatabaseEngineStatus{
+			AvailableVersions: everestv1alpha1.Versions{
+				Engine: everestv1alpha1.ComponentsMap{
+					"6.0.9-7":  {Status: everestv1alpha1.DBEngineComponentAvailable},
+					"6.0.12-9": {Status: everestv1alpha1.DBEngineComponentRecommended},
+					"4.4.10-8": {Status: everestv1alpha1.DBEngineComponentUnsupported},
+				},
+			},
+		},
+	}
+
+	v, err := engineVersion(engine, "")
+	require.NoError(t, err)
+	assert.Equal(t, "6.0.12-9", v)
+
+	v, err = engineVersion(engine, "6.0.9-7")
+	require.NoError(t, err)
+	assert.Equal(t, "6.0.9-7", v)
+
+	_, err = engineVersion(engine, "4.4.10-8")
+	require.Error(t, err)
+
+	engine.Spec.AllowedVersions = []string{"6.0.9-7"}
+	v, err = engineVersion(engine, "")
+	require.NoError(t, err)
+	assert.Equal(t, "6.0.9-7", v)
+}
+
+func TestCreateConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := CreateConfig{Name: "mongo-1", Namespace: "dev", Engine: "psmdb", Replicas: 3, StorageSize: "10Gi"}
+	require.NoError(t, valid.validate())
+
+	type tcase struct {
+		name   string
+		change func(c *CreateConfig)
+	}
+	tcases := []tcase{
+		{"no namespace", func(c *CreateConfig) { c.Namespace = "" }},
+		{"unknown engine", func(c *CreateConfig) { c.Engine = "mysql" }},
+		{"unsupported proxy", func(c *CreateConfig) { c.ProxyType = "haproxy" }},
+		{"no replicas", func(c *CreateConfig) { c.Replicas = 0 }},
+		{"invalid storage size", func(c *CreateConfig) { c.StorageSize = "10 GB" }},
+		{"invalid memory", func(c *CreateConfig) { c.Memory = "lots" }},
+		{"ranges without expose", func(c *CreateConfig) { c.IPSourceRanges = "10.0.0.0/8" }},
+		{"schedule without storage", func(c *CreateConfig) { c.BackupSchedule = "0 0 * * *" }},
+	}
+
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := valid
+			tc.change(&c)
+			assert.Error(t, c.validate())
+		})
+	}
+}
+
+func TestDatabaseCluster(t *testing.T) {
+	t.Parallel()
+
+	c := &Create{config: CreateConfig{
+		Name:           "mysql-1",
+		Namespace:      "dev",
+		Engine:         "pxc",
+		Replicas:       3,
+		StorageSize:    "10Gi",
+		CPU:            "1",
+		ExposeExternal: true,
+		IPSourceRanges: "10.0.0.0/8, 192.168.1.1",
+		BackupSchedule: "0 0 * * *",
+		BackupStorage:  "s3",
+	}}
+
+	assert.Equal(t, Cluster{
+		Name:          "mysql-1",
+		Namespace:     "dev",
+		Engine:        "pxc",
+		Version:       "8.0.35-27.1",
+		Replicas:      3,
+		StorageSize:   "10Gi",
+		CPU:           "1",
+		ProxyType:     "haproxy",
+		ProxyReplicas: 3,
+		Expose:        "external",
+		Schedules: []Schedule{{
+			Name:          "mysql-1-backup",
+			Schedule:      "0 0 * * *",
+			BackupStorage: "s3",
+			Enabled:       true,
+		}},
+	}, newCluster(c.databaseCluster("8.0.35-27.1")))
+}
+
+func TestReadiness(t *testing.T) {
+	t.Parallel()
+
+	ready := func(size int32) everestv1alpha1.DatabaseClusterStatus {
+		return everestv1alpha1.DatabaseClusterStatus{Status: everestv1alpha1.AppStateReady, Ready: size, Size: size}
+	}
+	initializing := everestv1alpha1.DatabaseClusterStatus{Status: everestv1alpha1.AppStateInit, Ready: 3, Size: 5}
+
+	type tcase struct {
+		name     string
+		before   *everestv1alpha1.DatabaseClusterStatus
+		statuses []everestv1alpha1.DatabaseClusterStatus
+		observed []int64
+		want     []bool
+	}
+	before := ready(3)
+	tcases := []tcase{
+		{"new cluster", nil, []everestv1alpha1.DatabaseClusterStatus{{}, ready(3)}, []int64{0, 1}, []bool{false, true}},
+		{"stale ready", &before, []everestv1alpha1.DatabaseClusterStatus{ready(3), initializing, ready(5)}, []int64{0, 0, 0}, []bool{false, false, true}},
+		{"size changed", &before, []everestv1alpha1.DatabaseClusterStatus{ready(5)}, []int64{0}, []bool{true}},
+		{"stale generation", &before, []everestv1alpha1.DatabaseClusterStatus{ready(3), ready(3)}, []int64{1, 2}, []bool{false, true}},
+		{"generation observed before ready", &before, []everestv1alpha1.DatabaseClusterStatus{initializing, ready(5)}, []int64{2, 2}, []bool{false, true}},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := &readiness{generation: 2, before: tc.before}
+			got := make([]bool, 0, len(tc.statuses))
+			for i, s := range tc.statuses {
+				got = append(got, r.ready(s, tc.observed[i]))
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Delete implements the main logic for the db delete command.
+type Delete struct {
+	config DeleteConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// DeleteConfig stores configuration for the db delete command.
+type DeleteConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// Name is the name of the database cluster.
+	Name string `mapstructure:"-"`
+	// Namespace is the namespace of the database cluster.
+	Namespace string `mapstructure:"namespace"`
+	// AssumeYes is true when all questions can be skipped.
+	AssumeYes bool `mapstructure:"assume-yes"`
+}
+
+// NewDelete returns a new Delete struct.
+func NewDelete(c DeleteConfig, l *zap.SugaredLogger) (*Delete, error) {
+	if c.Namespace == "" {
+		return nil, errors.New("namespace is required")
+	}
+	cli := &Delete{
+		config: c,
+		l:      l.With("component", "db/delete"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the db delete command.
+func (d *Delete) Run(ctx context.Context) error {
+	if !d.config.AssumeYes {
+		confirm := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to delete %s database cluster in %s namespace?",
+				d.config.Name, d.config.Namespace),
+		}
+		prompt := false
+		if err := survey.AskOne(confirm, &prompt); err != nil {
+			return err
+		}
+		if !prompt {
+			d.l.Info("Exiting")
+			return nil
+		}
+	}
+
+	if err := d.kubeClient.DeleteDatabaseCluster(ctx, d.config.Namespace, d.config.Name); err != nil {
+		return errors.Join(err, fmt.Errorf("could not delete %s database cluster", d.config.Name))
+	}
+	d.l.Infof("Database cluster %s has been deleted", d.config.Name)
+	return nil
+}
@@ -0,0 +1,72 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Get implements the main logic for the db get command.
+type Get struct {
+	config GetConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// GetConfig stores configuration for the db get command.
+type GetConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// Name is the name of the database cluster.
+	Name string `mapstructure:"-"`
+	// Namespace is the namespace of the database cluster.
+	Namespace string `mapstructure:"namespace"`
+}
+
+// NewGet returns a new Get struct.
+func NewGet(c GetConfig, l *zap.SugaredLogger) (*Get, error) {
+	if c.Namespace == "" {
+		return nil, errors.New("namespace is required")
+	}
+	cli := &Get{
+		config: c,
+		l:      l.With("component", "db/get"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the db get command.
+func (g *Get) Run(ctx context.Context) (*ClusterResponse, error) {
+	db, err := g.kubeClient.GetDatabaseCluster(ctx, g.config.Namespace, g.config.Name)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s database cluster", g.config.Name))
+	}
+	return &ClusterResponse{Cluster: newCluster(db)}, nil
+}
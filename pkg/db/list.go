@@ -0,0 +1,114 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// List implements the main logic for the db list command.
+type List struct {
+	config ListConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// ListConfig stores configuration for the db list command.
+	ListConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// Namespace limits the output to a single namespace.
+		// All the namespaces managed by Everest are listed if empty.
+		Namespace string `mapstructure:"namespace"`
+	}
+
+	// ListResponse is a response from the db list command.
+	ListResponse struct {
+		Clusters []Cluster `json:"clusters"`
+	}
+)
+
+func (r ListResponse) String() string {
+	if len(r.Clusters) == 0 {
+		return "There are no database clusters"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tENGINE\tVERSION\tREPLICAS\tSTATUS\tREADY\tHOSTNAME")
+	for _, c := range r.Clusters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%d/%d\t%s\n",
+			c.Namespace, c.Name, c.Engine, c.Version, c.Replicas, c.Status, c.Ready, c.Size, c.Hostname)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewList returns a new List struct.
+func NewList(c ListConfig, l *zap.SugaredLogger) (*List, error) {
+	cli := &List{
+		config: c,
+		l:      l.With("component", "db/list"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the db list command.
+func (l *List) Run(ctx context.Context) (*ListResponse, error) {
+	namespaces := []string{l.config.Namespace}
+	if l.config.Namespace == "" {
+		ns, err := l.kubeClient.GetDBNamespaces(ctx, l.config.SystemNamespace)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+		}
+		sort.Strings(ns)
+		namespaces = ns
+	}
+
+	res := &ListResponse{Clusters: []Cluster{}}
+	for _, ns := range namespaces {
+		dbs, err := l.kubeClient.ListDatabaseClusters(ctx, ns)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not list database clusters in %s namespace", ns))
+		}
+		sort.Slice(dbs.Items, func(i, j int) bool { return dbs.Items[i].Name < dbs.Items[j].Name })
+		for i := range dbs.Items {
+			res.Clusters = append(res.Clusters, newCluster(&dbs.Items[i]))
+		}
+	}
+	return res, nil
+}
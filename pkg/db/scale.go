@@ -0,0 +1,145 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Scale implements the main logic for the db scale command.
+type Scale struct {
+	config ScaleConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// ScaleConfig stores configuration for the db scale command.
+// Only the set values are changed.
+type ScaleConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// Name is the name of the database cluster.
+	Name string `mapstructure:"-"`
+	// Namespace is the namespace of the database cluster.
+	Namespace string `mapstructure:"namespace"`
+
+	Replicas      int32 `mapstructure:"replicas"`
+	ProxyReplicas int32 `mapstructure:"proxy-replicas"`
+	// CPU and Memory are the resources of every replica.
+	CPU    string `mapstructure:"cpu"`
+	Memory string `mapstructure:"memory"`
+
+	// Wait waits until the cluster is ready.
+	Wait bool `mapstructure:"wait"`
+	// WaitTimeout is the time to wait for the cluster to become ready.
+	WaitTimeout time.Duration `mapstructure:"wait-timeout"`
+}
+
+// NewScale returns a new Scale struct.
+func NewScale(c ScaleConfig, l *zap.SugaredLogger) (*Scale, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	cli := &Scale{
+		config: c,
+		l:      l.With("component", "db/scale"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+func (c ScaleConfig) validate() error {
+	if c.Namespace == "" {
+		return errors.New("namespace is required")
+	}
+	if c.Replicas < 0 || c.ProxyReplicas < 0 {
+		return errors.New("replicas must not be negative")
+	}
+	if c.Replicas == 0 && c.ProxyReplicas == 0 && c.CPU == "" && c.Memory == "" {
+		return errors.New("nothing to scale. Set the replicas, the proxy replicas or the resources")
+	}
+	for flag, q := range map[string]string{"cpu": c.CPU, "memory": c.Memory} {
+		if q == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(q); err != nil {
+			return errors.Join(err, fmt.Errorf("invalid %s %q", flag, q))
+		}
+	}
+	return nil
+}
+
+// Run runs the db scale command.
+func (s *Scale) Run(ctx context.Context) (*ClusterResponse, error) {
+	db, err := s.kubeClient.GetDatabaseCluster(ctx, s.config.Namespace, s.config.Name)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s database cluster", s.config.Name))
+	}
+	spec := db.Spec.DeepCopy()
+	status := db.Status
+
+	if s.config.Replicas != 0 {
+		db.Spec.Engine.Replicas = s.config.Replicas
+	}
+	if s.config.ProxyReplicas != 0 {
+		db.Spec.Proxy.Replicas = &s.config.ProxyReplicas
+	}
+	if s.config.CPU != "" {
+		db.Spec.Engine.Resources.CPU = resource.MustParse(s.config.CPU)
+	}
+	if s.config.Memory != "" {
+		db.Spec.Engine.Resources.Memory = resource.MustParse(s.config.Memory)
+	}
+
+	s.l.Infof("Scaling %s database cluster", s.config.Name)
+	if err := s.kubeClient.PatchDatabaseCluster(db); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not scale %s database cluster", s.config.Name))
+	}
+
+	if s.config.Wait {
+		// The status of an unchanged cluster is not updated by the operator.
+		var changed *everestv1alpha1.DatabaseCluster
+		var before *everestv1alpha1.DatabaseClusterStatus
+		if !equality.Semantic.DeepEqual(spec, &db.Spec) {
+			// The patched cluster is read back for the generation the operator has to observe.
+			if changed, err = s.kubeClient.GetDatabaseCluster(ctx, s.config.Namespace, s.config.Name); err != nil {
+				return nil, errors.Join(err, fmt.Errorf("could not get %s database cluster", s.config.Name))
+			}
+			before = &status
+		}
+		if db, err = waitForReady(ctx, s.kubeClient, s.l, s.config.Namespace, s.config.Name, s.config.WaitTimeout, changed, before); err != nil {
+			return nil, err
+		}
+	}
+	return &ClusterResponse{Cluster: newCluster(db)}, nil
+}
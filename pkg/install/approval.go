@@ -0,0 +1,71 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+const (
+	// InstallPlanApprovalAutomatic lets OLM approve the install plans
+	// including the ones of the future upgrades.
+	InstallPlanApprovalAutomatic = "automatic"
+	// InstallPlanApprovalManual approves the install plans created during
+	// the installation. The upgrades have to be approved manually.
+	InstallPlanApprovalManual = "manual"
+	// InstallPlanApprovalPrompt works as manual but asks for a confirmation
+	// before approving every install plan created during the installation.
+	InstallPlanApprovalPrompt = "prompt"
+)
+
+// ValidateInstallPlanApproval validates the install plan approval policy.
+func ValidateInstallPlanApproval(policy string) error {
+	switch policy {
+	case InstallPlanApprovalAutomatic, InstallPlanApprovalManual, InstallPlanApprovalPrompt:
+		return nil
+	}
+	return fmt.Errorf("invalid install plan approval %q. Allowed values are %s, %s and %s",
+		policy, InstallPlanApprovalAutomatic, InstallPlanApprovalManual, InstallPlanApprovalPrompt)
+}
+
+// setApproval sets the approval policy of the install request.
+func (o *Install) setApproval(req *kubernetes.InstallOperatorRequest) {
+	switch o.config.InstallPlanApproval {
+	case InstallPlanApprovalAutomatic:
+		req.InstallPlanApproval = v1alpha1.ApprovalAutomatic
+	case InstallPlanApprovalPrompt:
+		req.InstallPlanApproval = v1alpha1.ApprovalManual
+		req.ConfirmInstallPlan = confirmInstallPlan
+	default:
+		req.InstallPlanApproval = v1alpha1.ApprovalManual
+	}
+}
+
+// confirmInstallPlan asks the user to approve the install plan.
+func confirmInstallPlan(ip *v1alpha1.InstallPlan) (bool, error) {
+	approve := false
+	err := survey.AskOne(&survey.Confirm{
+		Message: fmt.Sprintf("Approve install plan %s in %s namespace installing %s?",
+			ip.Name, ip.Namespace, strings.Join(ip.Spec.ClusterServiceVersionNames, ", ")),
+	}, &approve)
+	return approve, err
+}
@@ -0,0 +1,175 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// clusterProfile holds the install defaults of a cluster type.
+type clusterProfile struct {
+	// builtInOLM is true if the platform ships OLM.
+	builtInOLM bool
+	// serviceType is the type of the Everest service.
+	serviceType corev1.ServiceType
+	// storageClasses are the storage classes made default, in order of preference,
+	// if the cluster has no default storage class.
+	storageClasses []string
+	// limitations are the known limitations of the platform.
+	limitations []string
+}
+
+// profileFor returns the install defaults of the cluster type.
+func profileFor(t kubernetes.ClusterType) clusterProfile {
+	switch t {
+	case kubernetes.ClusterTypeMinikube:
+		return clusterProfile{
+			serviceType:    corev1.ServiceTypeNodePort,
+			storageClasses: []string{"standard"},
+			limitations: []string{
+				"minikube is meant for testing. The data of the database clusters is stored on the minikube node",
+			},
+		}
+	case kubernetes.ClusterTypeKind:
+		return clusterProfile{
+			serviceType:    corev1.ServiceTypeClusterIP,
+			storageClasses: []string{"standard"},
+			limitations: []string{
+				"kind is meant for testing. The data of the database clusters is stored in the node containers",
+				"kind does not expose node ports by default. Use `kubectl port-forward` to access Everest",
+			},
+		}
+	case kubernetes.ClusterTypeK3s:
+		return clusterProfile{
+			serviceType:    corev1.ServiceTypeLoadBalancer,
+			storageClasses: []string{"local-path"},
+			limitations: []string{
+				"local-path volumes are bound to a single node and cannot be expanded",
+			},
+		}
+	case kubernetes.ClusterTypeOpenShift:
+		return clusterProfile{
+			builtInOLM:  true,
+			serviceType: corev1.ServiceTypeClusterIP,
+			limitations: []string{
+				"OpenShift does not expose Everest by default. Create a route to the everest service to access it",
+			},
+		}
+	case kubernetes.ClusterTypeEKS:
+		return clusterProfile{
+			serviceType:    corev1.ServiceTypeClusterIP,
+			storageClasses: []string{"gp3", "gp2"},
+			limitations: []string{
+				"EBS volumes are bound to an availability zone. Database pods cannot move to nodes in other zones",
+			},
+		}
+	case kubernetes.ClusterTypeGKE:
+		return clusterProfile{
+			serviceType:    corev1.ServiceTypeClusterIP,
+			storageClasses: []string{"standard-rwo", "standard"},
+			limitations: []string{
+				"Persistent disks are bound to a zone. Database pods cannot move to nodes in other zones",
+			},
+		}
+	case kubernetes.ClusterTypeAKS:
+		return clusterProfile{
+			serviceType:    corev1.ServiceTypeClusterIP,
+			storageClasses: []string{"managed-csi", "default"},
+			limitations: []string{
+				"Azure disks are bound to a zone. Database pods cannot move to nodes in other zones",
+			},
+		}
+	default:
+		return clusterProfile{serviceType: corev1.ServiceTypeClusterIP}
+	}
+}
+
+// detectClusterType detects the type of the cluster and adapts the installation to it.
+// Nothing is changed in the cluster.
+func (o *Install) detectClusterType(ctx context.Context) error {
+	t, err := o.kubeClient.GetClusterType(ctx)
+	if err != nil {
+		return errors.Join(err, errors.New("could not detect the cluster type"))
+	}
+	o.clusterType = t
+	o.l.Infof("Detected %s cluster", t)
+
+	p := profileFor(t)
+	for _, l := range p.limitations {
+		o.l.Warn(l)
+	}
+
+	if p.builtInOLM && !o.config.UseExistingOLM {
+		o.l.Infof("%s ships Operator Lifecycle Manager. Using the existing OLM installation", t)
+		o.config.UseExistingOLM = true
+	}
+	if o.config.EverestServiceType == "" {
+		o.config.EverestServiceType = string(p.serviceType)
+	}
+	o.kubeClient.SetEverestServiceType(corev1.ServiceType(o.config.EverestServiceType))
+
+	return nil
+}
+
+// provisionStorageClass makes sure the cluster has a default storage class.
+func (o *Install) provisionStorageClass(ctx context.Context) error {
+	return o.ensureDefaultStorageClass(ctx, profileFor(o.clusterType).storageClasses)
+}
+
+// ensureDefaultStorageClass makes the first existing storage class of the preferred ones
+// default unless the cluster already has a default storage class.
+// The default storage class is a cluster-wide setting so it is changed only
+// if SetDefaultStorageClass is enabled. The change is reverted by the uninstall command.
+func (o *Install) ensureDefaultStorageClass(ctx context.Context, preferred []string) error {
+	storageClasses, err := o.kubeClient.GetStorageClasses(ctx)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get storage classes"))
+	}
+	for _, sc := range storageClasses.Items {
+		if kubernetes.IsDefaultStorageClass(sc) {
+			o.l.Debugf("Using default storage class %s", sc.Name)
+			return nil
+		}
+	}
+
+	for _, name := range preferred {
+		if !slices.ContainsFunc(storageClasses.Items, func(sc storagev1.StorageClass) bool { return sc.Name == name }) {
+			continue
+		}
+		if !o.config.SetDefaultStorageClass {
+			o.l.Warnf("There is no default storage class. Run the installation with --set-default-storage-class "+
+				"to make %s storage class default or specify a storage class when creating database clusters", name)
+			return nil
+		}
+		o.l.Infof("Making %s storage class default. The change is reverted when Everest is uninstalled", name)
+		if err := o.kubeClient.SetDefaultStorageClass(ctx, name); err != nil {
+			return errors.Join(err, fmt.Errorf("could not make %s storage class default", name))
+		}
+		o.defaultStorageClass = name
+		return nil
+	}
+
+	o.l.Warn("There is no default storage class. Specify a storage class when creating database clusters")
+	return nil
+}
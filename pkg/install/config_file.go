@@ -0,0 +1,188 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/percona/percona-everest-cli/pkg/bundle"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// ErrConfigFileEmpty appears when the provided config file has no content.
+var ErrConfigFileEmpty = errors.New("config file is empty")
+
+type (
+	// ConfigFile describes the schema of the declarative configuration file
+	// accepted by install and upgrade commands.
+	// The keys match the names of the command line flags.
+	// JSON documents are accepted as well since JSON is a subset of YAML.
+	ConfigFile struct {
+		Kubeconfig             *string              `yaml:"kubeconfig"`
+		Namespaces             *string              `yaml:"namespaces"`
+		SkipWizard             *bool                `yaml:"skip-wizard"`
+		UpgradeOLM             *bool                `yaml:"upgrade-olm"`
+		CatalogImage           *string              `yaml:"catalog-image"`
+		DisableTelemetry       *bool                `yaml:"disable-telemetry"`
+		ManifestFile           *string              `yaml:"manifest-file"`
+		Bundle                 *string              `yaml:"bundle"`
+		RegistryMirror         []string             `yaml:"registry-mirror"`
+		ImagePullSecret        *string              `yaml:"image-pull-secret"`
+		InstallPlanApproval    *string              `yaml:"install-plan-approval"`
+		NamespaceOperators     *string              `yaml:"namespace-operators"`
+		EverestServiceType     *string              `yaml:"everest-service-type"`
+		UseExistingOLM         *bool                `yaml:"use-existing-olm"`
+		SetDefaultStorageClass *bool                `yaml:"set-default-storage-class"`
+		Operator               *ConfigFileOperators `yaml:"operator"`
+		Channel                *ConfigFileChannels  `yaml:"channel"`
+		OperatorVersion        *ConfigFileChannels  `yaml:"operator-version"`
+	}
+
+	// ConfigFileOperators describes the operator section of the config file.
+	ConfigFileOperators struct {
+		MongoDB       *bool `yaml:"mongodb"`
+		PostgreSQL    *bool `yaml:"postgresql"`
+		XtraDBCluster *bool `yaml:"xtradb-cluster"`
+	}
+
+	// ConfigFileChannels describes the channel and operator-version sections of the config file.
+	ConfigFileChannels struct {
+		Everest         *string `yaml:"everest"`
+		MongoDB         *string `yaml:"mongodb"`
+		PostgreSQL      *string `yaml:"postgresql"`
+		XtraDBCluster   *string `yaml:"xtradb-cluster"`
+		VictoriaMetrics *string `yaml:"victoria-metrics"`
+	}
+)
+
+// ValidateConfigFile reads the config file located at path
+// and validates it against the ConfigFile schema.
+func ValidateConfigFile(path string) (*ConfigFile, error) {
+	f, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not read config file"))
+	}
+
+	c, err := ParseConfigFile(f)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("invalid config file %s", path))
+	}
+
+	return c, nil
+}
+
+// ParseConfigFile decodes and validates the content of a config file.
+// Unknown keys and values of a wrong type are rejected.
+func ParseConfigFile(data []byte) (*ConfigFile, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrConfigFileEmpty
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	c := &ConfigFile{}
+	if err := dec.Decode(c); err != nil {
+		return nil, err
+	}
+	var extra any
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		return nil, errors.New("config file must contain a single document")
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *ConfigFile) validate() error {
+	if c.Namespaces != nil {
+		if _, err := ValidateNamespaces(*c.Namespaces); err != nil {
+			return err
+		}
+	}
+
+	if c.CatalogImage != nil && strings.TrimSpace(*c.CatalogImage) == "" {
+		return errors.New("catalog-image cannot be empty")
+	}
+
+	if c.ManifestFile != nil && c.Bundle != nil {
+		return bundle.ErrManifestAndBundle
+	}
+
+	if _, err := kubernetes.ParseRegistryMirror(c.RegistryMirror); err != nil {
+		return err
+	}
+
+	if c.InstallPlanApproval != nil {
+		if err := ValidateInstallPlanApproval(*c.InstallPlanApproval); err != nil {
+			return err
+		}
+	}
+
+	if c.EverestServiceType != nil {
+		if err := kubernetes.ValidateServiceType(*c.EverestServiceType); err != nil {
+			return err
+		}
+	}
+
+	if c.NamespaceOperators != nil {
+		if _, err := ParseNamespaceOperators(*c.NamespaceOperators); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Channel.validate("channel"); err != nil {
+		return err
+	}
+
+	if err := c.OperatorVersion.validate("operator-version"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validate checks that none of the values is empty.
+func (c *ConfigFileChannels) validate(section string) error {
+	if c == nil {
+		return nil
+	}
+
+	values := map[string]*string{
+		"everest":          c.Everest,
+		"mongodb":          c.MongoDB,
+		"postgresql":       c.PostgreSQL,
+		"xtradb-cluster":   c.XtraDBCluster,
+		"victoria-metrics": c.VictoriaMetrics,
+	}
+	for name, v := range values {
+		if v != nil && strings.TrimSpace(*v) == "" {
+			return fmt.Errorf("%s.%s cannot be empty", section, name)
+		}
+	}
+
+	return nil
+}
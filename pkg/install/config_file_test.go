@@ -0,0 +1,120 @@
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name    string
+		input   string
+		wantErr bool
+	}
+
+	tcases := []tcase{
+		{
+			name:    "empty",
+			input:   "  \n",
+			wantErr: true,
+		},
+		{
+			name: "valid yaml",
+			input: `
+namespaces: dev,prod
+skip-wizard: true
+catalog-image: docker.io/percona/everest-catalog:0.8.0
+disable-telemetry: true
+operator:
+  mongodb: true
+  postgresql: false
+  xtradb-cluster: true
+channel:
+  everest: fast-v0
+`,
+			wantErr: false,
+		},
+		{
+			name:    "valid json",
+			input:   `{"namespaces": "dev", "operator": {"mongodb": false}, "upgrade-olm": true}`,
+			wantErr: false,
+		},
+		{
+			name:    "unknown key",
+			input:   "namespace: dev\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown operator",
+			input:   "operator:\n  mysql: true\n",
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			input:   "skip-wizard: sometimes\n",
+			wantErr: true,
+		},
+		{
+			name:    "reserved namespace",
+			input:   "namespaces: everest-system\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty channel",
+			input:   "channel:\n  mongodb: \"\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "manifest file and bundle",
+			input:   "manifest-file: quickstart.yaml\nbundle: everest.tar.gz\n",
+			wantErr: true,
+		},
+		{
+			name:    "registry mirror",
+			input:   "registry-mirror:\n- docker.io=harbor.example.com/dockerhub\nimage-pull-secret: harbor\n",
+			wantErr: false,
+		},
+		{
+			name:    "invalid registry mirror",
+			input:   "registry-mirror:\n- docker.io\n",
+			wantErr: true,
+		},
+		{
+			name:    "operator version",
+			input:   "operator-version:\n  postgresql: 2.3.1\n",
+			wantErr: false,
+		},
+		{
+			name:    "empty operator version",
+			input:   "operator-version:\n  postgresql: \"\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid install plan approval",
+			input:   "install-plan-approval: sometimes\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid namespace operators",
+			input:   "namespace-operators: dev=mysql\n",
+			wantErr: true,
+		},
+		{
+			name:    "multiple documents",
+			input:   "namespaces: dev\n---\nnamespaces: prod\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseConfigFile([]byte(tc.input))
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
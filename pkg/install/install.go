@@ -21,9 +21,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/url"
-	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -34,8 +34,11 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
+	"github.com/percona/percona-everest-cli/pkg/bundle"
 	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
 	"github.com/percona/percona-everest-cli/pkg/token"
+	"github.com/percona/percona-everest-cli/pkg/version"
 )
 
 // Install implements the main logic for commands.
@@ -44,6 +47,18 @@ type Install struct {
 
 	config     Config
 	kubeClient *kubernetes.Kubernetes
+	registry   kubernetes.RegistryConfig
+	// startingCSVs maps operator names to the CSVs they are pinned to.
+	startingCSVs map[string]string
+	// namespaceOperators overrides the operators installed into the mapped namespaces.
+	namespaceOperators NamespaceOperators
+	// clusterType is the detected type of the cluster.
+	clusterType kubernetes.ClusterType
+	// explicitChannels stores the channels provided by the user.
+	// Only these channels are switched in existing subscriptions.
+	explicitChannels ChannelConfig
+	// defaultStorageClass is the storage class made default by the installation.
+	defaultStorageClass string
 }
 
 const (
@@ -76,20 +91,22 @@ const (
 	// dbsOperatorGroup is the name of the database operator group.
 	dbsOperatorGroup = "everest-databases"
 
-	// SystemNamespace is the namespace where everest is installed.
+	// SystemNamespace is the default namespace where everest is installed.
 	SystemNamespace = "everest-system"
-	// MonitoringNamespace is the namespace where the monitoring stack is installed.
+	// MonitoringNamespace is the default namespace where the monitoring stack is installed.
 	MonitoringNamespace = "everest-monitoring"
 	// EverestMonitoringNamespaceEnvVar is the name of the environment variable that holds the monitoring namespace.
 	EverestMonitoringNamespaceEnvVar = "MONITORING_NAMESPACE"
-	// disableTelemetryEnvVar is the name of the environment variable that disables telemetry.
-	disableTelemetryEnvVar = "DISABLE_TELEMETRY"
+	// DisableTelemetryEnvVar is the name of the environment variable that disables telemetry.
+	DisableTelemetryEnvVar = "DISABLE_TELEMETRY"
 )
 
 //nolint:gochecknoglobals
 var (
 	// ErrNSEmpty appears when the provided list of the namespaces is considered empty.
 	ErrNSEmpty = errors.New("namespace list is empty. Specify at least one namespace")
+	// ErrLastNamespace appears when all the namespaces managed by Everest are about to be removed.
+	ErrLastNamespace = errors.New("at least one namespace has to stay managed by Everest. Use `everestctl uninstall` to remove Everest")
 	// ErrNSReserved appears when some of the provided names are forbidden to use.
 	ErrNSReserved = func(ns string) error {
 		return fmt.Errorf("'%s' namespace is reserved for Everest internals. Please specify another namespace", ns)
@@ -113,8 +130,57 @@ type (
 		SkipWizard bool `mapstructure:"skip-wizard"`
 		// KubeconfigPath is a path to a kubeconfig
 		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// CatalogImage is the image of the Everest OLM catalog.
+		// If empty, the image matching the CLI version is used.
+		CatalogImage string `mapstructure:"catalog-image"`
+		// DisableTelemetry disables telemetry in the installed operators.
+		DisableTelemetry bool `mapstructure:"disable-telemetry"`
+		// DryRun records the changes instead of applying them to the cluster.
+		DryRun bool `mapstructure:"dry-run"`
+		// RenderOnly is a path to a directory the manifests are written to
+		// instead of being applied to the cluster.
+		RenderOnly string `mapstructure:"render-only"`
+		// ManifestFile is a path to a local Everest manifest
+		// used instead of downloading it.
+		ManifestFile string `mapstructure:"manifest-file"`
+		// Bundle is a path to an offline installation bundle
+		// created by `everestctl bundle create`.
+		Bundle string `mapstructure:"bundle"`
+		// RegistryMirror is a list of "source=mirror" pairs used to
+		// rewrite the images to a private registry.
+		RegistryMirror []string `mapstructure:"registry-mirror"`
+		// ImagePullSecret is the name of the secret used to pull the images.
+		ImagePullSecret string `mapstructure:"image-pull-secret"`
+		// InstallPlanApproval is the approval policy of the install plans.
+		InstallPlanApproval string `mapstructure:"install-plan-approval"`
+		// Resume skips the steps completed by a previous installation attempt.
+		Resume bool `mapstructure:"resume"`
+		// SkipPreflight skips the checks of the cluster run before the installation.
+		SkipPreflight bool `mapstructure:"skip-preflight"`
+		// NamespaceOperators is a raw mapping of namespaces to the operators
+		// installed into them such as "dev=pg,mongodb;prod=pxc".
+		// Namespaces missing in the mapping get the operators of Operator.
+		NamespaceOperators string `mapstructure:"namespace-operators"`
+		// EverestServiceType is the type of the everest service.
+		// If empty, the type suitable for the detected cluster type is used.
+		EverestServiceType string `mapstructure:"everest-service-type"`
+		// UseExistingOLM makes Everest use the OLM already installed in the cluster
+		// instead of installing the one bundled with the CLI.
+		UseExistingOLM bool `mapstructure:"use-existing-olm"`
+		// SetDefaultStorageClass allows the installation to make a storage class
+		// the default one if the cluster has no default storage class.
+		SetDefaultStorageClass bool `mapstructure:"set-default-storage-class"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// MonitoringNamespace is the namespace where the monitoring stack is installed.
+		MonitoringNamespace string `mapstructure:"monitoring-namespace"`
+		// OLMNamespace is the namespace where OLM is installed.
+		// It is ignored if the existing OLM is used.
+		OLMNamespace string `mapstructure:"olm-namespace"`
 
 		Operator OperatorConfig
+		Channel  ChannelConfig
+		Version  VersionConfig `mapstructure:"operator-version"`
 	}
 
 	// OperatorConfig identifies which operators shall be installed.
@@ -126,6 +192,21 @@ type (
 		// PXC stores if XtraDB Cluster shall be installed.
 		PXC bool `mapstructure:"xtradb-cluster"`
 	}
+
+	// ChannelConfig stores the OLM channels the operators are subscribed to.
+	// Empty values are replaced with the default channels.
+	ChannelConfig struct {
+		// Everest stores the channel of the Everest operator.
+		Everest string `mapstructure:"everest"`
+		// PG stores the channel of the PostgreSQL operator.
+		PG string `mapstructure:"postgresql"`
+		// PSMDB stores the channel of the MongoDB operator.
+		PSMDB string `mapstructure:"mongodb"`
+		// PXC stores the channel of the XtraDB Cluster operator.
+		PXC string `mapstructure:"xtradb-cluster"`
+		// VictoriaMetrics stores the channel of the VictoriaMetrics operator.
+		VictoriaMetrics string `mapstructure:"victoria-metrics"`
+	}
 )
 
 // NewInstall returns a new Install struct.
@@ -134,61 +215,119 @@ func NewInstall(c Config, l *zap.SugaredLogger) (*Install, error) {
 		config: c,
 		l:      l.With("component", "install"),
 	}
+	if err := cli.config.setNamespaceDefaults(); err != nil {
+		return nil, err
+	}
 
-	k, err := kubernetes.New(c.KubeconfigPath, cli.l)
+	b, err := bundle.Load(c.Bundle, c.ManifestFile)
 	if err != nil {
-		var u *url.Error
-		if errors.As(err, &u) {
-			cli.l.Error("Could not connect to Kubernetes. " +
-				"Make sure Kubernetes is running and is accessible from this computer/server.")
-		}
 		return nil, err
 	}
+	if b != nil && cli.config.CatalogImage == "" {
+		cli.config.CatalogImage = b.Metadata.CatalogImage
+	}
+	cli.registry, err = kubernetes.NewRegistryConfig(c.RegistryMirror, c.ImagePullSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.RenderOnly != "" {
+		// The cluster is not contacted when rendering manifests.
+		cli.kubeClient = kubernetes.NewEmpty(cli.l)
+		cli.kubeClient.SetOLMNamespace(cli.config.OLMNamespace)
+		cli.setManifest(b)
+		return cli, nil
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	if c.DryRun {
+		k.EnableDryRun()
+	}
+	k.SetRegistry(cli.registry)
+	k.SetOLMNamespace(cli.config.OLMNamespace)
 	cli.kubeClient = k
+	cli.setManifest(b)
 	return cli, nil
 }
 
+// setManifest makes the installation use the manifest of the bundle if provided.
+func (o *Install) setManifest(b *bundle.Bundle) {
+	if b == nil {
+		return
+	}
+	o.l.Debug("Using the Everest manifest from a local file")
+	o.kubeClient.SetManifest(b.Manifest)
+}
+
+// Plan returns the changes recorded in dry-run mode.
+func (o *Install) Plan() *client.Plan {
+	return o.kubeClient.DryRunPlan()
+}
+
 // Run runs the operators installation process.
 func (o *Install) Run(ctx context.Context) error {
 	if err := o.populateConfig(); err != nil {
 		return err
 	}
 
-	if err := o.provisionOLM(ctx); err != nil {
-		return err
+	if o.config.RenderOnly != "" {
+		return o.render(ctx)
 	}
 
-	if err := o.provisionMonitoringStack(ctx); err != nil {
+	if err := o.detectClusterType(ctx); err != nil {
 		return err
 	}
-
-	if err := o.provisionDBNamespaces(ctx); err != nil {
+	if !o.config.SkipPreflight {
+		if err := o.runPreflight(ctx); err != nil {
+			return err
+		}
+	}
+	if err := o.detectOLM(ctx); err != nil {
 		return err
 	}
-
-	if err := o.provisionEverestOperator(ctx); err != nil {
+	if err := o.provisionStorageClass(ctx); err != nil {
+		return err
+	}
+	if err := o.checkPullSecret(ctx); err != nil {
 		return err
 	}
 
-	if err := o.provisionEverest(ctx); err != nil {
+	state, err := o.initState(ctx)
+	if err != nil {
 		return err
 	}
-	_, err := o.kubeClient.GetSecret(ctx, token.SecretName, SystemNamespace)
-	if err != nil && !k8serrors.IsNotFound(err) {
-		return errors.Join(err, errors.New("could not get the everest token secret"))
+
+	return o.runSteps(ctx, o.steps(), state)
+}
+
+func (o *Install) populateConfig() error {
+	o.setChannelDefaults()
+	if o.config.CatalogImage == "" {
+		o.config.CatalogImage = version.CatalogImage()
 	}
-	if err != nil && k8serrors.IsNotFound(err) {
-		pwd, err := o.generateToken(ctx)
-		if err != nil {
+	if o.config.InstallPlanApproval == "" {
+		o.config.InstallPlanApproval = InstallPlanApprovalManual
+	}
+	if err := ValidateInstallPlanApproval(o.config.InstallPlanApproval); err != nil {
+		return err
+	}
+	if o.config.EverestServiceType != "" {
+		if err := kubernetes.ValidateServiceType(o.config.EverestServiceType); err != nil {
 			return err
 		}
-		o.l.Info("\n" + pwd.String() + "\n\n")
+	}
+	m, err := ParseNamespaceOperators(o.config.NamespaceOperators)
+	if err != nil {
+		return err
+	}
+	o.namespaceOperators = m
+	if o.config.Namespaces == "" {
+		o.config.Namespaces = strings.Join(m.Namespaces(), ",")
 	}
 
-	return nil
-}
-
-func (o *Install) populateConfig() error {
 	if !o.config.SkipWizard {
 		if err := o.runWizard(); err != nil {
 			return err
@@ -199,28 +338,22 @@ func (o *Install) populateConfig() error {
 	if err != nil {
 		return err
 	}
+	if err := o.config.validateDBNamespaces(l); err != nil {
+		return err
+	}
 	o.config.NamespacesList = l
 
-	return nil
+	return o.namespaceOperators.validate(l)
 }
 
 func (o *Install) installVMOperator(ctx context.Context) error {
 	o.l.Info("Creating operator group for everest")
-	if err := o.kubeClient.CreateOperatorGroup(ctx, monitoringOperatorGroup, MonitoringNamespace, []string{}); err != nil {
+	if err := o.kubeClient.CreateOperatorGroup(ctx, monitoringOperatorGroup, o.config.MonitoringNamespace, []string{}); err != nil {
 		return err
 	}
 	o.l.Infof("Installing %s operator", vmOperatorName)
 
-	params := kubernetes.InstallOperatorRequest{
-		Namespace:              MonitoringNamespace,
-		Name:                   vmOperatorName,
-		OperatorGroup:          monitoringOperatorGroup,
-		CatalogSource:          catalogSource,
-		CatalogSourceNamespace: kubernetes.OLMNamespace,
-		Channel:                vmOperatorChannel,
-		InstallPlanApproval:    v1alpha1.ApprovalManual,
-	}
-
+	params := o.vmOperatorRequest()
 	if err := o.kubeClient.InstallOperator(ctx, params); err != nil {
 		o.l.Errorf("failed installing %s operator", vmOperatorName)
 		return err
@@ -229,9 +362,28 @@ func (o *Install) installVMOperator(ctx context.Context) error {
 	return nil
 }
 
+// vmOperatorRequest returns a request to install the VictoriaMetrics operator.
+func (o *Install) vmOperatorRequest() kubernetes.InstallOperatorRequest {
+	req := kubernetes.InstallOperatorRequest{
+		Namespace:              o.config.MonitoringNamespace,
+		Name:                   vmOperatorName,
+		OperatorGroup:          monitoringOperatorGroup,
+		CatalogSource:          catalogSource,
+		CatalogSourceNamespace: o.kubeClient.GetOLMNamespace(),
+		Channel:                o.config.Channel.VictoriaMetrics,
+		SwitchChannel:          o.explicitChannels.VictoriaMetrics != "",
+		StartingCSV:            o.startingCSVs[vmOperatorName],
+	}
+	o.setApproval(&req)
+	return req
+}
+
 func (o *Install) provisionMonitoringStack(ctx context.Context) error {
 	l := o.l.With("action", "monitoring")
-	if err := o.createNamespace(MonitoringNamespace); err != nil {
+	if err := o.createNamespace(o.config.MonitoringNamespace); err != nil {
+		return err
+	}
+	if err := o.provisionPullSecret(ctx, o.config.MonitoringNamespace); err != nil {
 		return err
 	}
 
@@ -239,7 +391,7 @@ func (o *Install) provisionMonitoringStack(ctx context.Context) error {
 	if err := o.installVMOperator(ctx); err != nil {
 		return err
 	}
-	if err := o.kubeClient.ProvisionMonitoring(MonitoringNamespace); err != nil {
+	if err := o.kubeClient.ProvisionMonitoring(o.config.MonitoringNamespace); err != nil {
 		return errors.Join(err, errors.New("could not provision monitoring configuration"))
 	}
 
@@ -248,16 +400,16 @@ func (o *Install) provisionMonitoringStack(ctx context.Context) error {
 }
 
 func (o *Install) provisionEverestOperator(ctx context.Context) error {
-	if err := o.createNamespace(SystemNamespace); err != nil {
+	if err := o.createNamespace(o.config.SystemNamespace); err != nil {
 		return err
 	}
 
 	o.l.Info("Creating operator group for everest")
-	if err := o.kubeClient.CreateOperatorGroup(ctx, systemOperatorGroup, SystemNamespace, o.config.NamespacesList); err != nil {
+	if err := o.kubeClient.CreateOperatorGroup(ctx, systemOperatorGroup, o.config.SystemNamespace, o.config.NamespacesList); err != nil {
 		return err
 	}
 
-	if err := o.installOperator(ctx, everestOperatorChannel, everestOperatorName, SystemNamespace)(); err != nil {
+	if err := o.installOperator(ctx, o.config.Channel.Everest, everestOperatorName, o.config.SystemNamespace)(); err != nil {
 		return err
 	}
 
@@ -265,7 +417,7 @@ func (o *Install) provisionEverestOperator(ctx context.Context) error {
 }
 
 func (o *Install) provisionEverest(ctx context.Context) error {
-	d, err := o.kubeClient.GetDeployment(ctx, kubernetes.PerconaEverestDeploymentName, SystemNamespace)
+	d, err := o.kubeClient.GetDeployment(ctx, kubernetes.PerconaEverestDeploymentName, o.config.SystemNamespace)
 	var everestExists bool
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return err
@@ -275,62 +427,193 @@ func (o *Install) provisionEverest(ctx context.Context) error {
 	}
 
 	if !everestExists {
-		o.l.Info(fmt.Sprintf("Deploying Everest to %s", SystemNamespace))
-		err = o.kubeClient.InstallEverest(ctx, SystemNamespace)
+		o.l.Info(fmt.Sprintf("Deploying Everest to %s", o.config.SystemNamespace))
+		err = o.kubeClient.InstallEverest(ctx, o.config.SystemNamespace)
 		if err != nil {
 			return err
 		}
 	} else {
 		o.l.Info("Restarting Everest")
-		if err := o.kubeClient.RestartEverest(ctx, everestOperatorName, SystemNamespace); err != nil {
+		if err := o.kubeClient.RestartEverest(ctx, everestOperatorName, o.config.SystemNamespace); err != nil {
 			return err
 		}
-		if err := o.kubeClient.RestartEverest(ctx, everestBackendServiceName, SystemNamespace); err != nil {
+		if err := o.kubeClient.RestartEverest(ctx, everestBackendServiceName, o.config.SystemNamespace); err != nil {
 			return err
 		}
 	}
 
 	o.l.Info("Updating cluster role bindings for everest-admin")
-	if err := o.kubeClient.UpdateClusterRoleBinding(ctx, everestServiceAccountClusterRoleBinding, o.config.NamespacesList); err != nil {
+	if err := o.kubeClient.UpdateClusterRoleBinding(ctx, EverestClusterRoleBindingName(o.config.SystemNamespace), o.config.NamespacesList); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// EverestClusterRoleBindingName returns the name of the everest-admin cluster role binding
+// of the installation in the system namespace.
+func EverestClusterRoleBindingName(systemNamespace string) string {
+	return client.ClusterRoleBindingName(everestServiceAccountClusterRoleBinding, systemNamespace)
+}
+
 func (o *Install) provisionDBNamespaces(ctx context.Context) error {
 	for _, namespace := range o.config.NamespacesList {
-		namespace := namespace
-		if err := o.createNamespace(namespace); err != nil {
+		if err := o.provisionDBNamespace(ctx, namespace); err != nil {
 			return err
 		}
-		if err := o.kubeClient.CreateOperatorGroup(ctx, dbsOperatorGroup, namespace, []string{}); err != nil {
-			return err
+	}
+
+	return nil
+}
+
+// provisionDBNamespace installs the database operators and the Everest role into the namespace.
+func (o *Install) provisionDBNamespace(ctx context.Context, namespace string) error {
+	if err := o.createNamespace(namespace); err != nil {
+		return err
+	}
+	if err := o.provisionPullSecret(ctx, namespace); err != nil {
+		return err
+	}
+	if err := o.kubeClient.CreateOperatorGroup(ctx, dbsOperatorGroup, namespace, []string{}); err != nil {
+		return err
+	}
+
+	o.l.Infof("Installing operators into %s namespace", namespace)
+	if err := o.provisionOperators(ctx, namespace); err != nil {
+		return err
+	}
+	o.l.Info("Creating role for the Everest service account")
+	err := o.kubeClient.CreateRole(namespace, everestServiceAccountRole, o.serviceAccountRolePolicyRules())
+	if err != nil {
+		return errors.Join(err, errors.New("could not create role"))
+	}
+
+	o.l.Info("Binding role to the Everest Service account")
+	err = o.kubeClient.CreateRoleBinding(
+		namespace,
+		everestServiceAccountRoleBinding,
+		everestServiceAccountRole,
+		everestServiceAccount,
+	)
+	if err != nil {
+		return errors.Join(err, errors.New("could not create role binding"))
+	}
+
+	return nil
+}
+
+// channelField is a channel of ChannelConfig.
+type channelField struct {
+	// key is the mapstructure key of the channel.
+	key     string
+	channel *string
+	value   string
+}
+
+// fields returns the channels with their default values.
+func (c *ChannelConfig) fields() []channelField {
+	return []channelField{
+		{"everest", &c.Everest, everestOperatorChannel},
+		{"postgresql", &c.PG, pgOperatorChannel},
+		{"mongodb", &c.PSMDB, psmdbOperatorChannel},
+		{"xtradb-cluster", &c.PXC, pxcOperatorChannel},
+		{"victoria-metrics", &c.VictoriaMetrics, vmOperatorChannel},
+	}
+}
+
+// setDefaults fills empty channels with the default ones.
+func (c *ChannelConfig) setDefaults() {
+	for _, d := range c.fields() {
+		if *d.channel == "" {
+			*d.channel = d.value
 		}
+	}
+}
 
-		o.l.Infof("Installing operators into %s namespace", namespace)
-		if err := o.provisionOperators(ctx, namespace); err != nil {
-			return err
+// String returns the non-empty channels in the format accepted by parseChannelConfig
+// such as "everest=stable-v0,postgresql=stable-v2".
+func (c ChannelConfig) String() string {
+	entries := []string{}
+	for _, f := range c.fields() {
+		if *f.channel != "" {
+			entries = append(entries, f.key+"="+*f.channel)
 		}
-		o.l.Info("Creating role for the Everest service account")
-		err := o.kubeClient.CreateRole(namespace, everestServiceAccountRole, o.serviceAccountRolePolicyRules())
-		if err != nil {
-			return errors.Join(err, errors.New("could not create role"))
+	}
+	return strings.Join(entries, ",")
+}
+
+// parseChannelConfig parses the channels returned by ChannelConfig.String.
+func parseChannelConfig(str string) (ChannelConfig, error) {
+	c := ChannelConfig{}
+	fields := c.fields()
+	for _, entry := range strings.Split(str, ",") {
+		if entry == "" {
+			continue
+		}
+		key, channel, _ := strings.Cut(entry, "=")
+		i := slices.IndexFunc(fields, func(f channelField) bool { return f.key == key })
+		if i == -1 {
+			return ChannelConfig{}, fmt.Errorf("unknown operator channel %q", entry)
 		}
+		*fields[i].channel = channel
+	}
+	return c, nil
+}
+
+// setChannelDefaults remembers the channels provided by the user
+// and fills the others with the default ones.
+func (o *Install) setChannelDefaults() {
+	o.explicitChannels = o.config.Channel
+	o.config.Channel.setDefaults()
+}
+
+// channel returns the channel of the operator.
+func (c ChannelConfig) channel(operator string) string {
+	switch operator {
+	case everestOperatorName:
+		return c.Everest
+	case pxcOperatorName:
+		return c.PXC
+	case psmdbOperatorName:
+		return c.PSMDB
+	case pgOperatorName:
+		return c.PG
+	case vmOperatorName:
+		return c.VictoriaMetrics
+	}
+	return ""
+}
 
-		o.l.Info("Binding role to the Everest Service account")
-		err = o.kubeClient.CreateRoleBinding(
-			namespace,
-			everestServiceAccountRoleBinding,
-			everestServiceAccountRole,
-			everestServiceAccount,
+// OperatorChannel is the channel of an operator subscription.
+type OperatorChannel struct {
+	Namespace string
+	Operator  string
+	Channel   string
+}
+
+// OperatorChannels returns the channels of the operator subscriptions
+// in the system, monitoring and the provided database namespaces.
+// Operators with an empty channel are omitted.
+func (c ChannelConfig) OperatorChannels(systemNamespace, monitoringNamespace string, dbNamespaces []string) []OperatorChannel {
+	res := []OperatorChannel{
+		{systemNamespace, everestOperatorName, c.Everest},
+		{monitoringNamespace, vmOperatorName, c.VictoriaMetrics},
+	}
+	for _, ns := range dbNamespaces {
+		res = append(res,
+			OperatorChannel{ns, pxcOperatorName, c.PXC},
+			OperatorChannel{ns, psmdbOperatorName, c.PSMDB},
+			OperatorChannel{ns, pgOperatorName, c.PG},
 		)
-		if err != nil {
-			return errors.Join(err, errors.New("could not create role binding"))
-		}
 	}
 
-	return nil
+	filtered := make([]OperatorChannel, 0, len(res))
+	for _, ch := range res {
+		if ch.Channel != "" {
+			filtered = append(filtered, ch)
+		}
+	}
+	return filtered
 }
 
 // runWizard runs installation wizard.
@@ -426,15 +709,53 @@ func (o *Install) createNamespace(namespace string) error {
 	return nil
 }
 
+// checkPullSecret checks the image pull secret exists in the system namespace
+// where it is copied from into the other namespaces.
+func (o *Install) checkPullSecret(ctx context.Context) error {
+	if o.registry.PullSecret == "" {
+		return nil
+	}
+	_, err := o.kubeClient.GetSecret(ctx, o.registry.PullSecret, o.config.SystemNamespace)
+	if err != nil && k8serrors.IsNotFound(err) {
+		return fmt.Errorf("image pull secret %q not found in %s namespace. "+
+			"Create it in the system namespace before the installation", o.registry.PullSecret, o.config.SystemNamespace)
+	}
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the image pull secret"))
+	}
+	return nil
+}
+
+// provisionPullSecret copies the image pull secret from the system namespace into the namespace.
+func (o *Install) provisionPullSecret(ctx context.Context, namespace string) error {
+	if o.registry.PullSecret == "" {
+		return nil
+	}
+	o.l.Infof("Copying image pull secret %s to %s namespace", o.registry.PullSecret, namespace)
+	if err := o.kubeClient.CopySecret(ctx, o.registry.PullSecret, o.config.SystemNamespace, namespace); err != nil {
+		return errors.Join(err, fmt.Errorf("could not copy image pull secret %s from %s namespace",
+			o.registry.PullSecret, o.config.SystemNamespace))
+	}
+	return nil
+}
+
 func (o *Install) provisionOLM(ctx context.Context) error {
-	o.l.Info("Installing Operator Lifecycle Manager")
-	if err := o.kubeClient.InstallOLMOperator(ctx, false); err != nil {
-		o.l.Error("failed installing OLM")
+	if o.config.UseExistingOLM {
+		o.l.Infof("Skipping the installation of Operator Lifecycle Manager. Using the one in %s namespace",
+			o.kubeClient.GetOLMNamespace())
+	} else {
+		o.l.Info("Installing Operator Lifecycle Manager")
+		if err := o.kubeClient.InstallOLMOperator(ctx, false); err != nil {
+			o.l.Error("failed installing OLM")
+			return err
+		}
+		o.l.Info("OLM has been installed")
+	}
+	if err := o.provisionPullSecret(ctx, o.kubeClient.GetOLMNamespace()); err != nil {
 		return err
 	}
-	o.l.Info("OLM has been installed")
 	o.l.Info("Installing Percona OLM Catalog")
-	if err := o.kubeClient.InstallPerconaCatalog(ctx); err != nil {
+	if err := o.kubeClient.InstallPerconaCatalog(ctx, o.config.CatalogImage); err != nil {
 		o.l.Errorf("failed installing OLM catalog: %v", err)
 		return err
 	}
@@ -451,14 +772,8 @@ func (o *Install) provisionOperators(ctx context.Context, namespace string) erro
 	// The limit can be removed after it's refactored.
 	g.SetLimit(operatorInstallThreads)
 
-	if o.config.Operator.PXC {
-		g.Go(o.installOperator(gCtx, pxcOperatorChannel, pxcOperatorName, namespace))
-	}
-	if o.config.Operator.PSMDB {
-		g.Go(o.installOperator(gCtx, psmdbOperatorChannel, psmdbOperatorName, namespace))
-	}
-	if o.config.Operator.PG {
-		g.Go(o.installOperator(gCtx, pgOperatorChannel, pgOperatorName, namespace))
+	for _, op := range o.dbOperators(namespace) {
+		g.Go(o.installOperator(gCtx, op.channel, op.name, namespace))
 	}
 	if err := g.Wait(); err != nil {
 		return err
@@ -477,42 +792,7 @@ func (o *Install) installOperator(ctx context.Context, channel, operatorName, na
 
 		o.l.Infof("Installing %s operator", operatorName)
 
-		disableTelemetry, ok := os.LookupEnv(disableTelemetryEnvVar)
-		if !ok || disableTelemetry != "true" {
-			disableTelemetry = "false"
-		}
-
-		params := kubernetes.InstallOperatorRequest{
-			Namespace:              namespace,
-			Name:                   operatorName,
-			OperatorGroup:          systemOperatorGroup,
-			CatalogSource:          catalogSource,
-			CatalogSourceNamespace: kubernetes.OLMNamespace,
-			Channel:                channel,
-			InstallPlanApproval:    v1alpha1.ApprovalManual,
-			SubscriptionConfig: &v1alpha1.SubscriptionConfig{
-				Env: []corev1.EnvVar{
-					{
-						Name:  disableTelemetryEnvVar,
-						Value: disableTelemetry,
-					},
-				},
-			},
-		}
-		if operatorName == everestOperatorName {
-			params.TargetNamespaces = o.config.NamespacesList
-			params.SubscriptionConfig.Env = append(params.SubscriptionConfig.Env, []corev1.EnvVar{
-				{
-					Name:  EverestMonitoringNamespaceEnvVar,
-					Value: MonitoringNamespace,
-				},
-				{
-					Name:  kubernetes.EverestDBNamespacesEnvVar,
-					Value: strings.Join(o.config.NamespacesList, ","),
-				},
-			}...)
-		}
-
+		params := o.operatorRequest(channel, operatorName, namespace)
 		if err := o.kubeClient.InstallOperator(ctx, params); err != nil {
 			o.l.Errorf("failed installing %s operator", operatorName)
 			return err
@@ -523,6 +803,44 @@ func (o *Install) installOperator(ctx context.Context, channel, operatorName, na
 	}
 }
 
+// operatorRequest returns a request to install the operator in the namespace.
+func (o *Install) operatorRequest(channel, operatorName, namespace string) kubernetes.InstallOperatorRequest {
+	params := kubernetes.InstallOperatorRequest{
+		Namespace:              namespace,
+		Name:                   operatorName,
+		OperatorGroup:          systemOperatorGroup,
+		CatalogSource:          catalogSource,
+		CatalogSourceNamespace: o.kubeClient.GetOLMNamespace(),
+		Channel:                channel,
+		SwitchChannel:          o.explicitChannels.channel(operatorName) != "",
+		StartingCSV:            o.startingCSVs[operatorName],
+		SubscriptionConfig: &v1alpha1.SubscriptionConfig{
+			Env: []corev1.EnvVar{
+				{
+					Name:  DisableTelemetryEnvVar,
+					Value: strconv.FormatBool(o.config.DisableTelemetry),
+				},
+			},
+		},
+	}
+	o.setApproval(&params)
+	if operatorName == everestOperatorName {
+		params.TargetNamespaces = o.config.NamespacesList
+		params.SubscriptionConfig.Env = append(params.SubscriptionConfig.Env, []corev1.EnvVar{
+			{
+				Name:  EverestMonitoringNamespaceEnvVar,
+				Value: o.config.MonitoringNamespace,
+			},
+			{
+				Name:  kubernetes.EverestDBNamespacesEnvVar,
+				Value: strings.Join(o.config.NamespacesList, ","),
+			},
+		}...)
+	}
+
+	return params
+}
+
 func (o *Install) serviceAccountRolePolicyRules() []rbacv1.PolicyRule {
 	return []rbacv1.PolicyRule{
 		{
@@ -576,16 +894,14 @@ func (o *Install) serviceAccountRolePolicyRules() []rbacv1.PolicyRule {
 func (o *Install) generateToken(ctx context.Context) (*token.ResetResponse, error) {
 	o.l.Info("Creating token for Everest")
 
-	r, err := token.NewReset(
+	r := token.NewResetWithClient(
 		token.ResetConfig{
 			KubeconfigPath: o.config.KubeconfigPath,
-			Namespace:      SystemNamespace,
+			Namespace:      o.config.SystemNamespace,
 		},
+		o.kubeClient,
 		o.l,
 	)
-	if err != nil {
-		return nil, errors.Join(err, errors.New("could not initialize reset token"))
-	}
 
 	res, err := r.Run(ctx)
 	if err != nil {
@@ -627,6 +943,66 @@ func ValidateNamespaces(str string) ([]string, error) {
 	return list, nil
 }
 
+// AllowedNamespaces parses the comma-separated namespaces allowed to use a backup storage
+// or a monitoring instance and checks they are managed by Everest.
+// An empty list allows all the namespaces.
+func AllowedNamespaces(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace, namespaces string) ([]string, error) {
+	if strings.TrimSpace(namespaces) == "" {
+		return nil, nil
+	}
+	list, err := ValidateNamespaces(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	managed, err := k.GetDBNamespaces(ctx, systemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+	for _, ns := range list {
+		if !slices.Contains(managed, ns) {
+			return nil, fmt.Errorf("namespace %s is not managed by Everest", ns)
+		}
+	}
+	slices.Sort(list)
+	return list, nil
+}
+
+// setNamespaceDefaults fills the empty Everest namespaces with the default ones
+// and validates them.
+func (c *Config) setNamespaceDefaults() error {
+	defaults := []struct {
+		namespace *string
+		value     string
+	}{
+		{&c.SystemNamespace, SystemNamespace},
+		{&c.MonitoringNamespace, MonitoringNamespace},
+		{&c.OLMNamespace, kubernetes.OLMNamespace},
+	}
+	for _, d := range defaults {
+		if *d.namespace == "" {
+			*d.namespace = d.value
+		}
+		if err := validateRFC1035(*d.namespace); err != nil {
+			return err
+		}
+	}
+	if c.SystemNamespace == c.MonitoringNamespace {
+		return errors.New("system and monitoring namespaces must differ")
+	}
+	return nil
+}
+
+// validateDBNamespaces makes sure the database namespaces
+// do not clash with the Everest namespaces.
+func (c Config) validateDBNamespaces(namespaces []string) error {
+	for _, ns := range namespaces {
+		if ns == c.SystemNamespace || ns == c.MonitoringNamespace || ns == c.OLMNamespace {
+			return ErrNSReserved(ns)
+		}
+	}
+	return nil
+}
+
 // validates names to be RFC-1035 compatible  https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#rfc-1035-label-names
 func validateRFC1035(s string) error {
 	rfc1035Regex := "^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$"
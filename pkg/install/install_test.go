@@ -4,6 +4,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
 )
 
 func TestValidateNamespaces(t *testing.T) {
@@ -96,3 +100,95 @@ func TestValidateNamespaces(t *testing.T) {
 		})
 	}
 }
+
+func TestOperatorChannels(t *testing.T) {
+	t.Parallel()
+
+	c := ChannelConfig{Everest: "fast-v0", PG: "stable-v2"}
+	assert.Equal(t, []OperatorChannel{
+		{SystemNamespace, everestOperatorName, "fast-v0"},
+		{"dev", pgOperatorName, "stable-v2"},
+		{"prod", pgOperatorName, "stable-v2"},
+	}, c.OperatorChannels(SystemNamespace, MonitoringNamespace, []string{"dev", "prod"}))
+}
+
+func TestSwitchExplicitChannelsOnly(t *testing.T) {
+	t.Parallel()
+
+	o := &Install{
+		config:     Config{Channel: ChannelConfig{PXC: "fast-v1"}},
+		kubeClient: kubernetes.NewEmpty(zap.NewNop().Sugar()),
+	}
+	o.setChannelDefaults()
+
+	pxc := o.operatorRequest(o.config.Channel.PXC, pxcOperatorName, "dev")
+	assert.Equal(t, "fast-v1", pxc.Channel)
+	assert.True(t, pxc.SwitchChannel)
+
+	pg := o.operatorRequest(o.config.Channel.PG, pgOperatorName, "dev")
+	assert.Equal(t, pgOperatorChannel, pg.Channel)
+	assert.False(t, pg.SwitchChannel)
+	assert.False(t, o.vmOperatorRequest().SwitchChannel)
+}
+
+func TestNamespacesSets(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name     string
+		current  []string
+		changed  []string
+		merged   []string
+		subtract []string
+	}
+	tcases := []tcase{
+		{
+			name:     "disjoint",
+			current:  []string{"prod", "dev"},
+			changed:  []string{"staging"},
+			merged:   []string{"dev", "prod", "staging"},
+			subtract: []string{"dev", "prod"},
+		},
+		{
+			name:     "overlapping",
+			current:  []string{"prod", "dev", ""},
+			changed:  []string{"dev"},
+			merged:   []string{"dev", "prod"},
+			subtract: []string{"prod"},
+		},
+		{
+			name:     "all",
+			current:  []string{"dev"},
+			changed:  []string{"dev"},
+			merged:   []string{"dev"},
+			subtract: []string{},
+		},
+	}
+
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.merged, mergeNamespaces(tc.current, tc.changed))
+			assert.Equal(t, tc.subtract, subtractNamespaces(tc.current, tc.changed))
+		})
+	}
+}
+
+func TestNamespaceDefaults(t *testing.T) {
+	t.Parallel()
+
+	c := Config{SystemNamespace: "tenant-a"}
+	require.NoError(t, c.setNamespaceDefaults())
+	assert.Equal(t, "tenant-a", c.SystemNamespace)
+	assert.Equal(t, MonitoringNamespace, c.MonitoringNamespace)
+	assert.Equal(t, kubernetes.OLMNamespace, c.OLMNamespace)
+	require.ErrorContains(t, c.validateDBNamespaces([]string{"dev", "tenant-a"}), "reserved")
+	require.NoError(t, c.validateDBNamespaces([]string{"dev"}))
+
+	c = Config{SystemNamespace: "tenant-a", MonitoringNamespace: "tenant-a"}
+	require.Error(t, c.setNamespaceDefaults())
+
+	c = Config{OLMNamespace: "Invalid"}
+	require.Error(t, c.setNamespaceDefaults())
+}
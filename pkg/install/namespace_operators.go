@@ -0,0 +1,207 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+const stateKeyNamespaceOperators = "namespace-operators"
+
+// NamespaceOperators maps the database namespaces to the operators installed into them.
+// Namespaces missing in the mapping get the operators of OperatorConfig.
+type NamespaceOperators map[string]OperatorConfig
+
+// ParseNamespaceOperators parses a mapping such as "dev=pg,mongodb;prod=pxc".
+func ParseNamespaceOperators(str string) (NamespaceOperators, error) {
+	m := NamespaceOperators{}
+	for _, entry := range strings.Split(str, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		ns, ops, ok := strings.Cut(entry, "=")
+		ns = strings.TrimSpace(ns)
+		if !ok || ns == "" {
+			return nil, fmt.Errorf("invalid namespace operators %q. Use the namespace=operator,operator format", entry)
+		}
+		if _, err := ValidateNamespaces(ns); err != nil {
+			return nil, err
+		}
+		if _, ok := m[ns]; ok {
+			return nil, fmt.Errorf("namespace %s is listed more than once", ns)
+		}
+
+		c := OperatorConfig{}
+		for _, op := range strings.Split(ops, ",") {
+			switch strings.TrimSpace(op) {
+			case "pxc", "xtradb-cluster":
+				c.PXC = true
+			case "psmdb", "mongodb":
+				c.PSMDB = true
+			case "pg", "postgresql":
+				c.PG = true
+			case "":
+			default:
+				return nil, fmt.Errorf("unknown operator %q for namespace %s. Allowed values are pxc, mongodb and pg", op, ns)
+			}
+		}
+		if len(c.Names()) == 0 {
+			return nil, fmt.Errorf("no operators selected for namespace %s", ns)
+		}
+		m[ns] = c
+	}
+
+	return m, nil
+}
+
+// String returns the mapping in the format accepted by ParseNamespaceOperators.
+func (m NamespaceOperators) String() string {
+	entries := make([]string, 0, len(m))
+	for _, ns := range m.Namespaces() {
+		c := m[ns]
+		ops := []string{}
+		if c.PG {
+			ops = append(ops, "pg")
+		}
+		if c.PSMDB {
+			ops = append(ops, "mongodb")
+		}
+		if c.PXC {
+			ops = append(ops, "pxc")
+		}
+		entries = append(entries, ns+"="+strings.Join(ops, ","))
+	}
+	return strings.Join(entries, ";")
+}
+
+// Namespaces returns the sorted namespaces of the mapping.
+func (m NamespaceOperators) Namespaces() []string {
+	namespaces := make([]string, 0, len(m))
+	for ns := range m {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// Operators returns the operators of the namespace or def if the namespace is not mapped.
+func (m NamespaceOperators) Operators(namespace string, def OperatorConfig) OperatorConfig {
+	if c, ok := m[namespace]; ok {
+		return c
+	}
+	return def
+}
+
+// Selected returns false if the operator is a database operator
+// which is not selected for the mapped namespace.
+func (m NamespaceOperators) Selected(namespace, operator string) bool {
+	c, ok := m[namespace]
+	if !ok {
+		return true
+	}
+	switch operator {
+	case pxcOperatorName:
+		return c.PXC
+	case psmdbOperatorName:
+		return c.PSMDB
+	case pgOperatorName:
+		return c.PG
+	}
+	return true
+}
+
+// validate checks that all the mapped namespaces are managed by Everest.
+func (m NamespaceOperators) validate(namespaces []string) error {
+	for _, ns := range m.Namespaces() {
+		if !slices.Contains(namespaces, ns) {
+			return fmt.Errorf("namespace %s of namespace operators is not in the namespaces list", ns)
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the selected operators.
+func (c OperatorConfig) Names() []string {
+	names := []string{}
+	if c.PXC {
+		names = append(names, pxcOperatorName)
+	}
+	if c.PSMDB {
+		names = append(names, psmdbOperatorName)
+	}
+	if c.PG {
+		names = append(names, pgOperatorName)
+	}
+	return names
+}
+
+// LoadNamespaceOperators returns the mapping stored by the installation in the system namespace.
+// It returns nil if no mapping is stored.
+func LoadNamespaceOperators(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace string) (NamespaceOperators, error) {
+	state, err := loadState(ctx, k, systemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	str, ok := state[stateKeyNamespaceOperators]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+	return ParseNamespaceOperators(str)
+}
+
+// namespaceOperatorsState returns the operators of every namespace in the list.
+func (o *Install) namespaceOperatorsState() NamespaceOperators {
+	m := make(NamespaceOperators, len(o.config.NamespacesList))
+	for _, ns := range o.config.NamespacesList {
+		m[ns] = o.namespaceOperators.Operators(ns, o.config.Operator)
+	}
+	return m
+}
+
+// updateNamespaceOperatorsState updates the mapping stored by the installation.
+// Nothing is stored if there is no installation state.
+func (o *Install) updateNamespaceOperatorsState(ctx context.Context, update func(m NamespaceOperators)) error {
+	cm, err := o.kubeClient.GetConfigMap(ctx, StateConfigMapName, o.config.SystemNamespace)
+	if err != nil && k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the installation state"))
+	}
+
+	m, err := ParseNamespaceOperators(cm.Data[stateKeyNamespaceOperators])
+	if err != nil {
+		return err
+	}
+	update(m)
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[stateKeyNamespaceOperators] = m.String()
+
+	return o.saveState(cm.Data)
+}
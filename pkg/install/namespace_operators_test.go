@@ -0,0 +1,108 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamespaceOperators(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name   string
+		input  string
+		output NamespaceOperators
+		error  string
+	}
+	tcases := []tcase{
+		{
+			name:   "empty",
+			input:  "",
+			output: NamespaceOperators{},
+		},
+		{
+			name:  "multiple namespaces",
+			input: "dev=pg,mongodb; prod=pxc;",
+			output: NamespaceOperators{
+				"dev":  {PG: true, PSMDB: true},
+				"prod": {PXC: true},
+			},
+		},
+		{
+			name:   "long names",
+			input:  "dev=postgresql,xtradb-cluster,psmdb",
+			output: NamespaceOperators{"dev": {PG: true, PSMDB: true, PXC: true}},
+		},
+		{
+			name:  "missing operators",
+			input: "dev=",
+			error: "no operators selected for namespace dev",
+		},
+		{
+			name:  "missing separator",
+			input: "dev",
+			error: `invalid namespace operators "dev". Use the namespace=operator,operator format`,
+		},
+		{
+			name:  "unknown operator",
+			input: "dev=mysql",
+			error: `unknown operator "mysql" for namespace dev. Allowed values are pxc, mongodb and pg`,
+		},
+		{
+			name:  "duplicated namespace",
+			input: "dev=pg;dev=pxc",
+			error: "namespace dev is listed more than once",
+		},
+		{
+			name:  "reserved namespace",
+			input: "everest-system=pg",
+			error: ErrNSReserved("everest-system").Error(),
+		},
+	}
+
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := ParseNamespaceOperators(tc.input)
+			if tc.error != "" {
+				require.EqualError(t, err, tc.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.output, m)
+
+			parsed, err := ParseNamespaceOperators(m.String())
+			require.NoError(t, err)
+			assert.Equal(t, m, parsed)
+		})
+	}
+}
+
+func TestNamespaceOperatorsSelected(t *testing.T) {
+	t.Parallel()
+
+	m := NamespaceOperators{"dev": {PG: true}}
+	assert.True(t, m.Selected("dev", pgOperatorName))
+	assert.False(t, m.Selected("dev", pxcOperatorName))
+	assert.True(t, m.Selected("dev", everestOperatorName))
+	assert.True(t, m.Selected("prod", pxcOperatorName))
+	assert.Equal(t, []string{pxcOperatorName}, m.Operators("prod", OperatorConfig{PXC: true}).Names())
+}
@@ -0,0 +1,270 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// AddNamespaces provisions the namespaces from the config and adds them
+// to the namespaces managed by Everest.
+// It returns the namespaces managed by Everest after the change.
+func (o *Install) AddNamespaces(ctx context.Context) ([]string, error) {
+	o.setChannelDefaults()
+	if o.config.InstallPlanApproval == "" {
+		o.config.InstallPlanApproval = InstallPlanApprovalManual
+	}
+	if err := ValidateInstallPlanApproval(o.config.InstallPlanApproval); err != nil {
+		return nil, err
+	}
+
+	m, err := ParseNamespaceOperators(o.config.NamespaceOperators)
+	if err != nil {
+		return nil, err
+	}
+	o.namespaceOperators = m
+	if o.config.Namespaces == "" {
+		o.config.Namespaces = strings.Join(m.Namespaces(), ",")
+	}
+	add, err := ValidateNamespaces(o.config.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.config.validateDBNamespaces(add); err != nil {
+		return nil, err
+	}
+	if err := m.validate(add); err != nil {
+		return nil, err
+	}
+	current, err := o.managedNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	olm, err := LoadOLMState(ctx, o.kubeClient, o.config.SystemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	o.kubeClient.SetOLMNamespace(olm.Namespace)
+	// The operators are pulled from the private registry of the installation unless another one is given.
+	if o.registry.IsEmpty() {
+		state, err := loadState(ctx, o.kubeClient, o.config.SystemNamespace)
+		if err != nil {
+			return nil, err
+		}
+		if o.registry, err = registryFromState(state); err != nil {
+			return nil, err
+		}
+		o.kubeClient.SetRegistry(o.registry)
+	}
+
+	o.config.NamespacesList = add
+	if err := o.provisionDBNamespaces(ctx); err != nil {
+		return nil, err
+	}
+
+	namespaces := mergeNamespaces(current, add)
+	if err := o.setManagedNamespaces(ctx, namespaces, nil); err != nil {
+		return nil, err
+	}
+	err = o.updateNamespaceOperatorsState(ctx, func(state NamespaceOperators) {
+		for ns, c := range o.namespaceOperatorsState() {
+			state[ns] = c
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaces, nil
+}
+
+// RemoveNamespaces removes the namespaces from the config from the namespaces
+// managed by Everest and deletes the operators and the Everest role installed into them.
+// The namespaces themselves are kept. Namespaces with database clusters are not removed.
+// It returns the namespaces managed by Everest after the change.
+func (o *Install) RemoveNamespaces(ctx context.Context) ([]string, error) {
+	remove, err := ValidateNamespaces(o.config.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	current, err := o.managedNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := removableNamespaces(current, remove, func(namespace string) (int, error) {
+		clusters, err := o.kubeClient.ListDatabaseClusters(ctx, namespace)
+		if err != nil {
+			return 0, errors.Join(err, fmt.Errorf("could not list database clusters in %s namespace", namespace))
+		}
+		return len(clusters.Items), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Everest stops watching the namespaces before the operators are removed from them.
+	if err := o.setManagedNamespaces(ctx, namespaces, remove); err != nil {
+		return nil, err
+	}
+	for _, namespace := range remove {
+		if err := o.deprovisionDBNamespace(ctx, namespace); err != nil {
+			return nil, err
+		}
+	}
+	err = o.updateNamespaceOperatorsState(ctx, func(state NamespaceOperators) {
+		for _, ns := range remove {
+			delete(state, ns)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaces, nil
+}
+
+// removableNamespaces checks the namespaces can be removed from the current ones
+// and returns the namespaces left. Only the managed namespaces without database clusters
+// can be removed and at least one namespace has to be left.
+func removableNamespaces(current, remove []string, countClusters func(namespace string) (int, error)) ([]string, error) {
+	for _, namespace := range remove {
+		if !slices.Contains(current, namespace) {
+			return nil, fmt.Errorf("namespace %s is not managed by Everest", namespace)
+		}
+		clusters, err := countClusters(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if clusters != 0 {
+			return nil, fmt.Errorf("namespace %s has %d database clusters. Delete them before removing the namespace",
+				namespace, clusters)
+		}
+	}
+
+	namespaces := subtractNamespaces(current, remove)
+	if len(namespaces) == 0 {
+		return nil, ErrLastNamespace
+	}
+	return namespaces, nil
+}
+
+// managedNamespaces returns the namespaces currently managed by Everest.
+func (o *Install) managedNamespaces(ctx context.Context) ([]string, error) {
+	namespaces, err := o.kubeClient.GetDBNamespaces(ctx, o.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest. Make sure Everest is installed"))
+	}
+	return mergeNamespaces(namespaces, nil), nil
+}
+
+// setManagedNamespaces makes the Everest operator and the Everest service account
+// work with the given namespaces only.
+func (o *Install) setManagedNamespaces(ctx context.Context, namespaces, removed []string) error {
+	o.l.Info("Updating operator group for everest")
+	err := o.kubeClient.SetOperatorGroupTargetNamespaces(ctx, systemOperatorGroup, o.config.SystemNamespace, namespaces)
+	if err != nil {
+		return errors.Join(err, errors.New("could not update operator group"))
+	}
+
+	o.l.Info("Updating the namespaces watched by the Everest operator")
+	err = o.kubeClient.SetSubscriptionEnvVar(ctx, o.config.SystemNamespace, everestOperatorName, corev1.EnvVar{
+		Name:  kubernetes.EverestDBNamespacesEnvVar,
+		Value: strings.Join(namespaces, ","),
+	})
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not update %s subscription", everestOperatorName))
+	}
+
+	o.l.Info("Updating cluster role bindings for everest-admin")
+	if err := o.kubeClient.UpdateClusterRoleBinding(ctx, EverestClusterRoleBindingName(o.config.SystemNamespace), namespaces); err != nil {
+		return err
+	}
+	if len(removed) != 0 {
+		if err := o.kubeClient.RemoveClusterRoleBindingNamespaces(ctx, EverestClusterRoleBindingName(o.config.SystemNamespace), removed); err != nil {
+			return err
+		}
+	}
+
+	// The Everest operator is restarted by OLM once the subscription is updated.
+	o.l.Info("Restarting Everest")
+	return o.kubeClient.RestartEverest(ctx, everestBackendServiceName, o.config.SystemNamespace)
+}
+
+// deprovisionDBNamespace deletes what provisionDBNamespace has installed into the namespace.
+func (o *Install) deprovisionDBNamespace(ctx context.Context, namespace string) error {
+	o.l.Infof("Deleting operators from %s namespace", namespace)
+	for _, operator := range []string{pxcOperatorName, psmdbOperatorName, pgOperatorName} {
+		if err := o.kubeClient.DeleteOperator(ctx, namespace, operator); err != nil {
+			return errors.Join(err, fmt.Errorf("could not delete %s operator", operator))
+		}
+	}
+	if err := o.kubeClient.DeleteObject(kubernetes.NewOperatorGroup(dbsOperatorGroup, namespace, nil)); err != nil {
+		return errors.Join(err, errors.New("could not delete operator group"))
+	}
+
+	o.l.Info("Deleting the role of the Everest service account")
+	err := o.kubeClient.DeleteObject(kubernetes.NewRoleBinding(
+		namespace,
+		everestServiceAccountRoleBinding,
+		everestServiceAccountRole,
+		everestServiceAccount,
+	))
+	if err != nil {
+		return errors.Join(err, errors.New("could not delete role binding"))
+	}
+	if err := o.kubeClient.DeleteObject(kubernetes.NewRole(namespace, everestServiceAccountRole, nil)); err != nil {
+		return errors.Join(err, errors.New("could not delete role"))
+	}
+
+	return nil
+}
+
+// mergeNamespaces returns the sorted union of the namespaces skipping the empty ones.
+func mergeNamespaces(a, b []string) []string {
+	m := make(map[string]struct{}, len(a)+len(b))
+	for _, ns := range append(append([]string{}, a...), b...) {
+		if ns != "" {
+			m[ns] = struct{}{}
+		}
+	}
+	namespaces := make([]string, 0, len(m))
+	for ns := range m {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// subtractNamespaces returns the sorted namespaces of a that are not in b.
+func subtractNamespaces(a, b []string) []string {
+	namespaces := []string{}
+	for _, ns := range mergeNamespaces(a, nil) {
+		if !slices.Contains(b, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,89 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemovableNamespaces(t *testing.T) {
+	t.Parallel()
+
+	clusters := map[string]int{"dev": 0, "staging": 0, "prod": 2}
+	countClusters := func(namespace string) (int, error) {
+		if namespace == "broken" {
+			return 0, errors.New("forbidden")
+		}
+		return clusters[namespace], nil
+	}
+	current := []string{"broken", "dev", "prod", "staging"}
+
+	type tcase struct {
+		name    string
+		remove  []string
+		want    []string
+		wantErr string
+	}
+	tcases := []tcase{
+		{name: "removable", remove: []string{"dev", "staging"}, want: []string{"broken", "prod"}},
+		{name: "not managed", remove: []string{"qa"}, wantErr: "namespace qa is not managed by Everest"},
+		{name: "database clusters", remove: []string{"dev", "prod"}, wantErr: "namespace prod has 2 database clusters"},
+		{name: "list error", remove: []string{"broken"}, wantErr: "forbidden"},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			namespaces, err := removableNamespaces(current, tc.remove, countClusters)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, namespaces)
+		})
+	}
+
+	_, err := removableNamespaces([]string{"dev"}, []string{"dev"}, countClusters)
+	assert.ErrorIs(t, err, ErrLastNamespace)
+}
+
+func TestRegistryFromState(t *testing.T) {
+	t.Parallel()
+
+	r, err := registryFromState(map[string]string{
+		stateKeyRegistryMirror:  "docker.io=harbor.example.com/dockerhub,quay.io=harbor.example.com/quay",
+		stateKeyImagePullSecret: "harbor",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"docker.io": "harbor.example.com/dockerhub",
+		"quay.io":   "harbor.example.com/quay",
+	}, r.Mirror)
+	assert.Equal(t, "harbor", r.PullSecret)
+
+	r, err = registryFromState(nil)
+	require.NoError(t, err)
+	assert.True(t, r.IsEmpty())
+
+	_, err = registryFromState(map[string]string{stateKeyRegistryMirror: "docker.io"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// OLMState describes the OLM used by the installation.
+type OLMState struct {
+	// Namespace is the namespace of the Percona catalog.
+	Namespace string
+	// Existing is true if Everest uses an OLM installed by someone else.
+	Existing bool
+}
+
+// LoadOLMState returns the OLM used by the installation in the system namespace.
+// The OLM bundled with the CLI is returned if there is no installation state.
+func LoadOLMState(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace string) (OLMState, error) {
+	olm := OLMState{Namespace: kubernetes.OLMNamespace}
+	state, err := loadState(ctx, k, systemNamespace)
+	if err != nil {
+		return olm, err
+	}
+
+	if ns := state[stateKeyOLMNamespace]; ns != "" {
+		olm.Namespace = ns
+	}
+	if v, ok := state[stateKeyExistingOLM]; ok {
+		if olm.Existing, err = strconv.ParseBool(v); err != nil {
+			return olm, errors.Join(err, errors.New("invalid installation state"))
+		}
+	}
+	return olm, nil
+}
+
+// detectOLM looks up the existing OLM installation if it shall be used.
+func (o *Install) detectOLM(ctx context.Context) error {
+	if !o.config.UseExistingOLM {
+		return nil
+	}
+
+	ns, err := o.kubeClient.FindOLMNamespace(ctx)
+	if err != nil {
+		return errors.Join(err, errors.New("could not find an existing OLM installation"))
+	}
+	o.l.Infof("Using the existing OLM installation. Percona catalog goes to %s namespace", ns)
+	o.kubeClient.SetOLMNamespace(ns)
+	return nil
+}
+
+// olmState returns the OLM used by the installation.
+func (o *Install) olmState() OLMState {
+	return OLMState{
+		Namespace: o.kubeClient.GetOLMNamespace(),
+		Existing:  o.config.UseExistingOLM,
+	}
+}
+
+// OtherInstallations returns the system namespaces of the Everest installations
+// other than the one in the system namespace which use the catalog in the OLM namespace.
+func OtherInstallations(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace, olmNamespace string) ([]string, error) {
+	subs, err := k.ListSubscriptions(ctx, "")
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not list subscriptions"))
+	}
+	namespaces := []string{}
+	for _, s := range subs.Items {
+		if s.Name == everestOperatorName && s.Namespace != systemNamespace &&
+			s.Spec != nil && s.Spec.CatalogSourceNamespace == olmNamespace {
+			namespaces = append(namespaces, s.Namespace)
+		}
+	}
+	return namespaces, nil
+}
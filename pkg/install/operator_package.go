@@ -0,0 +1,115 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// VersionConfig stores the operator versions the installation is pinned to.
+// Empty values install the head of the channel.
+type VersionConfig struct {
+	// Everest stores the version of the Everest operator.
+	Everest string `mapstructure:"everest"`
+	// PG stores the version of the PostgreSQL operator.
+	PG string `mapstructure:"postgresql"`
+	// PSMDB stores the version of the MongoDB operator.
+	PSMDB string `mapstructure:"mongodb"`
+	// PXC stores the version of the XtraDB Cluster operator.
+	PXC string `mapstructure:"xtradb-cluster"`
+	// VictoriaMetrics stores the version of the VictoriaMetrics operator.
+	VictoriaMetrics string `mapstructure:"victoria-metrics"`
+}
+
+// operatorPackage is an operator package installed from the Percona catalog.
+type operatorPackage struct {
+	name    string
+	channel string
+	version string
+}
+
+// operatorPackages returns the packages of the installed operators.
+func (o *Install) operatorPackages() []operatorPackage {
+	res := []operatorPackage{
+		{everestOperatorName, o.config.Channel.Everest, o.config.Version.Everest},
+		{vmOperatorName, o.config.Channel.VictoriaMetrics, o.config.Version.VictoriaMetrics},
+	}
+	for _, op := range o.allDBOperators() {
+		switch op.name {
+		case pxcOperatorName:
+			res = append(res, operatorPackage{op.name, op.channel, o.config.Version.PXC})
+		case psmdbOperatorName:
+			res = append(res, operatorPackage{op.name, op.channel, o.config.Version.PSMDB})
+		case pgOperatorName:
+			res = append(res, operatorPackage{op.name, op.channel, o.config.Version.PG})
+		}
+	}
+	return res
+}
+
+// validateOperatorChannels checks that the channels of the installed operators
+// exist in the package manifests of the Percona catalog.
+func (o *Install) validateOperatorChannels(ctx context.Context) error {
+	for _, p := range o.operatorPackages() {
+		err := o.kubeClient.ValidateOperatorChannel(ctx, p.name, p.channel)
+		if err != nil && o.config.DryRun && k8serrors.IsNotFound(err) {
+			// The catalog is not installed yet in dry-run mode.
+			o.l.Warnf("Channel %s of %s cannot be validated in dry-run mode", p.channel, p.name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveOperatorVersions resolves the pinned versions to the CSV names
+// using the package manifests of the Percona catalog.
+func (o *Install) resolveOperatorVersions(ctx context.Context) error {
+	o.startingCSVs = make(map[string]string)
+	for _, v := range o.operatorPackages() {
+		if v.version == "" {
+			continue
+		}
+		if o.config.RenderOnly != "" {
+			// The catalog is not available when rendering manifests.
+			o.l.Warnf("Version %s of %s cannot be validated in render-only mode", v.version, v.name)
+			o.startingCSVs[v.name] = kubernetes.OperatorCSVName(v.name, v.version)
+			continue
+		}
+
+		csv, err := o.kubeClient.GetOperatorCSV(ctx, v.name, v.channel, v.version)
+		if err != nil && o.config.DryRun && k8serrors.IsNotFound(err) {
+			// The catalog is not installed yet in dry-run mode.
+			o.l.Warnf("Version %s of %s cannot be validated in dry-run mode", v.version, v.name)
+			csv, err = kubernetes.OperatorCSVName(v.name, v.version), nil
+		}
+		if err != nil {
+			return err
+		}
+
+		o.l.Infof("Pinning %s operator to %s", v.name, csv)
+		o.startingCSVs[v.name] = csv
+	}
+
+	return nil
+}
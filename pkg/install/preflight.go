@@ -0,0 +1,66 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/preflight"
+)
+
+// ReservedNamespaces returns the namespaces reserved for Everest internals.
+func ReservedNamespaces(systemNamespace, monitoringNamespace, olmNamespace string) []preflight.ReservedNamespace {
+	return []preflight.ReservedNamespace{
+		{Name: systemNamespace, Subscription: everestOperatorName},
+		{Name: monitoringNamespace, Subscription: vmOperatorName},
+		{Name: olmNamespace, Deployment: kubernetes.OLMOperatorName},
+	}
+}
+
+// runPreflight checks the cluster before anything is changed in it.
+func (o *Install) runPreflight(ctx context.Context) error {
+	o.l.Info("Running preflight checks")
+	p, err := preflight.NewPreflight(preflight.Config{
+		KubeconfigPath:     o.config.KubeconfigPath,
+		ReservedNamespaces: ReservedNamespaces(o.config.SystemNamespace, o.config.MonitoringNamespace, o.config.OLMNamespace),
+		UseExistingOLM:     o.config.UseExistingOLM,
+		OLMNamespace:       o.config.OLMNamespace,
+	}, o.l)
+	if err != nil {
+		return err
+	}
+
+	res, err := p.Run(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range res.Checks {
+		switch c.Status {
+		case preflight.StatusFail:
+			o.l.Errorf("Preflight check %s has failed: %s. %s", c.Name, c.Message, c.Hint)
+		case preflight.StatusWarn:
+			o.l.Warnf("Preflight check %s: %s. %s", c.Name, c.Message, c.Hint)
+		}
+	}
+	if res.Failed() {
+		return errors.Join(preflight.ErrChecksFailed,
+			errors.New("fix the problems above or run the installation with --skip-preflight"))
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/percona/percona-everest-cli/data"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+// renderedFile is a manifest file written by the render-only mode.
+type renderedFile struct {
+	name    string
+	content []byte
+}
+
+// render writes the manifests of the installation to the render-only directory.
+// The files are prefixed with a number in the order they shall be applied.
+func (o *Install) render(ctx context.Context) error {
+	if err := o.resolveOperatorVersions(ctx); err != nil {
+		return err
+	}
+
+	files, err := o.renderFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(o.config.RenderOnly, 0o755); err != nil { //nolint:gomnd
+		return errors.Join(err, errors.New("could not create render directory"))
+	}
+
+	for i, f := range files {
+		content, err := o.registry.CustomizeManifest(f.content)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not customize %s", f.name))
+		}
+		path := filepath.Join(o.config.RenderOnly, fmt.Sprintf("%02d-%s.yaml", i, f.name))
+		o.l.Infof("Writing %s", path)
+		if err := os.WriteFile(path, content, 0o644); err != nil { //nolint:gosec,gomnd
+			return errors.Join(err, fmt.Errorf("could not write %s", path))
+		}
+	}
+
+	o.l.Infof("Manifests have been written to %s", o.config.RenderOnly)
+	o.l.Info("Once they are applied, run `everestctl token reset` to generate the Everest token")
+
+	return nil
+}
+
+func (o *Install) renderFiles(ctx context.Context) ([]renderedFile, error) { //nolint:funlen
+	files := make([]renderedFile, 0, 8+len(o.config.NamespacesList)) //nolint:gomnd
+
+	// The cluster is not contacted so the existing OLM is expected in the configured OLM namespace.
+	if !o.config.UseExistingOLM {
+		for _, f := range []string{"crds", "olm"} {
+			content, err := data.OLMCRDs.ReadFile("crds/olm/" + f + ".yaml")
+			if err != nil {
+				return nil, err
+			}
+			if content, err = kubernetes.RelocateOLMManifest(content, o.kubeClient.GetOLMNamespace()); err != nil {
+				return nil, err
+			}
+			files = append(files, renderedFile{name: "olm-" + f, content: content})
+		}
+	}
+
+	catalog, err := kubernetes.PerconaCatalogManifest(o.config.CatalogImage, o.kubeClient.GetOLMNamespace())
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, renderedFile{name: "everest-catalog", content: catalog})
+
+	namespaces := []runtime.Object{kubernetes.NewNamespace(o.config.MonitoringNamespace)}
+	for _, ns := range o.config.NamespacesList {
+		namespaces = append(namespaces, kubernetes.NewNamespace(ns))
+	}
+	namespaces = append(namespaces, kubernetes.NewNamespace(o.config.SystemNamespace))
+	if files, err = appendRendered(files, "namespaces", namespaces...); err != nil {
+		return nil, err
+	}
+
+	if files, err = appendRendered(files, "monitoring-operator",
+		kubernetes.NewOperatorGroup(monitoringOperatorGroup, o.config.MonitoringNamespace, []string{}),
+		renderSubscription(o.vmOperatorRequest()),
+	); err != nil {
+		return nil, err
+	}
+
+	monitoring := []runtime.Object{}
+	for _, path := range kubernetes.VictoriaMetricsFiles() {
+		content, err := data.OLMCRDs.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		objs, err := client.CustomizeManifest(content, o.config.MonitoringNamespace)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("cannot render file: %q", path))
+		}
+		for _, obj := range objs {
+			monitoring = append(monitoring, obj)
+		}
+	}
+	if files, err = appendRendered(files, "monitoring", monitoring...); err != nil {
+		return nil, err
+	}
+
+	for _, ns := range o.config.NamespacesList {
+		objs := []runtime.Object{kubernetes.NewOperatorGroup(dbsOperatorGroup, ns, []string{})}
+		for _, op := range o.dbOperators(ns) {
+			objs = append(objs, renderSubscription(o.operatorRequest(op.channel, op.name, ns)))
+		}
+		objs = append(objs,
+			kubernetes.NewRole(ns, everestServiceAccountRole, o.serviceAccountRolePolicyRules()),
+			kubernetes.NewRoleBinding(ns, everestServiceAccountRoleBinding, everestServiceAccountRole, everestServiceAccount),
+		)
+		if files, err = appendRendered(files, "namespace-"+ns, objs...); err != nil {
+			return nil, err
+		}
+	}
+
+	if files, err = appendRendered(files, "everest-operator",
+		kubernetes.NewOperatorGroup(systemOperatorGroup, o.config.SystemNamespace, o.config.NamespacesList),
+		renderSubscription(o.operatorRequest(o.config.Channel.Everest, everestOperatorName, o.config.SystemNamespace)),
+	); err != nil {
+		return nil, err
+	}
+
+	o.l.Info("Downloading Everest manifest")
+	manifest, err := o.kubeClient.GetEverestManifest(ctx)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed downloading everest manifest file"))
+	}
+	objs, err := client.CustomizeManifest(manifest, o.config.SystemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	everest := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		if obj.GetKind() == "ClusterRoleBinding" && obj.GetName() == EverestClusterRoleBindingName(o.config.SystemNamespace) {
+			if err := addClusterRoleBindingSubjects(obj, o.config.NamespacesList); err != nil {
+				return nil, err
+			}
+		}
+		if err := kubernetes.SetServiceType(obj, corev1.ServiceType(o.config.EverestServiceType)); err != nil {
+			return nil, err
+		}
+		everest = append(everest, obj)
+	}
+	if files, err = appendRendered(files, "everest", everest...); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+type dbOperator struct {
+	name    string
+	channel string
+}
+
+// dbOperators returns the database operators selected for the namespace.
+func (o *Install) dbOperators(namespace string) []dbOperator {
+	ops := []dbOperator{}
+	for _, name := range o.namespaceOperators.Operators(namespace, o.config.Operator).Names() {
+		ops = append(ops, dbOperator{name, o.dbOperatorChannel(name)})
+	}
+	return ops
+}
+
+// allDBOperators returns the database operators selected for any of the namespaces.
+func (o *Install) allDBOperators() []dbOperator {
+	selected := OperatorConfig{}
+	for _, ns := range o.config.NamespacesList {
+		c := o.namespaceOperators.Operators(ns, o.config.Operator)
+		selected.PXC = selected.PXC || c.PXC
+		selected.PSMDB = selected.PSMDB || c.PSMDB
+		selected.PG = selected.PG || c.PG
+	}
+	ops := []dbOperator{}
+	for _, name := range selected.Names() {
+		ops = append(ops, dbOperator{name, o.dbOperatorChannel(name)})
+	}
+	return ops
+}
+
+func (o *Install) dbOperatorChannel(name string) string {
+	return o.config.Channel.channel(name)
+}
+
+// renderSubscription returns the subscription for the install request.
+// Nobody approves install plans when the manifests are applied by
+// a GitOps tool so the rendered subscriptions use automatic approval.
+func renderSubscription(req kubernetes.InstallOperatorRequest) *v1alpha1.Subscription {
+	req.InstallPlanApproval = v1alpha1.ApprovalAutomatic
+	req.ConfirmInstallPlan = nil
+	s := kubernetes.NewSubscription(req)
+	s.Spec.Config = req.SubscriptionConfig
+	return s
+}
+
+// addClusterRoleBindingSubjects binds the subject of the cluster role binding
+// in the provided namespaces the same way UpdateClusterRoleBinding does.
+func addClusterRoleBindingSubjects(u *unstructured.Unstructured, namespaces []string) error {
+	subjects, ok, err := unstructured.NestedSlice(u.Object, "subjects")
+	if err != nil || !ok || len(subjects) == 0 {
+		return err
+	}
+	subject, ok := subjects[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for _, ns := range namespaces {
+		s := runtime.DeepCopyJSON(subject)
+		s["namespace"] = ns
+		subjects = append(subjects, s)
+	}
+	return unstructured.SetNestedSlice(u.Object, subjects, "subjects")
+}
+
+func appendRendered(files []renderedFile, name string, objs ...runtime.Object) ([]renderedFile, error) {
+	content, err := marshalObjects(objs...)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not render %s", name))
+	}
+	return append(files, renderedFile{name: name, content: content}), nil
+}
+
+// marshalObjects returns a multi-document YAML with the provided objects.
+// Status and empty creation timestamps are omitted.
+func marshalObjects(objs ...runtime.Object) ([]byte, error) {
+	var b bytes.Buffer
+	for i, obj := range objs {
+		// Objects are converted through JSON since the default unstructured
+		// converter can't handle some of the OLM types.
+		j, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := make(map[string]interface{})
+		if err := json.Unmarshal(j, &u); err != nil {
+			return nil, err
+		}
+		delete(u, "status")
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+
+		out, err := yaml.Marshal(u)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		b.Write(out)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,47 @@
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+func TestMarshalObjects(t *testing.T) {
+	t.Parallel()
+
+	o := &Install{config: Config{DisableTelemetry: true}, kubeClient: kubernetes.NewEmpty(zap.NewNop().Sugar())}
+	out, err := marshalObjects(
+		kubernetes.NewOperatorGroup(dbsOperatorGroup, "dev", []string{}),
+		renderSubscription(o.operatorRequest(pxcOperatorChannel, pxcOperatorName, "dev")),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: operators.coreos.com/v1
+kind: OperatorGroup
+metadata:
+  name: everest-databases
+  namespace: dev
+spec:
+  targetNamespaces:
+  - dev
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: percona-xtradb-cluster-operator
+  namespace: dev
+spec:
+  channel: stable-v1
+  config:
+    env:
+    - name: DISABLE_TELEMETRY
+      value: "true"
+  installPlanApproval: Automatic
+  name: percona-xtradb-cluster-operator
+  source: everest-catalog
+  sourceNamespace: everest-olm
+`, string(out))
+}
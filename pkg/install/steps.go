@@ -0,0 +1,309 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/token"
+)
+
+const (
+	// StateConfigMapName is the name of the config map storing the installation state.
+	StateConfigMapName = "everest-install-state"
+
+	stepOLM             = "olm"
+	stepChannels        = "operator-channels"
+	stepVersions        = "operator-versions"
+	stepMonitoring      = "monitoring"
+	stepDBNamespaces    = "db-namespaces"
+	stepEverestOperator = "everest-operator"
+	stepEverest         = "everest"
+	stepToken           = "token"
+
+	stepStatusCompleted = "completed"
+	stepStatusFailed    = "failed"
+
+	stateKeyNamespaces   = "namespaces"
+	stateKeyError        = "error"
+	stateKeyOLMNamespace = "olm-namespace"
+	stateKeyExistingOLM  = "existing-olm"
+	// stateKeyMonitoringNamespace is read by the commands run after the installation.
+	stateKeyMonitoringNamespace = "monitoring-namespace"
+	// stateKeyDefaultStorageClass is the storage class made default by the installation.
+	// It is reverted by the uninstall command.
+	stateKeyDefaultStorageClass = "default-storage-class"
+	// stateKeyRegistryMirror and stateKeyImagePullSecret hold the private registry of the installation.
+	// They are used by the namespaces add command.
+	stateKeyRegistryMirror  = "registry-mirror"
+	stateKeyImagePullSecret = "image-pull-secret"
+	// stateKeyChannels holds the operator channels provided by the user.
+	// They are used together with the namespace operators by a resumed installation.
+	stateKeyChannels = "channels"
+)
+
+// step is a named provisioning step of the installation.
+type step struct {
+	name string
+	run  func(ctx context.Context) error
+	// volatile steps keep no state in the cluster so they are never skipped.
+	volatile bool
+}
+
+// steps returns the provisioning steps in the order they are run.
+func (o *Install) steps() []step {
+	return []step{
+		{name: stepOLM, run: o.provisionOLM},
+		{name: stepChannels, run: o.validateOperatorChannels, volatile: true},
+		{name: stepVersions, run: o.resolveOperatorVersions, volatile: true},
+		{name: stepMonitoring, run: o.provisionMonitoringStack},
+		{name: stepDBNamespaces, run: o.provisionDBNamespaces},
+		{name: stepEverestOperator, run: o.provisionEverestOperator},
+		{name: stepEverest, run: o.provisionEverest},
+		{name: stepToken, run: o.provisionToken},
+	}
+}
+
+// runSteps runs the steps and records their state in the state config map.
+// Steps completed according to the state are skipped when resuming.
+func (o *Install) runSteps(ctx context.Context, steps []step, state map[string]string) error {
+	for _, s := range steps {
+		if o.config.Resume && !s.volatile && state[s.name] == stepStatusCompleted {
+			o.l.Infof("Skipping completed step %s", s.name)
+			continue
+		}
+
+		o.l.Debugf("Running step %s", s.name)
+		if err := s.run(ctx); err != nil {
+			state[s.name] = stepStatusFailed
+			state[stateKeyError] = err.Error()
+			if err := o.saveState(state); err != nil {
+				o.l.Warnf("Could not save the installation state: %s", err)
+			}
+			return errors.Join(err, fmt.Errorf("installation failed at step %q. "+
+				"Fix the issue and run the install command with --resume to continue", s.name))
+		}
+
+		state[s.name] = stepStatusCompleted
+		delete(state, stateKeyError)
+		if err := o.saveState(state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initState returns the state of the installation. A new state is returned
+// unless the installation is resumed.
+func (o *Install) initState(ctx context.Context) (map[string]string, error) {
+	namespaces := strings.Join(o.config.NamespacesList, ",")
+	olm := o.olmState()
+	state := map[string]string{
+		stateKeyNamespaces:          namespaces,
+		stateKeyNamespaceOperators:  o.namespaceOperatorsState().String(),
+		stateKeyChannels:            o.explicitChannels.String(),
+		stateKeyOLMNamespace:        olm.Namespace,
+		stateKeyExistingOLM:         strconv.FormatBool(olm.Existing),
+		stateKeyMonitoringNamespace: o.config.MonitoringNamespace,
+	}
+	if o.defaultStorageClass != "" {
+		state[stateKeyDefaultStorageClass] = o.defaultStorageClass
+	}
+	if len(o.registry.Mirror) != 0 {
+		state[stateKeyRegistryMirror] = strings.Join(o.registry.MirrorList(), ",")
+	}
+	if o.registry.PullSecret != "" {
+		state[stateKeyImagePullSecret] = o.registry.PullSecret
+	}
+	if !o.config.Resume {
+		// The storage class made default by a previous installation is still to be reverted.
+		prev, err := loadState(ctx, o.kubeClient, o.config.SystemNamespace)
+		if err != nil {
+			return nil, err
+		}
+		if sc, ok := prev[stateKeyDefaultStorageClass]; ok && o.defaultStorageClass == "" {
+			state[stateKeyDefaultStorageClass] = sc
+		}
+		return state, nil
+	}
+
+	cm, err := o.kubeClient.GetConfigMap(ctx, StateConfigMapName, o.config.SystemNamespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, errors.Join(err, errors.New("could not get the installation state"))
+	}
+	if err != nil {
+		o.l.Info("No installation state found. Starting from the beginning")
+		return state, nil
+	}
+
+	if cm.Data[stateKeyNamespaces] != namespaces {
+		return nil, fmt.Errorf("the namespaces %q differ from the resumed installation namespaces %q. "+
+			"Run the install command without --resume", namespaces, cm.Data[stateKeyNamespaces])
+	}
+	if ns, ok := cm.Data[stateKeyMonitoringNamespace]; ok && ns != o.config.MonitoringNamespace {
+		return nil, fmt.Errorf("the monitoring namespace %q differs from the resumed installation monitoring namespace %q. "+
+			"Run the install command without --resume", o.config.MonitoringNamespace, ns)
+	}
+	if err := o.resumeSelection(cm.Data); err != nil {
+		return nil, err
+	}
+	state[stateKeyNamespaceOperators] = o.namespaceOperatorsState().String()
+	state[stateKeyChannels] = o.explicitChannels.String()
+	for k, v := range cm.Data {
+		if _, ok := state[k]; ok {
+			continue
+		}
+		state[k] = v
+	}
+
+	return state, nil
+}
+
+// resumeSelection makes the installation use the operators and channels
+// selected by the resumed installation.
+// The channels provided by the user have to match the resumed ones.
+func (o *Install) resumeSelection(state map[string]string) error {
+	if str, ok := state[stateKeyNamespaceOperators]; ok {
+		m, err := ParseNamespaceOperators(str)
+		if err != nil {
+			return errors.Join(err, errors.New("invalid namespace operators in the installation state"))
+		}
+		o.l.Infof("Using the operators of the resumed installation: %s", m)
+		o.namespaceOperators = m
+	}
+
+	str, ok := state[stateKeyChannels]
+	if !ok {
+		return nil
+	}
+	channels, err := parseChannelConfig(str)
+	if err != nil {
+		return errors.Join(err, errors.New("invalid operator channels in the installation state"))
+	}
+	if o.explicitChannels != (ChannelConfig{}) && o.explicitChannels != channels {
+		return fmt.Errorf("the operator channels %q differ from the resumed installation channels %q. "+
+			"Run the install command without --resume", o.explicitChannels, channels)
+	}
+	o.explicitChannels = channels
+	o.config.Channel = channels
+	o.config.Channel.setDefaults()
+	return nil
+}
+
+// loadState returns the installation state stored in the system namespace.
+// It returns nil if there is no installation state.
+func loadState(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace string) (map[string]string, error) {
+	cm, err := k.GetConfigMap(ctx, StateConfigMapName, systemNamespace)
+	if err != nil && k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the installation state"))
+	}
+	return cm.Data, nil
+}
+
+// LoadMonitoringNamespace returns the monitoring namespace of the installation in the system namespace.
+// MonitoringNamespace is returned if there is no installation state.
+func LoadMonitoringNamespace(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace string) (string, error) {
+	state, err := loadState(ctx, k, systemNamespace)
+	if err != nil {
+		return "", err
+	}
+	if ns := state[stateKeyMonitoringNamespace]; ns != "" {
+		return ns, nil
+	}
+	return MonitoringNamespace, nil
+}
+
+// LoadDefaultStorageClass returns the storage class made default by the installation
+// in the system namespace. It returns an empty string if no storage class was made default.
+func LoadDefaultStorageClass(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace string) (string, error) {
+	state, err := loadState(ctx, k, systemNamespace)
+	if err != nil {
+		return "", err
+	}
+	return state[stateKeyDefaultStorageClass], nil
+}
+
+// registryFromState returns the private registry recorded in the installation state.
+func registryFromState(state map[string]string) (kubernetes.RegistryConfig, error) {
+	var mirrors []string
+	if m := state[stateKeyRegistryMirror]; m != "" {
+		mirrors = strings.Split(m, ",")
+	}
+	r, err := kubernetes.NewRegistryConfig(mirrors, state[stateKeyImagePullSecret])
+	if err != nil {
+		return kubernetes.RegistryConfig{}, errors.Join(err, errors.New("invalid registry in the installation state"))
+	}
+	return r, nil
+}
+
+// saveState stores the state of the installation in the state config map.
+// The system namespace is created if it does not exist yet.
+func (o *Install) saveState(state map[string]string) error {
+	if o.config.DryRun {
+		return nil
+	}
+
+	if err := o.kubeClient.CreateNamespace(o.config.SystemNamespace); err != nil {
+		return errors.Join(err, errors.New("could not provision namespace"))
+	}
+	err := o.kubeClient.SetConfigMap(&corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StateConfigMapName,
+			Namespace: o.config.SystemNamespace,
+		},
+		Data: state,
+	})
+	if err != nil {
+		return errors.Join(err, errors.New("could not save the installation state"))
+	}
+
+	return nil
+}
+
+func (o *Install) provisionToken(ctx context.Context) error {
+	_, err := o.kubeClient.GetSecret(ctx, token.SecretName, o.config.SystemNamespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Join(err, errors.New("could not get the everest token secret"))
+	}
+	if err != nil && k8serrors.IsNotFound(err) {
+		pwd, err := o.generateToken(ctx)
+		if err != nil {
+			return err
+		}
+		if !o.config.DryRun {
+			o.l.Info("\n" + pwd.String() + "\n\n")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRunSteps(t *testing.T) {
+	t.Parallel()
+
+	l, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	// Dry-run mode prevents saving the state to the cluster.
+	o := &Install{
+		config: Config{DryRun: true, Resume: true},
+		l:      l.Sugar(),
+	}
+
+	ran := []string{}
+	stepFunc := func(name string, err error) step {
+		return step{name: name, run: func(context.Context) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+	steps := []step{
+		stepFunc(stepOLM, nil),
+		stepFunc(stepMonitoring, nil),
+		stepFunc(stepDBNamespaces, errors.New("timeout")),
+		stepFunc(stepEverest, nil),
+	}
+	state := map[string]string{stepOLM: stepStatusCompleted}
+
+	err = o.runSteps(context.Background(), steps, state)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `step "db-namespaces"`)
+	assert.Equal(t, []string{stepMonitoring, stepDBNamespaces}, ran)
+	assert.Equal(t, map[string]string{
+		stepOLM:          stepStatusCompleted,
+		stepMonitoring:   stepStatusCompleted,
+		stepDBNamespaces: stepStatusFailed,
+		stateKeyError:    "timeout",
+	}, state)
+
+	ran = []string{}
+	steps[2] = stepFunc(stepDBNamespaces, nil)
+	require.NoError(t, o.runSteps(context.Background(), steps, state))
+	assert.Equal(t, []string{stepDBNamespaces, stepEverest}, ran)
+	assert.NotContains(t, state, stateKeyError)
+}
+
+func TestParseChannelConfig(t *testing.T) {
+	t.Parallel()
+
+	c := ChannelConfig{Everest: "fast-v0", PXC: "stable-v1", VictoriaMetrics: "stable-v0"}
+	assert.Equal(t, "everest=fast-v0,xtradb-cluster=stable-v1,victoria-metrics=stable-v0", c.String())
+	parsed, err := parseChannelConfig(c.String())
+	require.NoError(t, err)
+	assert.Equal(t, c, parsed)
+
+	parsed, err = parseChannelConfig("")
+	require.NoError(t, err)
+	assert.Equal(t, ChannelConfig{}, parsed)
+
+	_, err = parseChannelConfig("mysql=stable-v1")
+	require.Error(t, err)
+}
+
+func TestResumeSelection(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name     string
+		explicit ChannelConfig
+		state    map[string]string
+		want     ChannelConfig
+		error    string
+	}
+	tcases := []tcase{
+		{
+			name:  "resumed channels",
+			state: map[string]string{stateKeyNamespaceOperators: "dev=pg", stateKeyChannels: "postgresql=fast-v2"},
+			want:  ChannelConfig{PG: "fast-v2"},
+		},
+		{
+			name:     "same channels",
+			explicit: ChannelConfig{PG: "fast-v2"},
+			state:    map[string]string{stateKeyNamespaceOperators: "dev=pg", stateKeyChannels: "postgresql=fast-v2"},
+			want:     ChannelConfig{PG: "fast-v2"},
+		},
+		{
+			name:     "different channels",
+			explicit: ChannelConfig{PG: "stable-v2"},
+			state:    map[string]string{stateKeyNamespaceOperators: "dev=pg", stateKeyChannels: "postgresql=fast-v2"},
+			error:    "differ from the resumed installation channels",
+		},
+		{
+			name:     "state without channels",
+			explicit: ChannelConfig{PG: "stable-v2"},
+			state:    map[string]string{stateKeyNamespaceOperators: "dev=pg"},
+			want:     ChannelConfig{PG: "stable-v2"},
+		},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			o := &Install{
+				config: Config{
+					NamespacesList: []string{"dev"},
+					Operator:       OperatorConfig{PG: true, PSMDB: true, PXC: true},
+					Channel:        tc.explicit,
+				},
+				l: zap.NewNop().Sugar(),
+			}
+			o.setChannelDefaults()
+
+			err := o.resumeSelection(tc.state)
+			if tc.error != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "dev=pg", o.namespaceOperatorsState().String())
+			assert.Equal(t, tc.want, o.explicitChannels)
+			want := tc.want
+			want.setDefaults()
+			assert.Equal(t, want, o.config.Channel)
+		})
+	}
+}
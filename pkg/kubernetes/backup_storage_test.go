@@ -0,0 +1,88 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+// everestOperatorDeployment returns the deployment of the Everest operator watching the namespaces.
+func everestOperatorDeployment(namespaces string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: everestOperatorContainerName,
+						Env:  []corev1.EnvVar{{Name: EverestDBNamespacesEnvVar, Value: namespaces}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestIsBackupStorageUsed(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name     string
+		clusters int
+		backups  int
+		restores int
+		want     bool
+	}
+	tcases := []tcase{
+		{name: "unused"},
+		{name: "database cluster", clusters: 1, want: true},
+		{name: "backup", backups: 1, want: true},
+		{name: "restore", restores: 1, want: true},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			k8sclient := &client.MockKubeClientConnector{}
+			k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+			k8sclient.On("GetBackupStorage", ctx, "everest-system", "s3").Return(&everestv1alpha1.BackupStorage{}, nil)
+			k8sclient.On("GetDeployment", ctx, EverestOperatorDeploymentName, "everest-system").
+				Return(everestOperatorDeployment("dev"), nil)
+			k8sclient.On("ListDatabaseClusters", ctx, "dev", mock.Anything).
+				Return(&everestv1alpha1.DatabaseClusterList{Items: make([]everestv1alpha1.DatabaseCluster, tc.clusters)}, nil)
+			k8sclient.On("ListDatabaseClusterBackups", ctx, "dev", mock.Anything).
+				Return(&everestv1alpha1.DatabaseClusterBackupList{Items: make([]everestv1alpha1.DatabaseClusterBackup, tc.backups)}, nil)
+			k8sclient.On("ListDatabaseClusterRestores", ctx, "dev", mock.Anything).
+				Return(&everestv1alpha1.DatabaseClusterRestoreList{Items: make([]everestv1alpha1.DatabaseClusterRestore, tc.restores)}, nil)
+
+			used, err := k.IsBackupStorageUsed(ctx, "everest-system", "s3")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, used)
+		})
+	}
+}
@@ -39,6 +39,7 @@ import (
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -84,6 +85,11 @@ const (
 
 	defaultAPIURIPath  = "/api"
 	defaultAPIsURIPath = "/apis"
+
+	// defaultSystemNamespace and defaultMonitoringNamespace are the namespaces
+	// Everest is installed into by default.
+	defaultSystemNamespace     = "everest-system"
+	defaultMonitoringNamespace = "everest-monitoring"
 )
 
 // Each level has 2 spaces for PrefixWriter.
@@ -345,27 +351,10 @@ func (c *Client) ListSecrets(ctx context.Context) (*corev1.SecretList, error) {
 
 // DeleteObject deletes object from the k8s cluster.
 func (c *Client) DeleteObject(obj runtime.Object) error {
-	groupResources, err := restmapper.GetAPIGroupResources(c.clientset.Discovery())
+	helper, namespace, name, err := c.resourceHelper(obj)
 	if err != nil {
 		return err
 	}
-	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
-
-	gvk := obj.GetObjectKind().GroupVersionKind()
-	gk := schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}
-	mapping, err := mapper.RESTMapping(gk, gvk.Version)
-	if err != nil {
-		return err
-	}
-	namespace, name, err := c.retrieveMetaFromObject(obj)
-	if err != nil {
-		return err
-	}
-	cli, err := c.resourceClient(mapping.GroupVersionKind.GroupVersion())
-	if err != nil {
-		return err
-	}
-	helper := resource.NewHelper(cli, mapping)
 	err = deleteObject(helper, namespace, name)
 	return err
 }
@@ -382,28 +371,64 @@ func deleteObject(helper *resource.Helper, namespace, name string) error {
 
 // ApplyObject applies object.
 func (c *Client) ApplyObject(obj runtime.Object) error {
-	groupResources, err := restmapper.GetAPIGroupResources(c.clientset.Discovery())
+	helper, namespace, name, err := c.resourceHelper(obj)
+	if err != nil {
+		return err
+	}
+	return c.applyObject(helper, namespace, name, obj)
+}
+
+// CreateObject creates object.
+// Unlike ApplyObject it fails with an AlreadyExists error if the object exists.
+func (c *Client) CreateObject(obj runtime.Object) error {
+	helper, namespace, _, err := c.resourceHelper(obj)
 	if err != nil {
 		return err
 	}
+	_, err = helper.Create(namespace, false, obj)
+	return err
+}
+
+// ObjectExists checks if the object exists in the k8s cluster.
+func (c *Client) ObjectExists(obj runtime.Object) (bool, error) {
+	helper, namespace, name, err := c.resourceHelper(obj)
+	if err != nil {
+		return false, err
+	}
+	_, err = helper.Get(namespace, name)
+	if err != nil && apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resourceHelper returns a REST helper for the object kind
+// together with the namespace and the name of the object.
+func (c *Client) resourceHelper(obj runtime.Object) (*resource.Helper, string, string, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(c.clientset.Discovery())
+	if err != nil {
+		return nil, "", "", err
+	}
 	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
 
 	gvk := obj.GetObjectKind().GroupVersionKind()
 	gk := schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}
 	mapping, err := mapper.RESTMapping(gk, gvk.Version)
 	if err != nil {
-		return err
+		return nil, "", "", err
 	}
 	namespace, name, err := c.retrieveMetaFromObject(obj)
 	if err != nil {
-		return err
+		return nil, "", "", err
 	}
 	cli, err := c.resourceClient(mapping.GroupVersionKind.GroupVersion())
 	if err != nil {
-		return err
+		return nil, "", "", err
 	}
-	helper := resource.NewHelper(cli, mapping)
-	return c.applyObject(helper, namespace, name, obj)
+	return resource.NewHelper(cli, mapping), namespace, name, nil
 }
 
 func (c *Client) applyObject(helper *resource.Helper, namespace, name string, obj runtime.Object) error {
@@ -503,7 +528,10 @@ func (c *Client) GetNodes(ctx context.Context) (*corev1.NodeList, error) {
 }
 
 // GetLogs returns logs for pod.
-func (c *Client) GetLogs(ctx context.Context, pod, container string) (string, error) {
+func (c *Client) GetLogs(ctx context.Context, namespace, pod, container string) (string, error) {
+	if namespace == "" {
+		namespace = c.namespace
+	}
 	defaultLogLines := int64(3000)
 	options := &corev1.PodLogOptions{}
 	if container != "" {
@@ -513,7 +541,7 @@ func (c *Client) GetLogs(ctx context.Context, pod, container string) (string, er
 	options.TailLines = &defaultLogLines
 	buf := &bytes.Buffer{}
 
-	req := c.clientset.CoreV1().Pods(c.namespace).GetLogs(pod, options)
+	req := c.clientset.CoreV1().Pods(namespace).GetLogs(pod, options)
 	podLogs, err := req.Stream(ctx)
 	if err != nil {
 		return buf.String(), err
@@ -528,11 +556,14 @@ func (c *Client) GetLogs(ctx context.Context, pod, container string) (string, er
 }
 
 // GetEvents retrieves events from a pod by a name.
-func (c *Client) GetEvents(ctx context.Context, name string) (string, error) {
-	pod, err := c.clientset.CoreV1().Pods(c.namespace).Get(ctx, name, metav1.GetOptions{})
+func (c *Client) GetEvents(ctx context.Context, namespace, name string) (string, error) {
+	if namespace == "" {
+		namespace = c.namespace
+	}
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		eventsInterface := c.clientset.CoreV1().Events(c.namespace)
-		selector := eventsInterface.GetFieldSelector(&name, &c.namespace, nil, nil)
+		eventsInterface := c.clientset.CoreV1().Events(namespace)
+		selector := eventsInterface.GetFieldSelector(&name, &namespace, nil, nil)
 		initialOpts := metav1.ListOptions{
 			FieldSelector: selector.String(),
 			Limit:         defaultChunkSize,
@@ -710,7 +741,7 @@ func translateTimestampSince(timestamp metav1.Time) string {
 // ApplyFile accepts manifest file contents, parses into []runtime.Object
 // and applies them against the cluster.
 func (c *Client) ApplyFile(fileBytes []byte) error {
-	objs, err := c.getObjects(fileBytes)
+	objs, err := getObjects(fileBytes)
 	if err != nil {
 		return err
 	}
@@ -726,7 +757,7 @@ func (c *Client) ApplyFile(fileBytes []byte) error {
 // ApplyManifestFile accepts manifest file contents, parses into []runtime.Object
 // and applies them against the cluster.
 func (c *Client) ApplyManifestFile(fileBytes []byte, namespace string) error {
-	objs, err := c.getObjects(fileBytes)
+	objs, err := getObjects(fileBytes)
 	if err != nil {
 		return err
 	}
@@ -746,7 +777,7 @@ func (c *Client) ApplyManifestFile(fileBytes []byte, namespace string) error {
 // DeleteManifestFile accepts manifest file contents, parses into []runtime.Object
 // and deletes them from the cluster.
 func (c *Client) DeleteManifestFile(fileBytes []byte, namespace string) error {
-	objs, err := c.getObjects(fileBytes)
+	objs, err := getObjects(fileBytes)
 	if err != nil {
 		return err
 	}
@@ -764,21 +795,11 @@ func (c *Client) DeleteManifestFile(fileBytes []byte, namespace string) error {
 }
 
 func (c *Client) applyTemplateCustomization(u *unstructured.Unstructured, namespace string) error {
-	if err := unstructured.SetNestedField(u.Object, namespace, "metadata", "namespace"); err != nil {
+	if err := setManifestNamespace(u, namespace); err != nil {
 		return err
 	}
 
-	kind, ok, err := unstructured.NestedString(u.Object, "kind")
-	if err != nil {
-		return err
-	}
-
-	if ok && kind == "ClusterRoleBinding" {
-		if err := c.updateClusterRoleBinding(u, namespace); err != nil {
-			return err
-		}
-	}
-	if ok && kind == "Service" {
+	if u.GetKind() == "Service" {
 		// During installation or upgrading of the everest backend
 		// CLI should keep spec.type untouched to prevent overriding of it.
 		if err := c.setEverestServiceType(u, namespace); err != nil {
@@ -789,6 +810,47 @@ func (c *Client) applyTemplateCustomization(u *unstructured.Unstructured, namesp
 	return nil
 }
 
+// CustomizeManifest decodes the manifest file and moves its objects to the namespace.
+// It performs the same customizations as ApplyManifestFile except the ones
+// which depend on the objects existing in the cluster.
+func CustomizeManifest(fileBytes []byte, namespace string) ([]*unstructured.Unstructured, error) {
+	objs, err := getObjects(fileBytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range objs {
+		if err := setManifestNamespace(o, namespace); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+// setManifestNamespace moves an object of the manifest file to the namespace.
+func setManifestNamespace(u *unstructured.Unstructured, namespace string) error {
+	if err := unstructured.SetNestedField(u.Object, namespace, "metadata", "namespace"); err != nil {
+		return err
+	}
+
+	if u.GetKind() == "ClusterRoleBinding" {
+		u.SetName(ClusterRoleBindingName(u.GetName(), namespace))
+		return updateClusterRoleBinding(u, namespace)
+	}
+
+	return nil
+}
+
+// ClusterRoleBindingName returns the name of the cluster role binding of a manifest
+// installed in the namespace. Cluster role bindings are shared by the installations
+// in all the namespaces so the ones outside of the default Everest namespaces get
+// names of their own instead of replacing the subjects of each other.
+func ClusterRoleBindingName(name, namespace string) string {
+	if namespace == defaultSystemNamespace || namespace == defaultMonitoringNamespace {
+		return name
+	}
+	return name + "-" + namespace
+}
+
 func (c *Client) setEverestServiceType(u *unstructured.Unstructured, namespace string) error {
 	s, err := c.GetService(context.Background(), namespace, "everest")
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -805,7 +867,7 @@ func (c *Client) setEverestServiceType(u *unstructured.Unstructured, namespace s
 	return nil
 }
 
-func (c *Client) updateClusterRoleBinding(u *unstructured.Unstructured, namespace string) error {
+func updateClusterRoleBinding(u *unstructured.Unstructured, namespace string) error {
 	sub, ok, err := unstructured.NestedFieldNoCopy(u.Object, "subjects")
 	if err != nil {
 		return err
@@ -833,7 +895,7 @@ func (c *Client) updateClusterRoleBinding(u *unstructured.Unstructured, namespac
 	return unstructured.SetNestedSlice(u.Object, subjects, "subjects")
 }
 
-func (c *Client) getObjects(f []byte) ([]*unstructured.Unstructured, error) {
+func getObjects(f []byte) ([]*unstructured.Unstructured, error) {
 	objs := []*unstructured.Unstructured{}
 	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(f), 100)
 	var err error
@@ -1259,6 +1321,19 @@ func (c *Client) ListSubscriptions(ctx context.Context, namespace string) (*v1al
 	return operatorClient.OperatorsV1alpha1().Subscriptions(namespace).List(ctx, metav1.ListOptions{})
 }
 
+// ListInstallPlans lists all the install plans in the namespace.
+func (c *Client) ListInstallPlans(ctx context.Context, namespace string) (*v1alpha1.InstallPlanList, error) {
+	c.rcLock.Lock()
+	defer c.rcLock.Unlock()
+
+	operatorClient, err := versioned.NewForConfig(c.restConfig)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("cannot create an operator client instance"))
+	}
+
+	return operatorClient.OperatorsV1alpha1().InstallPlans(namespace).List(ctx, metav1.ListOptions{})
+}
+
 // GetInstallPlan retrieves an OLM install plan by namespace and name.
 func (c *Client) GetInstallPlan(ctx context.Context, namespace string, name string) (*v1alpha1.InstallPlan, error) {
 	c.rcLock.Lock()
@@ -1355,6 +1430,16 @@ func (c *Client) GetClusterServiceVersion(
 	return operatorClient.OperatorsV1alpha1().ClusterServiceVersions(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
 }
 
+// GetCatalogSource retrieves an OLM catalog source by namespace and name.
+func (c *Client) GetCatalogSource(ctx context.Context, namespace, name string) (*v1alpha1.CatalogSource, error) {
+	operatorClient, err := versioned.NewForConfig(c.restConfig)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("cannot create an operator client instance"))
+	}
+
+	return operatorClient.OperatorsV1alpha1().CatalogSources(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
 // ListClusterServiceVersion list all CSVs for the given namespace.
 func (c *Client) ListClusterServiceVersion(
 	ctx context.Context,
@@ -1384,7 +1469,7 @@ func (c *Client) DeleteClusterServiceVersion(
 // DeleteFile accepts manifest file contents parses into []runtime.Object
 // and deletes them from the cluster.
 func (c *Client) DeleteFile(fileBytes []byte) error {
-	objs, err := c.getObjects(fileBytes)
+	objs, err := getObjects(fileBytes)
 	if err != nil {
 		return err
 	}
@@ -1402,6 +1487,19 @@ func (c *Client) GetService(ctx context.Context, namespace, name string) (*corev
 	return c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+// GetConfigMap returns config map by name and namespace.
+func (c *Client) GetConfigMap(ctx context.Context, name, namespace string) (*corev1.ConfigMap, error) {
+	return c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// CreateSelfSubjectAccessReview checks whether the current user can perform an action.
+func (c *Client) CreateSelfSubjectAccessReview(
+	ctx context.Context,
+	review *authorizationv1.SelfSubjectAccessReview,
+) (*authorizationv1.SelfSubjectAccessReview, error) {
+	return c.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+}
+
 // GetClusterRoleBinding returns cluster role binding by given name.
 func (c *Client) GetClusterRoleBinding(ctx context.Context, name string) (*rbacv1.ClusterRoleBinding, error) {
 	return c.clientset.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
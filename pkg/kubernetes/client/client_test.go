@@ -642,3 +642,44 @@ func TestListCRs(t *testing.T) {
 		}(test))
 	}
 }
+
+func TestCustomizeManifestClusterRoleBindingPerTenant(t *testing.T) {
+	t.Parallel()
+
+	manifest := []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: everest-admin-cluster-role-binding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: everest-admin-cluster-role
+subjects:
+- kind: ServiceAccount
+  name: everest-admin
+  namespace: everest-system
+`)
+
+	tests := []struct {
+		namespace string
+		wantName  string
+	}{
+		{namespace: "everest-system", wantName: "everest-admin-cluster-role-binding"},
+		{namespace: "tenant-a", wantName: "everest-admin-cluster-role-binding-tenant-a"},
+		{namespace: "tenant-b", wantName: "everest-admin-cluster-role-binding-tenant-b"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.namespace, func(t *testing.T) {
+			t.Parallel()
+			objs, err := CustomizeManifest(manifest, tt.namespace)
+			require.NoError(t, err)
+			require.Len(t, objs, 1)
+			assert.Equal(t, tt.wantName, objs[0].GetName())
+			subjects, _, err := unstructured.NestedSlice(objs[0].Object, "subjects")
+			require.NoError(t, err)
+			require.Len(t, subjects, 1)
+			assert.Equal(t, tt.namespace, subjects[0].(map[string]interface{})["namespace"]) //nolint:forcetypeassert
+		})
+	}
+}
@@ -0,0 +1,568 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	v1 "github.com/operator-framework/api/pkg/operators/v1"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// PlanActionCreate is recorded when an object would be created.
+	PlanActionCreate = "create"
+	// PlanActionPatch is recorded when an existing object would be changed.
+	PlanActionPatch = "patch"
+	// PlanActionDelete is recorded when an object would be deleted.
+	PlanActionDelete = "delete"
+	// PlanActionApprove is recorded when an install plan would be approved.
+	PlanActionApprove = "approve"
+
+	// dryRunInstallPlanPrefix prefixes the names of the install plans
+	// reported for subscriptions which exist only in the plan.
+	dryRunInstallPlanPrefix = "install-"
+)
+
+type (
+	// PlanAction is a single change to the cluster recorded in dry-run mode.
+	PlanAction struct {
+		Action    string `json:"action"`
+		Kind      string `json:"kind"`
+		Namespace string `json:"namespace,omitempty"`
+		Name      string `json:"name"`
+	}
+
+	// Plan holds the changes a command would make to the cluster.
+	Plan struct {
+		Actions []PlanAction `json:"actions"`
+	}
+
+	// DryRunClient wraps a KubeClientConnector and records the calls
+	// changing the cluster instead of running them. Read calls are passed
+	// to the wrapped client and waits return immediately.
+	DryRunClient struct {
+		KubeClientConnector
+
+		mu            sync.Mutex
+		actions       []PlanAction
+		recorded      map[string]struct{}
+		namespaces    map[string]struct{}
+		subscriptions map[types.NamespacedName]*v1alpha1.Subscription
+		installPlans  map[types.NamespacedName]struct{}
+		groups        map[types.NamespacedName]*v1.OperatorGroup
+		objects       map[string]*unstructured.Unstructured
+	}
+)
+
+// NewDryRunClient returns a new DryRunClient wrapping the provided client.
+func NewDryRunClient(c KubeClientConnector) *DryRunClient {
+	return &DryRunClient{
+		KubeClientConnector: c,
+		recorded:            make(map[string]struct{}),
+		namespaces:          make(map[string]struct{}),
+		subscriptions:       make(map[types.NamespacedName]*v1alpha1.Subscription),
+		installPlans:        make(map[types.NamespacedName]struct{}),
+		groups:              make(map[types.NamespacedName]*v1.OperatorGroup),
+		objects:             make(map[string]*unstructured.Unstructured),
+	}
+}
+
+// Plan returns the changes recorded so far.
+func (d *DryRunClient) Plan() *Plan {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	actions := make([]PlanAction, len(d.actions))
+	copy(actions, d.actions)
+	return &Plan{Actions: actions}
+}
+
+// String returns the plan in a diff-like format.
+func (p Plan) String() string {
+	if len(p.Actions) == 0 {
+		return "No changes. The cluster is up to date."
+	}
+
+	symbols := map[string]string{
+		PlanActionCreate:  "+",
+		PlanActionPatch:   "~",
+		PlanActionDelete:  "-",
+		PlanActionApprove: ">",
+	}
+	counts := make(map[string]int)
+
+	var b strings.Builder
+	for _, a := range p.Actions {
+		counts[a.Action]++
+		name := a.Name
+		if a.Namespace != "" {
+			name = a.Namespace + "/" + a.Name
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", symbols[a.Action], a.Kind, name)
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to patch, %d to delete, %d to approve.",
+		counts[PlanActionCreate], counts[PlanActionPatch], counts[PlanActionDelete], counts[PlanActionApprove],
+	)
+
+	return b.String()
+}
+
+func (d *DryRunClient) record(action, kind, namespace, name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Objects applied several times during a run are reported once.
+	key := strings.Join([]string{action, kind, namespace, name}, "/")
+	if action == PlanActionPatch {
+		if _, ok := d.recorded[strings.Join([]string{PlanActionCreate, kind, namespace, name}, "/")]; ok {
+			return
+		}
+	}
+	if _, ok := d.recorded[key]; ok {
+		return
+	}
+	d.recorded[key] = struct{}{}
+	d.actions = append(d.actions, PlanAction{
+		Action:    action,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+	})
+}
+
+func (d *DryRunClient) recordApply(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+
+	action := PlanActionCreate
+	if exists, err := d.KubeClientConnector.ObjectExists(obj); err == nil && exists {
+		action = PlanActionPatch
+	}
+	d.record(action, kind, accessor.GetNamespace(), accessor.GetName())
+
+	// The object is converted through JSON since the default unstructured
+	// converter can't handle some of the OLM types.
+	j, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(j); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.objects[objectKey(kind, accessor.GetNamespace(), accessor.GetName())] = u
+	d.mu.Unlock()
+
+	return nil
+}
+
+func (d *DryRunClient) recordDelete(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if exists, err := d.KubeClientConnector.ObjectExists(obj); err == nil && !exists {
+		return nil
+	}
+	d.record(PlanActionDelete, obj.GetObjectKind().GroupVersionKind().Kind, accessor.GetNamespace(), accessor.GetName())
+	return nil
+}
+
+func objectKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// ApplyObject records creation or update of the object.
+func (d *DryRunClient) ApplyObject(obj runtime.Object) error {
+	return d.recordApply(obj)
+}
+
+// CreateObject records creation of the object.
+// It fails with an AlreadyExists error if the object exists in the cluster or in the plan.
+func (d *DryRunClient) CreateObject(obj runtime.Object) error {
+	exists, err := d.ObjectExists(obj)
+	if err != nil {
+		return err
+	}
+	if exists {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		return apierrors.NewAlreadyExists(schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}, accessor.GetName())
+	}
+	return d.recordApply(obj)
+}
+
+// DeleteObject records deletion of the object.
+func (d *DryRunClient) DeleteObject(obj runtime.Object) error {
+	return d.recordDelete(obj)
+}
+
+// ObjectExists checks if the object exists in the cluster or in the plan.
+func (d *DryRunClient) ObjectExists(obj runtime.Object) (bool, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err
+	}
+	d.mu.Lock()
+	_, ok := d.objects[objectKey(obj.GetObjectKind().GroupVersionKind().Kind, accessor.GetNamespace(), accessor.GetName())]
+	d.mu.Unlock()
+	if ok {
+		return true, nil
+	}
+	return d.KubeClientConnector.ObjectExists(obj)
+}
+
+// ApplyFile records creation or update of the objects in the file.
+func (d *DryRunClient) ApplyFile(fileBytes []byte) error {
+	objs, err := getObjects(fileBytes)
+	if err != nil {
+		return err
+	}
+	for _, o := range objs {
+		if err := d.recordApply(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyManifestFile records creation or update of the objects in the manifest file.
+func (d *DryRunClient) ApplyManifestFile(fileBytes []byte, namespace string) error {
+	objs, err := CustomizeManifest(fileBytes, namespace)
+	if err != nil {
+		return err
+	}
+	for _, o := range objs {
+		if err := d.recordApply(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteManifestFile records deletion of the objects in the manifest file.
+func (d *DryRunClient) DeleteManifestFile(fileBytes []byte, namespace string) error {
+	objs, err := CustomizeManifest(fileBytes, namespace)
+	if err != nil {
+		return err
+	}
+	for _, o := range objs {
+		if err := d.recordDelete(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteFile records deletion of the objects in the file.
+func (d *DryRunClient) DeleteFile(fileBytes []byte) error {
+	objs, err := getObjects(fileBytes)
+	if err != nil {
+		return err
+	}
+	for _, o := range objs {
+		if err := d.recordDelete(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateNamespace records creation of the namespace.
+func (d *DryRunClient) CreateNamespace(name string) error {
+	if err := d.recordApply(&corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.namespaces[name] = struct{}{}
+	d.mu.Unlock()
+	return nil
+}
+
+// GetNamespace returns a namespace from the cluster or from the plan.
+func (d *DryRunClient) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	ns, err := d.KubeClientConnector.GetNamespace(ctx, name)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return ns, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.namespaces[name]; ok {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+	}
+	return ns, err
+}
+
+// DeleteNamespace records deletion of the namespace.
+func (d *DryRunClient) DeleteNamespace(_ context.Context, name string) error {
+	return d.recordDelete(&corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	})
+}
+
+// DeletePod records deletion of the pod.
+func (d *DryRunClient) DeletePod(_ context.Context, namespace, name string) error {
+	d.record(PlanActionDelete, "Pod", namespace, name)
+	return nil
+}
+
+// GetOperatorGroup returns an operator group from the plan or from the cluster.
+func (d *DryRunClient) GetOperatorGroup(ctx context.Context, namespace, name string) (*v1.OperatorGroup, error) {
+	d.mu.Lock()
+	og, ok := d.groups[types.NamespacedName{Namespace: namespace, Name: name}]
+	d.mu.Unlock()
+	if ok {
+		return og.DeepCopy(), nil
+	}
+	return d.KubeClientConnector.GetOperatorGroup(ctx, namespace, name)
+}
+
+// CreateOperatorGroup records creation of the operator group.
+func (d *DryRunClient) CreateOperatorGroup(_ context.Context, namespace, name string, targetNamespaces []string) (*v1.OperatorGroup, error) {
+	og := &v1.OperatorGroup{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.OperatorGroupKind},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       v1.OperatorGroupSpec{TargetNamespaces: targetNamespaces},
+	}
+	d.record(PlanActionCreate, og.Kind, namespace, name)
+	d.mu.Lock()
+	d.groups[types.NamespacedName{Namespace: namespace, Name: name}] = og
+	d.mu.Unlock()
+	return og.DeepCopy(), nil
+}
+
+// CreateSubscription records creation of the subscription.
+func (d *DryRunClient) CreateSubscription(_ context.Context, namespace string, subscription *v1alpha1.Subscription) (*v1alpha1.Subscription, error) {
+	d.record(PlanActionCreate, v1alpha1.SubscriptionKind, namespace, subscription.Name)
+	d.storeSubscription(namespace, subscription)
+	return subscription, nil
+}
+
+// UpdateSubscription records update of the subscription.
+func (d *DryRunClient) UpdateSubscription(_ context.Context, namespace string, subscription *v1alpha1.Subscription) (*v1alpha1.Subscription, error) {
+	d.record(PlanActionPatch, v1alpha1.SubscriptionKind, namespace, subscription.Name)
+	d.mu.Lock()
+	_, ok := d.subscriptions[types.NamespacedName{Namespace: namespace, Name: subscription.Name}]
+	d.mu.Unlock()
+	if ok {
+		d.storeSubscription(namespace, subscription)
+	}
+	return subscription, nil
+}
+
+// CreateSubscriptionForCatalog records creation of the subscription.
+func (d *DryRunClient) CreateSubscriptionForCatalog(_ context.Context, namespace, name, catalogNamespace, catalog,
+	packageName, channel, startingCSV string, approval v1alpha1.Approval,
+) (*v1alpha1.Subscription, error) {
+	subscription := &v1alpha1.Subscription{
+		TypeMeta:   metav1.TypeMeta{Kind: v1alpha1.SubscriptionKind, APIVersion: v1alpha1.SubscriptionCRDAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: &v1alpha1.SubscriptionSpec{
+			CatalogSource:          catalog,
+			CatalogSourceNamespace: catalogNamespace,
+			Package:                packageName,
+			Channel:                channel,
+			StartingCSV:            startingCSV,
+			InstallPlanApproval:    approval,
+		},
+	}
+	d.record(PlanActionCreate, v1alpha1.SubscriptionKind, namespace, name)
+	d.storeSubscription(namespace, subscription)
+	return subscription, nil
+}
+
+func (d *DryRunClient) storeSubscription(namespace string, subscription *v1alpha1.Subscription) {
+	s := subscription.DeepCopy()
+	// The subscription exists only in the plan so we point it to a placeholder
+	// install plan to let the callers continue as if OLM resolved it.
+	ipName := dryRunInstallPlanPrefix + subscription.Name
+	s.Status.InstallPlanRef = &corev1.ObjectReference{Namespace: namespace, Name: ipName}
+	s.Status.Install = &v1alpha1.InstallPlanReference{Name: ipName}
+
+	d.mu.Lock()
+	d.subscriptions[types.NamespacedName{Namespace: namespace, Name: subscription.Name}] = s
+	d.installPlans[types.NamespacedName{Namespace: namespace, Name: ipName}] = struct{}{}
+	d.mu.Unlock()
+}
+
+// GetSubscription returns a subscription from the plan or from the cluster.
+func (d *DryRunClient) GetSubscription(ctx context.Context, namespace, name string) (*v1alpha1.Subscription, error) {
+	d.mu.Lock()
+	s, ok := d.subscriptions[types.NamespacedName{Namespace: namespace, Name: name}]
+	d.mu.Unlock()
+	if ok {
+		return s.DeepCopy(), nil
+	}
+	return d.KubeClientConnector.GetSubscription(ctx, namespace, name)
+}
+
+// GetInstallPlan returns an install plan from the cluster. A placeholder
+// is returned for subscriptions which exist only in the plan.
+func (d *DryRunClient) GetInstallPlan(ctx context.Context, namespace string, name string) (*v1alpha1.InstallPlan, error) {
+	if d.isPlaceholderInstallPlan(namespace, name) {
+		return &v1alpha1.InstallPlan{
+			TypeMeta:   metav1.TypeMeta{Kind: v1alpha1.InstallPlanKind, APIVersion: v1alpha1.InstallPlanAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		}, nil
+	}
+	return d.KubeClientConnector.GetInstallPlan(ctx, namespace, name)
+}
+
+// UpdateInstallPlan records approval of the install plan.
+func (d *DryRunClient) UpdateInstallPlan(ctx context.Context, namespace string, installPlan *v1alpha1.InstallPlan) (*v1alpha1.InstallPlan, error) {
+	if !installPlan.Spec.Approved {
+		d.record(PlanActionPatch, v1alpha1.InstallPlanKind, namespace, installPlan.Name)
+		return installPlan, nil
+	}
+	if !d.isPlaceholderInstallPlan(namespace, installPlan.Name) {
+		ip, err := d.KubeClientConnector.GetInstallPlan(ctx, namespace, installPlan.Name)
+		if err == nil && ip.Spec.Approved {
+			// Already approved. Nothing changes.
+			return installPlan, nil
+		}
+	}
+
+	d.record(PlanActionApprove, v1alpha1.InstallPlanKind, namespace, installPlan.Name)
+	return installPlan, nil
+}
+
+func (d *DryRunClient) isPlaceholderInstallPlan(namespace, name string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.installPlans[types.NamespacedName{Namespace: namespace, Name: name}]
+	return ok
+}
+
+// GetClusterRoleBinding returns a cluster role binding from the cluster or from the plan.
+func (d *DryRunClient) GetClusterRoleBinding(ctx context.Context, name string) (*rbacv1.ClusterRoleBinding, error) {
+	crb, err := d.KubeClientConnector.GetClusterRoleBinding(ctx, name)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return crb, err
+	}
+
+	d.mu.Lock()
+	var u *unstructured.Unstructured
+	for _, o := range d.objects {
+		if o.GetKind() == "ClusterRoleBinding" && o.GetName() == name {
+			u = o
+			break
+		}
+	}
+	d.mu.Unlock()
+	if u == nil {
+		return crb, err
+	}
+
+	res := &rbacv1.ClusterRoleBinding{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteClusterServiceVersion records deletion of the cluster service version.
+func (d *DryRunClient) DeleteClusterServiceVersion(_ context.Context, key types.NamespacedName) error {
+	d.record(PlanActionDelete, v1alpha1.ClusterServiceVersionKind, key.Namespace, key.Name)
+	return nil
+}
+
+// CreateBackupStorage records creation of the backup storage.
+func (d *DryRunClient) CreateBackupStorage(_ context.Context, storage *everestv1alpha1.BackupStorage) error {
+	d.record(PlanActionCreate, "BackupStorage", storage.Namespace, storage.Name)
+	return nil
+}
+
+// UpdateBackupStorage records update of the backup storage.
+func (d *DryRunClient) UpdateBackupStorage(_ context.Context, storage *everestv1alpha1.BackupStorage) error {
+	d.record(PlanActionPatch, "BackupStorage", storage.Namespace, storage.Name)
+	return nil
+}
+
+// DeleteBackupStorage records deletion of the backup storage.
+func (d *DryRunClient) DeleteBackupStorage(_ context.Context, namespace, name string) error {
+	d.record(PlanActionDelete, "BackupStorage", namespace, name)
+	return nil
+}
+
+// CreateMonitoringConfig records creation of the monitoring config.
+func (d *DryRunClient) CreateMonitoringConfig(_ context.Context, config *everestv1alpha1.MonitoringConfig) error {
+	d.record(PlanActionCreate, "MonitoringConfig", config.Namespace, config.Name)
+	return nil
+}
+
+// UpdateMonitoringConfig records update of the monitoring config.
+func (d *DryRunClient) UpdateMonitoringConfig(_ context.Context, config *everestv1alpha1.MonitoringConfig) error {
+	d.record(PlanActionPatch, "MonitoringConfig", config.Namespace, config.Name)
+	return nil
+}
+
+// DeleteMonitoringConfig records deletion of the monitoring config.
+func (d *DryRunClient) DeleteMonitoringConfig(_ context.Context, namespace, name string) error {
+	d.record(PlanActionDelete, "MonitoringConfig", namespace, name)
+	return nil
+}
+
+// DeleteAllMonitoringResources records deletion of the monitoring resources.
+func (d *DryRunClient) DeleteAllMonitoringResources(_ context.Context, namespace string) error {
+	d.record(PlanActionDelete, "VMAgent", namespace, "*")
+	return nil
+}
+
+// DoCSVWait returns immediately in dry-run mode.
+func (d *DryRunClient) DoCSVWait(context.Context, types.NamespacedName) error {
+	return nil
+}
+
+// GetSubscriptionCSV returns the subscription key in dry-run mode.
+func (d *DryRunClient) GetSubscriptionCSV(_ context.Context, subKey types.NamespacedName) (types.NamespacedName, error) {
+	return subKey, nil
+}
+
+// DoRolloutWait returns immediately in dry-run mode.
+func (d *DryRunClient) DoRolloutWait(context.Context, types.NamespacedName) error {
+	return nil
+}
+
+// DoPackageWait returns immediately in dry-run mode.
+func (d *DryRunClient) DoPackageWait(context.Context, string, string) error {
+	return nil
+}
@@ -0,0 +1,74 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readOnlyMethods lists the methods of KubeClientConnector which do not change
+// the cluster although their names do not start with Get or List.
+var readOnlyMethods = map[string]struct{}{ //nolint:gochecknoglobals
+	"ClusterName":                   {},
+	"Config":                        {},
+	"CreateSelfSubjectAccessReview": {},
+	"GenerateKubeConfigWithToken":   {},
+}
+
+// TestDryRunClientOverridesMutatingMethods makes sure new methods changing
+// the cluster are not passed to the wrapped client in dry-run mode.
+func TestDryRunClientOverridesMutatingMethods(t *testing.T) {
+	t.Parallel()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "dry_run.go", nil, 0)
+	require.NoError(t, err)
+
+	overridden := map[string]struct{}{}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+			continue
+		}
+		star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := star.X.(*ast.Ident); ok && ident.Name == "DryRunClient" {
+			overridden[fn.Name.Name] = struct{}{}
+		}
+	}
+
+	iface := reflect.TypeOf((*KubeClientConnector)(nil)).Elem()
+	for i := 0; i < iface.NumMethod(); i++ {
+		name := iface.Method(i).Name
+		if strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List") {
+			continue
+		}
+		if _, ok := readOnlyMethods[name]; ok {
+			continue
+		}
+		_, ok := overridden[name]
+		assert.Truef(t, ok, "%s may change the cluster and must be overridden by DryRunClient", name)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	packagev1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
 	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -58,6 +59,11 @@ type KubeClientConnector interface {
 	DeleteObject(obj runtime.Object) error
 	// ApplyObject applies object.
 	ApplyObject(obj runtime.Object) error
+	// CreateObject creates object.
+	// Unlike ApplyObject it fails with an AlreadyExists error if the object exists.
+	CreateObject(obj runtime.Object) error
+	// ObjectExists checks if the object exists in the k8s cluster.
+	ObjectExists(obj runtime.Object) (bool, error)
 	// Config returns stored *rest.Config.
 	Config() *rest.Config
 	// GetPersistentVolumes returns Persistent Volumes available in the cluster.
@@ -71,9 +77,9 @@ type KubeClientConnector interface {
 	// GetNodes returns list of nodes.
 	GetNodes(ctx context.Context) (*corev1.NodeList, error)
 	// GetLogs returns logs for pod.
-	GetLogs(ctx context.Context, pod, container string) (string, error)
+	GetLogs(ctx context.Context, namespace, pod, container string) (string, error)
 	// GetEvents retrieves events from a pod by a name.
-	GetEvents(ctx context.Context, name string) (string, error)
+	GetEvents(ctx context.Context, namespace, name string) (string, error)
 	// ApplyFile accepts manifest file contents, parses into []runtime.Object
 	// and applies them against the cluster.
 	ApplyFile(fileBytes []byte) error
@@ -105,6 +111,8 @@ type KubeClientConnector interface {
 	GetSubscription(ctx context.Context, namespace, name string) (*v1alpha1.Subscription, error)
 	// ListSubscriptions all the subscriptions in the namespace.
 	ListSubscriptions(ctx context.Context, namespace string) (*v1alpha1.SubscriptionList, error)
+	// ListInstallPlans lists all the install plans in the namespace.
+	ListInstallPlans(ctx context.Context, namespace string) (*v1alpha1.InstallPlanList, error)
 	// GetInstallPlan retrieves an OLM install plan by namespace and name.
 	GetInstallPlan(ctx context.Context, namespace string, name string) (*v1alpha1.InstallPlan, error)
 	// DoPackageWait for the package to be available in OLM.
@@ -119,6 +127,8 @@ type KubeClientConnector interface {
 	ListCRs(ctx context.Context, namespace string, gvr schema.GroupVersionResource, labelSelector *metav1.LabelSelector) (*unstructured.UnstructuredList, error)
 	// GetClusterServiceVersion retrieve a CSV by namespaced name.
 	GetClusterServiceVersion(ctx context.Context, key types.NamespacedName) (*v1alpha1.ClusterServiceVersion, error)
+	// GetCatalogSource retrieves an OLM catalog source by namespace and name.
+	GetCatalogSource(ctx context.Context, namespace, name string) (*v1alpha1.CatalogSource, error)
 	// ListClusterServiceVersion list all CSVs for the given namespace.
 	ListClusterServiceVersion(ctx context.Context, namespace string) (*v1alpha1.ClusterServiceVersionList, error)
 	// DeleteClusterServiceVersion deletes a CSV by namespaced name.
@@ -128,6 +138,10 @@ type KubeClientConnector interface {
 	DeleteFile(fileBytes []byte) error
 	// GetService returns k8s service by provided namespace and name.
 	GetService(ctx context.Context, namespace, name string) (*corev1.Service, error)
+	// GetConfigMap returns config map by name and namespace.
+	GetConfigMap(ctx context.Context, name, namespace string) (*corev1.ConfigMap, error)
+	// CreateSelfSubjectAccessReview checks whether the current user can perform an action.
+	CreateSelfSubjectAccessReview(ctx context.Context, review *authorizationv1.SelfSubjectAccessReview) (*authorizationv1.SelfSubjectAccessReview, error)
 	// GetClusterRoleBinding returns cluster role binding by given name.
 	GetClusterRoleBinding(ctx context.Context, name string) (*rbacv1.ClusterRoleBinding, error)
 	// ListDatabaseClusters returns list of managed database clusters.
@@ -11,6 +11,7 @@ import (
 	v1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
 	mock "github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -175,6 +176,24 @@ func (_m *MockKubeClientConnector) CreateNamespace(name string) error {
 	return r0
 }
 
+// CreateObject provides a mock function with given fields: obj
+func (_m *MockKubeClientConnector) CreateObject(obj runtime.Object) error {
+	ret := _m.Called(obj)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateObject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(runtime.Object) error); ok {
+		r0 = rf(obj)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateOperatorGroup provides a mock function with given fields: ctx, namespace, name, targetNamespaces
 func (_m *MockKubeClientConnector) CreateOperatorGroup(ctx context.Context, namespace string, name string, targetNamespaces []string) (*v1.OperatorGroup, error) {
 	ret := _m.Called(ctx, namespace, name, targetNamespaces)
@@ -205,6 +224,36 @@ func (_m *MockKubeClientConnector) CreateOperatorGroup(ctx context.Context, name
 	return r0, r1
 }
 
+// CreateSelfSubjectAccessReview provides a mock function with given fields: ctx, review
+func (_m *MockKubeClientConnector) CreateSelfSubjectAccessReview(ctx context.Context, review *authorizationv1.SelfSubjectAccessReview) (*authorizationv1.SelfSubjectAccessReview, error) {
+	ret := _m.Called(ctx, review)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSelfSubjectAccessReview")
+	}
+
+	var r0 *authorizationv1.SelfSubjectAccessReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *authorizationv1.SelfSubjectAccessReview) (*authorizationv1.SelfSubjectAccessReview, error)); ok {
+		return rf(ctx, review)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *authorizationv1.SelfSubjectAccessReview) *authorizationv1.SelfSubjectAccessReview); ok {
+		r0 = rf(ctx, review)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authorizationv1.SelfSubjectAccessReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *authorizationv1.SelfSubjectAccessReview) error); ok {
+		r1 = rf(ctx, review)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateSubscription provides a mock function with given fields: ctx, namespace, subscription
 func (_m *MockKubeClientConnector) CreateSubscription(ctx context.Context, namespace string, subscription *operatorsv1alpha1.Subscription) (*operatorsv1alpha1.Subscription, error) {
 	ret := _m.Called(ctx, namespace, subscription)
@@ -541,6 +590,36 @@ func (_m *MockKubeClientConnector) GetBackupStorage(ctx context.Context, namespa
 	return r0, r1
 }
 
+// GetCatalogSource provides a mock function with given fields: ctx, namespace, name
+func (_m *MockKubeClientConnector) GetCatalogSource(ctx context.Context, namespace string, name string) (*operatorsv1alpha1.CatalogSource, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCatalogSource")
+	}
+
+	var r0 *operatorsv1alpha1.CatalogSource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*operatorsv1alpha1.CatalogSource, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *operatorsv1alpha1.CatalogSource); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operatorsv1alpha1.CatalogSource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetClusterRoleBinding provides a mock function with given fields: ctx, name
 func (_m *MockKubeClientConnector) GetClusterRoleBinding(ctx context.Context, name string) (*rbacv1.ClusterRoleBinding, error) {
 	ret := _m.Called(ctx, name)
@@ -601,6 +680,36 @@ func (_m *MockKubeClientConnector) GetClusterServiceVersion(ctx context.Context,
 	return r0, r1
 }
 
+// GetConfigMap provides a mock function with given fields: ctx, name, namespace
+func (_m *MockKubeClientConnector) GetConfigMap(ctx context.Context, name string, namespace string) (*corev1.ConfigMap, error) {
+	ret := _m.Called(ctx, name, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConfigMap")
+	}
+
+	var r0 *corev1.ConfigMap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*corev1.ConfigMap, error)); ok {
+		return rf(ctx, name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *corev1.ConfigMap); ok {
+		r0 = rf(ctx, name, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*corev1.ConfigMap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDatabaseCluster provides a mock function with given fields: ctx, namespace, name
 func (_m *MockKubeClientConnector) GetDatabaseCluster(ctx context.Context, namespace string, name string) (*v1alpha1.DatabaseCluster, error) {
 	ret := _m.Called(ctx, namespace, name)
@@ -751,9 +860,9 @@ func (_m *MockKubeClientConnector) GetDeployment(ctx context.Context, name strin
 	return r0, r1
 }
 
-// GetEvents provides a mock function with given fields: ctx, name
-func (_m *MockKubeClientConnector) GetEvents(ctx context.Context, name string) (string, error) {
-	ret := _m.Called(ctx, name)
+// GetEvents provides a mock function with given fields: ctx, namespace, name
+func (_m *MockKubeClientConnector) GetEvents(ctx context.Context, namespace string, name string) (string, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetEvents")
@@ -761,17 +870,17 @@ func (_m *MockKubeClientConnector) GetEvents(ctx context.Context, name string) (
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, namespace, name)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -809,9 +918,9 @@ func (_m *MockKubeClientConnector) GetInstallPlan(ctx context.Context, namespace
 	return r0, r1
 }
 
-// GetLogs provides a mock function with given fields: ctx, pod, container
-func (_m *MockKubeClientConnector) GetLogs(ctx context.Context, pod string, container string) (string, error) {
-	ret := _m.Called(ctx, pod, container)
+// GetLogs provides a mock function with given fields: ctx, namespace, pod, container
+func (_m *MockKubeClientConnector) GetLogs(ctx context.Context, namespace string, pod string, container string) (string, error) {
+	ret := _m.Called(ctx, namespace, pod, container)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLogs")
@@ -819,17 +928,17 @@ func (_m *MockKubeClientConnector) GetLogs(ctx context.Context, pod string, cont
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return rf(ctx, pod, container)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, namespace, pod, container)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, pod, container)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, namespace, pod, container)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, pod, container)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, namespace, pod, container)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1525,6 +1634,36 @@ func (_m *MockKubeClientConnector) ListDeployments(ctx context.Context, namespac
 	return r0, r1
 }
 
+// ListInstallPlans provides a mock function with given fields: ctx, namespace
+func (_m *MockKubeClientConnector) ListInstallPlans(ctx context.Context, namespace string) (*operatorsv1alpha1.InstallPlanList, error) {
+	ret := _m.Called(ctx, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListInstallPlans")
+	}
+
+	var r0 *operatorsv1alpha1.InstallPlanList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*operatorsv1alpha1.InstallPlanList, error)); ok {
+		return rf(ctx, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *operatorsv1alpha1.InstallPlanList); ok {
+		r0 = rf(ctx, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*operatorsv1alpha1.InstallPlanList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListMonitoringConfigs provides a mock function with given fields: ctx, namespace
 func (_m *MockKubeClientConnector) ListMonitoringConfigs(ctx context.Context, namespace string) (*v1alpha1.MonitoringConfigList, error) {
 	ret := _m.Called(ctx, namespace)
@@ -1645,6 +1784,34 @@ func (_m *MockKubeClientConnector) ListSubscriptions(ctx context.Context, namesp
 	return r0, r1
 }
 
+// ObjectExists provides a mock function with given fields: obj
+func (_m *MockKubeClientConnector) ObjectExists(obj runtime.Object) (bool, error) {
+	ret := _m.Called(obj)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ObjectExists")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(runtime.Object) (bool, error)); ok {
+		return rf(obj)
+	}
+	if rf, ok := ret.Get(0).(func(runtime.Object) bool); ok {
+		r0 = rf(obj)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(runtime.Object) error); ok {
+		r1 = rf(obj)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateBackupStorage provides a mock function with given fields: ctx, storage
 func (_m *MockKubeClientConnector) UpdateBackupStorage(ctx context.Context, storage *v1alpha1.BackupStorage) error {
 	ret := _m.Called(ctx, storage)
@@ -1746,8 +1913,7 @@ func (_m *MockKubeClientConnector) UpdateSubscription(ctx context.Context, names
 func NewMockKubeClientConnector(t interface {
 	mock.TestingT
 	Cleanup(func())
-},
-) *MockKubeClientConnector {
+}) *MockKubeClientConnector {
 	mock := &MockKubeClientConnector{}
 	mock.Mock.Test(t)
 
@@ -0,0 +1,57 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterTypeFromNodes(t *testing.T) {
+	t.Parallel()
+
+	node := func(providerID string, labels map[string]string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+		}
+	}
+
+	tcases := []struct {
+		name string
+		node corev1.Node
+		want ClusterType
+	}{
+		{"minikube", node("", map[string]string{"minikube.k8s.io/name": "minikube"}), ClusterTypeMinikube},
+		{"kind", node("kind://docker/kind/kind-control-plane", nil), ClusterTypeKind},
+		{"k3s provider", node("k3s://server", nil), ClusterTypeK3s},
+		{"k3s label", node("", map[string]string{"node.kubernetes.io/instance-type": "k3s"}), ClusterTypeK3s},
+		{"eks", node("aws:///eu-west-1a/i-0123", map[string]string{"eks.amazonaws.com/nodegroup": "default"}), ClusterTypeEKS},
+		{"gke", node("gce://project/europe-west1-b/node", nil), ClusterTypeGKE},
+		{"aks", node("azure:///subscriptions/id/resourceGroups/rg", nil), ClusterTypeAKS},
+		{"unknown", node("", nil), ClusterTypeUnknown},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, clusterTypeFromNodes([]corev1.Node{tc.node}))
+		})
+	}
+}
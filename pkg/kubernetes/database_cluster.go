@@ -18,11 +18,29 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
 
 	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// engineResources maps the engine types to the custom resources of their operators.
+var engineResources = map[everestv1alpha1.EngineType]schema.GroupVersionResource{ //nolint:gochecknoglobals
+	everestv1alpha1.DatabaseEnginePXC:        {Group: "pxc.percona.com", Version: "v1", Resource: "perconaxtradbclusters"},
+	everestv1alpha1.DatabaseEnginePSMDB:      {Group: "psmdb.percona.com", Version: "v1", Resource: "perconaservermongodbs"},
+	everestv1alpha1.DatabaseEnginePostgresql: {Group: "pgv2.percona.com", Version: "v2", Resource: "perconapgclusters"},
+}
+
+// databaseClusterResource is the custom resource of the database clusters.
+var databaseClusterResource = schema.GroupVersionResource{ //nolint:gochecknoglobals
+	Group:    everestv1alpha1.GroupVersion.Group,
+	Version:  everestv1alpha1.GroupVersion.Version,
+	Resource: "databaseclusters",
+}
+
 // ListDatabaseClusters returns list of managed database clusters.
 func (k *Kubernetes) ListDatabaseClusters(ctx context.Context, namespace string) (*everestv1alpha1.DatabaseClusterList, error) {
 	return k.client.ListDatabaseClusters(ctx, namespace, metav1.ListOptions{})
@@ -33,17 +51,40 @@ func (k *Kubernetes) GetDatabaseCluster(ctx context.Context, namespace, name str
 	return k.client.GetDatabaseCluster(ctx, namespace, name)
 }
 
+// GetDatabaseClusterObservedGeneration returns the generation of the database cluster
+// the operator has acted on. It returns zero if the operator does not report
+// the observed generation in the status.
+func (k *Kubernetes) GetDatabaseClusterObservedGeneration(ctx context.Context, namespace, name string) (int64, error) {
+	list, err := k.client.ListCRs(ctx, namespace, databaseClusterResource, nil)
+	if err != nil {
+		return 0, err
+	}
+	for _, cr := range list.Items {
+		if cr.GetName() != name {
+			continue
+		}
+		generation, _, err := unstructured.NestedInt64(cr.Object, "status", "observedGeneration")
+		return generation, err
+	}
+	return 0, apierrors.NewNotFound(databaseClusterResource.GroupResource(), name)
+}
+
 // CreateDatabaseCluster creates database cluster.
+// It fails with an AlreadyExists error if the database cluster exists.
 func (k *Kubernetes) CreateDatabaseCluster(cluster *everestv1alpha1.DatabaseCluster) error {
 	if cluster.ObjectMeta.Annotations == nil {
 		cluster.ObjectMeta.Annotations = make(map[string]string)
 	}
 	cluster.ObjectMeta.Annotations[managedByKey] = "pmm"
-	return k.client.ApplyObject(cluster)
+	cluster.TypeMeta.APIVersion = databaseClusterAPIVersion
+	cluster.TypeMeta.Kind = databaseClusterKind
+	return k.client.CreateObject(cluster)
 }
 
 // PatchDatabaseCluster patches CR of managed Database cluster.
 func (k *Kubernetes) PatchDatabaseCluster(cluster *everestv1alpha1.DatabaseCluster) error {
+	cluster.TypeMeta.APIVersion = databaseClusterAPIVersion
+	cluster.TypeMeta.Kind = databaseClusterKind
 	return k.client.ApplyObject(cluster)
 }
 
@@ -57,3 +98,30 @@ func (k *Kubernetes) DeleteDatabaseCluster(ctx context.Context, namespace, name
 	cluster.TypeMeta.Kind = databaseClusterKind
 	return k.client.DeleteObject(cluster)
 }
+
+// GetEngineCRVersions returns the CR versions of the engine custom resources
+// in the namespace by the name of the database cluster.
+func (k *Kubernetes) GetEngineCRVersions(
+	ctx context.Context,
+	namespace string,
+	engine everestv1alpha1.EngineType,
+) (map[string]string, error) {
+	gvr, ok := engineResources[engine]
+	if !ok {
+		return nil, fmt.Errorf("unknown engine type %s", engine)
+	}
+	list, err := k.client.ListCRs(ctx, namespace, gvr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string, len(list.Items))
+	for _, cr := range list.Items {
+		v, _, err := unstructured.NestedString(cr.Object, "spec", "crVersion")
+		if err != nil {
+			return nil, err
+		}
+		versions[cr.GetName()] = v
+	}
+	return versions, nil
+}
@@ -31,3 +31,22 @@ func (k *Kubernetes) GetDatabaseClusterBackup(ctx context.Context, namespace, na
 func (k *Kubernetes) ListDatabaseClusterBackups(ctx context.Context, namespace string, options metav1.ListOptions) (*everestv1alpha1.DatabaseClusterBackupList, error) {
 	return k.client.ListDatabaseClusterBackups(ctx, namespace, options)
 }
+
+// CreateDatabaseClusterBackup creates database cluster backup.
+// It fails with an AlreadyExists error if the backup exists.
+func (k *Kubernetes) CreateDatabaseClusterBackup(backup *everestv1alpha1.DatabaseClusterBackup) error {
+	backup.TypeMeta.APIVersion = databaseClusterAPIVersion
+	backup.TypeMeta.Kind = databaseClusterBackupKind
+	return k.client.CreateObject(backup)
+}
+
+// DeleteDatabaseClusterBackup deletes database cluster backup.
+func (k *Kubernetes) DeleteDatabaseClusterBackup(ctx context.Context, namespace, name string) error {
+	backup, err := k.client.GetDatabaseClusterBackup(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	backup.TypeMeta.APIVersion = databaseClusterAPIVersion
+	backup.TypeMeta.Kind = databaseClusterBackupKind
+	return k.client.DeleteObject(backup)
+}
@@ -0,0 +1,129 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+func TestCreateKeepsExisting(t *testing.T) {
+	t.Parallel()
+
+	meta := metav1.ObjectMeta{Name: "mysql-1", Namespace: "dev"}
+	tcases := []struct {
+		name   string
+		create func(k *Kubernetes) error
+	}{
+		{"cluster", func(k *Kubernetes) error {
+			return k.CreateDatabaseCluster(&everestv1alpha1.DatabaseCluster{ObjectMeta: meta})
+		}},
+		{"backup", func(k *Kubernetes) error {
+			return k.CreateDatabaseClusterBackup(&everestv1alpha1.DatabaseClusterBackup{ObjectMeta: meta})
+		}},
+		{"restore", func(k *Kubernetes) error {
+			return k.CreateRestore(&everestv1alpha1.DatabaseClusterRestore{ObjectMeta: meta})
+		}},
+		{"secret", func(k *Kubernetes) error {
+			return k.CreateSecret(&corev1.Secret{ObjectMeta: meta})
+		}},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &client.MockKubeClientConnector{}
+			k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+			exists := k8serrors.NewAlreadyExists(schema.GroupResource{Group: "everest.percona.com", Resource: tc.name}, "mysql-1")
+			k8sclient.On("CreateObject", mock.Anything).Return(exists).Once()
+
+			err := tc.create(k)
+			require.Error(t, err)
+			assert.True(t, k8serrors.IsAlreadyExists(err))
+			k8sclient.AssertExpectations(t)
+			k8sclient.AssertNotCalled(t, "ApplyObject", mock.Anything)
+		})
+	}
+}
+
+func TestGetDatabaseClusterObservedGeneration(t *testing.T) {
+	t.Parallel()
+
+	cluster := func(name string, status map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name},
+			"status":   status,
+		}}
+	}
+	type tcase struct {
+		name     string
+		clusters []unstructured.Unstructured
+		want     int64
+		notFound bool
+	}
+	tcases := []tcase{
+		{
+			name: "observed",
+			clusters: []unstructured.Unstructured{
+				cluster("mysql-2", map[string]interface{}{"observedGeneration": int64(5)}),
+				cluster("mysql-1", map[string]interface{}{"observedGeneration": int64(3)}),
+			},
+			want: 3,
+		},
+		{
+			name:     "not reported",
+			clusters: []unstructured.Unstructured{cluster("mysql-1", map[string]interface{}{"status": "ready"})},
+		},
+		{
+			name:     "not found",
+			clusters: []unstructured.Unstructured{cluster("mysql-2", nil)},
+			notFound: true,
+		},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			k8sclient := &client.MockKubeClientConnector{}
+			k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+			k8sclient.On("ListCRs", mock.Anything, "dev", databaseClusterResource, mock.Anything).
+				Return(&unstructured.UnstructuredList{Items: tc.clusters}, nil)
+
+			got, err := k.GetDatabaseClusterObservedGeneration(context.Background(), "dev", "mysql-1")
+			if tc.notFound {
+				assert.True(t, k8serrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+// EnableDryRun switches the client to dry-run mode.
+// In dry-run mode no changes are made to the cluster. They are recorded
+// instead and can be retrieved with DryRunPlan.
+func (k *Kubernetes) EnableDryRun() {
+	if k.IsDryRun() {
+		return
+	}
+	k.client = client.NewDryRunClient(k.client)
+}
+
+// IsDryRun returns true if the client runs in dry-run mode.
+func (k *Kubernetes) IsDryRun() bool {
+	_, ok := k.client.(*client.DryRunClient)
+	return ok
+}
+
+// DryRunPlan returns the changes recorded in dry-run mode.
+// It returns nil if dry-run mode is not enabled.
+func (k *Kubernetes) DryRunPlan() *client.Plan {
+	d, ok := k.client.(*client.DryRunClient)
+	if !ok {
+		return nil
+	}
+	return d.Plan()
+}
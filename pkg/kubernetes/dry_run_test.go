@@ -0,0 +1,71 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	k8sclient := &client.MockKubeClientConnector{}
+	l, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	k := &Kubernetes{client: k8sclient, l: l.Sugar()}
+	k.EnableDryRun()
+	require.True(t, k.IsDryRun())
+
+	// Only read calls reach the cluster. The mock fails on any other call.
+	k8sclient.On("ObjectExists", mock.Anything).Return(false, nil)
+	k8sclient.On("GetOperatorGroup", ctx, "dev", "everest-databases").Return(
+		nil, apierrors.NewNotFound(schema.GroupResource{}, "everest-databases"),
+	)
+	k8sclient.On("GetSubscription", mock.Anything, "dev", "percona-xtradb-cluster-operator").Return(
+		nil, apierrors.NewNotFound(schema.GroupResource{}, "percona-xtradb-cluster-operator"),
+	)
+
+	require.NoError(t, k.CreateNamespace("dev"))
+	require.NoError(t, k.CreateOperatorGroup(ctx, "everest-databases", "dev", []string{}))
+	require.NoError(t, k.InstallOperator(ctx, InstallOperatorRequest{
+		Namespace:           "dev",
+		Name:                "percona-xtradb-cluster-operator",
+		Channel:             "stable-v1",
+		InstallPlanApproval: v1alpha1.ApprovalManual,
+	}))
+	require.NoError(t, k.CreateRole("dev", "everest-admin-role", nil))
+
+	require.Equal(t, []client.PlanAction{
+		{Action: client.PlanActionCreate, Kind: "Namespace", Name: "dev"},
+		{Action: client.PlanActionCreate, Kind: "OperatorGroup", Namespace: "dev", Name: "everest-databases"},
+		{Action: client.PlanActionCreate, Kind: "Subscription", Namespace: "dev", Name: "percona-xtradb-cluster-operator"},
+		{Action: client.PlanActionApprove, Kind: "InstallPlan", Namespace: "dev", Name: "install-percona-xtradb-cluster-operator"},
+		{Action: client.PlanActionCreate, Kind: "Role", Namespace: "dev", Name: "everest-admin-role"},
+	}, k.DryRunPlan().Actions)
+	k8sclient.AssertExpectations(t)
+}
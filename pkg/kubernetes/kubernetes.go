@@ -26,6 +26,8 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	"net/url"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -36,6 +38,7 @@ import (
 	"go.uber.org/zap"
 	yamlv3 "gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -65,6 +68,16 @@ const (
 	ClusterTypeMinikube ClusterType = "minikube"
 	// ClusterTypeEKS is for EKS.
 	ClusterTypeEKS ClusterType = "eks"
+	// ClusterTypeKind is for kind.
+	ClusterTypeKind ClusterType = "kind"
+	// ClusterTypeK3s is for k3s.
+	ClusterTypeK3s ClusterType = "k3s"
+	// ClusterTypeOpenShift is for OpenShift.
+	ClusterTypeOpenShift ClusterType = "openshift"
+	// ClusterTypeGKE is for GKE.
+	ClusterTypeGKE ClusterType = "gke"
+	// ClusterTypeAKS is for AKS.
+	ClusterTypeAKS ClusterType = "aks"
 	// ClusterTypeGeneric is a generic type.
 	ClusterTypeGeneric ClusterType = "generic"
 
@@ -84,9 +97,20 @@ const (
 	pxcOperatorContainerName     = "percona-xtradb-cluster-operator"
 	everestOperatorContainerName = "manager"
 	databaseClusterKind          = "DatabaseCluster"
+	databaseClusterBackupKind    = "DatabaseClusterBackup"
+	databaseClusterRestoreKind   = "DatabaseClusterRestore"
 	databaseClusterAPIVersion    = "everest.percona.com/v1alpha1"
 	restartAnnotationKey         = "everest.percona.com/restart"
 	managedByKey                 = "everest.percona.com/managed-by"
+	// EngineTypeAnnotationKey is the annotation of backups holding the engine type
+	// of the database cluster they were taken from.
+	EngineTypeAnnotationKey = "everest.percona.com/engine-type"
+	// EngineVersionAnnotationKey is the annotation of backups holding the engine version
+	// of the database cluster they were taken from.
+	EngineVersionAnnotationKey = "everest.percona.com/engine-version"
+	// StorageSizeAnnotationKey is the annotation of backups holding the storage size
+	// of the database cluster they were taken from.
+	StorageSizeAnnotationKey = "everest.percona.com/storage-size"
 	// ContainerStateWaiting represents a state when container requires some
 	// operations being done in order to complete start up.
 	ContainerStateWaiting ContainerState = "waiting"
@@ -94,9 +118,15 @@ const (
 	// then either ran to completion or failed for some reason.
 	ContainerStateTerminated ContainerState = "terminated"
 
-	// OLMNamespace is the namespace where OLM is installed.
-	OLMNamespace    = "everest-olm"
-	olmOperatorName = "olm-operator"
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+	olmAPIGroup                       = "operators.coreos.com"
+	openShiftConfigAPIGroup           = "config.openshift.io"
+
+	// OLMNamespace is the default namespace where OLM is installed.
+	OLMNamespace = "everest-olm"
+	// OLMOperatorName is the name of the OLM operator deployment.
+	OLMOperatorName = "olm-operator"
 
 	// APIVersionCoreosV1 constant for some API requests.
 	APIVersionCoreosV1 = "operators.coreos.com/v1"
@@ -107,8 +137,11 @@ const (
 
 var (
 	// ErrEmptyVersionTag Got an empty version tag from GitHub API.
-	ErrEmptyVersionTag       error = errors.New("got an empty version tag from Github")
-	errNoEverestOperatorPods       = errors.New("no instances of everest-operator are running")
+	ErrEmptyVersionTag error = errors.New("got an empty version tag from Github")
+	// ErrInstallPlanNotApproved appears when the user declines an install plan.
+	ErrInstallPlanNotApproved = errors.New("install plan not approved")
+	errNoEverestOperatorPods  = errors.New("no instances of everest-operator are running")
+	errStorageClassNotFound   = errors.New("storage class not found")
 )
 
 // Kubernetes is a client for Kubernetes.
@@ -117,6 +150,14 @@ type Kubernetes struct {
 	l          *zap.SugaredLogger
 	httpClient *http.Client
 	kubeconfig string
+	manifest   []byte
+	registry   RegistryConfig
+	// manifestURL overrides the URL of the Everest manifest if set.
+	manifestURL string
+	// everestServiceType overrides the type of the Everest service if set.
+	everestServiceType corev1.ServiceType
+	// olmNamespace overrides OLMNamespace if set.
+	olmNamespace string
 }
 
 // ContainerState describes container's state - waiting, running, terminated.
@@ -160,6 +201,21 @@ func New(kubeconfigPath string, l *zap.SugaredLogger) (*Kubernetes, error) {
 	}, nil
 }
 
+// Connect returns new Kubernetes object like New.
+// It logs a hint if the cluster is not accessible.
+func Connect(kubeconfigPath string, l *zap.SugaredLogger) (*Kubernetes, error) {
+	k, err := New(kubeconfigPath, l)
+	if err != nil {
+		var u *url.Error
+		if errors.As(err, &u) {
+			l.Error("Could not connect to Kubernetes. " +
+				"Make sure Kubernetes is running and is accessible from this computer/server.")
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
 // Config returns *rest.Config.
 func (k *Kubernetes) Config() *rest.Config {
 	return k.client.Config()
@@ -185,20 +241,51 @@ func (k *Kubernetes) ClusterName() string {
 	return k.client.ClusterName()
 }
 
-// GetDefaultStorageClassName returns first storageClassName from kubernetes cluster.
+// GetDefaultStorageClassName returns the name of the default storage class.
+// The first storage class is returned if none is marked as default.
 func (k *Kubernetes) GetDefaultStorageClassName(ctx context.Context) (string, error) {
 	storageClasses, err := k.client.GetStorageClasses(ctx)
 	if err != nil {
 		return "", err
 	}
+	for _, sc := range storageClasses.Items {
+		if IsDefaultStorageClass(sc) {
+			return sc.Name, nil
+		}
+	}
 	if len(storageClasses.Items) != 0 {
 		return storageClasses.Items[0].Name, nil
 	}
 	return "", errors.New("no storage classes available")
 }
 
-// GetClusterType tries to guess the underlying kubernetes cluster based on storage class.
+// IsDefaultStorageClass returns true if the storage class is marked as default.
+func IsDefaultStorageClass(sc storagev1.StorageClass) bool {
+	return sc.Annotations[defaultStorageClassAnnotation] == "true" ||
+		sc.Annotations[betaDefaultStorageClassAnnotation] == "true"
+}
+
+// GetClusterType tries to guess the underlying kubernetes cluster
+// based on the API groups, the nodes and the storage classes.
 func (k *Kubernetes) GetClusterType(ctx context.Context) (ClusterType, error) {
+	crds, err := k.client.ListCRDs(ctx, nil)
+	if err != nil {
+		return ClusterTypeUnknown, err
+	}
+	for _, crd := range crds.Items {
+		if crd.Spec.Group == openShiftConfigAPIGroup {
+			return ClusterTypeOpenShift, nil
+		}
+	}
+
+	nodes, err := k.client.GetNodes(ctx)
+	if err != nil {
+		return ClusterTypeUnknown, err
+	}
+	if t := clusterTypeFromNodes(nodes.Items); t != ClusterTypeUnknown {
+		return t, nil
+	}
+
 	storageClasses, err := k.client.GetStorageClasses(ctx)
 	if err != nil {
 		return ClusterTypeUnknown, err
@@ -216,6 +303,68 @@ func (k *Kubernetes) GetClusterType(ctx context.Context) (ClusterType, error) {
 	return ClusterTypeGeneric, nil
 }
 
+// clusterTypeFromNodes guesses the cluster type from the labels and the provider IDs of the nodes.
+func clusterTypeFromNodes(nodes []corev1.Node) ClusterType {
+	for _, node := range nodes {
+		labels := node.Labels
+		providerID := node.Spec.ProviderID
+		switch {
+		case labels["minikube.k8s.io/name"] != "":
+			return ClusterTypeMinikube
+		case strings.HasPrefix(providerID, "kind://"):
+			return ClusterTypeKind
+		case strings.HasPrefix(providerID, "k3s://") || labels["node.kubernetes.io/instance-type"] == "k3s":
+			return ClusterTypeK3s
+		case labels["eks.amazonaws.com/nodegroup"] != "" || labels["eks.amazonaws.com/compute-type"] != "":
+			return ClusterTypeEKS
+		case strings.HasPrefix(providerID, "gce://") || labels["cloud.google.com/gke-nodepool"] != "":
+			return ClusterTypeGKE
+		case strings.HasPrefix(providerID, "azure://") || labels["kubernetes.azure.com/cluster"] != "":
+			return ClusterTypeAKS
+		}
+	}
+	return ClusterTypeUnknown
+}
+
+// SetDefaultStorageClass marks the storage class as default.
+func (k *Kubernetes) SetDefaultStorageClass(ctx context.Context, name string) error {
+	return k.setDefaultStorageClassAnnotation(ctx, name, true)
+}
+
+// UnsetDefaultStorageClass removes the default mark set by SetDefaultStorageClass.
+// Nothing is done if the storage class does not exist anymore.
+func (k *Kubernetes) UnsetDefaultStorageClass(ctx context.Context, name string) error {
+	err := k.setDefaultStorageClassAnnotation(ctx, name, false)
+	if errors.Is(err, errStorageClassNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (k *Kubernetes) setDefaultStorageClassAnnotation(ctx context.Context, name string, isDefault bool) error {
+	storageClasses, err := k.client.GetStorageClasses(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sc := range storageClasses.Items {
+		if sc.Name != name {
+			continue
+		}
+		if sc.Annotations == nil {
+			sc.Annotations = make(map[string]string)
+		}
+		if isDefault {
+			sc.Annotations[defaultStorageClassAnnotation] = "true"
+		} else {
+			delete(sc.Annotations, defaultStorageClassAnnotation)
+		}
+		sc.Kind = "StorageClass"
+		sc.APIVersion = "storage.k8s.io/v1"
+		return k.client.ApplyObject(&sc)
+	}
+	return fmt.Errorf("%w: %s", errStorageClassNotFound, name)
+}
+
 // getOperatorVersion parses operator version from operator deployment.
 func (k *Kubernetes) getOperatorVersion(ctx context.Context, deploymentName, containerName string) (string, error) {
 	deployment, err := k.client.GetDeployment(ctx, deploymentName, "")
@@ -260,6 +409,66 @@ func (k *Kubernetes) SetSecret(secret *corev1.Secret) error {
 	return k.client.ApplyObject(secret)
 }
 
+// CreateSecret creates a secret.
+// It fails with an AlreadyExists error if the secret exists.
+func (k *Kubernetes) CreateSecret(secret *corev1.Secret) error {
+	return k.client.CreateObject(secret)
+}
+
+// DeleteSecret deletes a secret. A missing secret is not an error.
+func (k *Kubernetes) DeleteSecret(namespace, name string) error {
+	return k.client.DeleteObject(&corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	})
+}
+
+// CopySecret copies the secret from the namespace to the other namespaces.
+// Secrets existing in the other namespaces are kept.
+func (k *Kubernetes) CopySecret(ctx context.Context, name, from string, to ...string) error {
+	secret, err := k.client.GetSecret(ctx, name, from)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range to {
+		if namespace == from {
+			continue
+		}
+		err := k.client.CreateObject(&corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetConfigMap returns config map by name and namespace.
+func (k *Kubernetes) GetConfigMap(ctx context.Context, name, namespace string) (*corev1.ConfigMap, error) {
+	return k.client.GetConfigMap(ctx, name, namespace)
+}
+
+// SetConfigMap creates or updates an existing config map.
+func (k *Kubernetes) SetConfigMap(cm *corev1.ConfigMap) error {
+	return k.client.ApplyObject(cm)
+}
+
 // CreatePMMSecret creates pmm secret in kubernetes.
 func (k *Kubernetes) CreatePMMSecret(namespace, secretName string, secrets map[string][]byte) error {
 	secret := &corev1.Secret{ //nolint: exhaustruct
@@ -278,8 +487,11 @@ func (k *Kubernetes) CreatePMMSecret(namespace, secretName string, secrets map[s
 }
 
 // CreateRestore creates a restore.
+// It fails with an AlreadyExists error if the restore exists.
 func (k *Kubernetes) CreateRestore(restore *everestv1alpha1.DatabaseClusterRestore) error {
-	return k.client.ApplyObject(restore)
+	restore.TypeMeta.APIVersion = databaseClusterAPIVersion
+	restore.TypeMeta.Kind = databaseClusterRestoreKind
+	return k.client.CreateObject(restore)
 }
 
 // GetPods returns list of pods.
@@ -295,6 +507,7 @@ func (k *Kubernetes) GetPods(
 func (k *Kubernetes) GetLogs(
 	ctx context.Context,
 	containerStatuses []corev1.ContainerStatus,
+	namespace,
 	pod,
 	container string,
 ) ([]string, error) {
@@ -302,7 +515,7 @@ func (k *Kubernetes) GetLogs(
 		return []string{}, nil
 	}
 
-	stdout, err := k.client.GetLogs(ctx, pod, container)
+	stdout, err := k.client.GetLogs(ctx, namespace, pod, container)
 	if err != nil {
 		return nil, errors.Join(err, errors.New("couldn't get logs"))
 	}
@@ -315,8 +528,8 @@ func (k *Kubernetes) GetLogs(
 }
 
 // GetEvents returns pod's events as a slice of strings.
-func (k *Kubernetes) GetEvents(ctx context.Context, pod string) ([]string, error) {
-	stdout, err := k.client.GetEvents(ctx, pod)
+func (k *Kubernetes) GetEvents(ctx context.Context, namespace, pod string) ([]string, error) {
+	stdout, err := k.client.GetEvents(ctx, namespace, pod)
 	if err != nil {
 		return nil, errors.Join(err, errors.New("couldn't describe pod"))
 	}
@@ -358,6 +571,11 @@ func IsNodeInCondition(node corev1.Node, conditionType corev1.NodeConditionType)
 	return false
 }
 
+// GetNodes returns list of cluster nodes.
+func (k *Kubernetes) GetNodes(ctx context.Context) (*corev1.NodeList, error) {
+	return k.client.GetNodes(ctx)
+}
+
 // GetWorkerNodes returns list of cluster workers nodes.
 func (k *Kubernetes) GetWorkerNodes(ctx context.Context) ([]corev1.Node, error) {
 	nodes, err := k.client.GetNodes(ctx)
@@ -397,7 +615,7 @@ func (k *Kubernetes) GetStorageClasses(ctx context.Context) (*storagev1.StorageC
 
 // InstallOLMOperator installs the OLM in the Kubernetes cluster.
 func (k *Kubernetes) InstallOLMOperator(ctx context.Context, upgrade bool) error {
-	deployment, err := k.client.GetDeployment(ctx, olmOperatorName, OLMNamespace)
+	deployment, err := k.client.GetDeployment(ctx, OLMOperatorName, k.GetOLMNamespace())
 	if err == nil && deployment != nil && deployment.ObjectMeta.Name != "" && !upgrade {
 		k.l.Info("OLM operator is already installed")
 		return nil // already installed
@@ -416,7 +634,7 @@ func (k *Kubernetes) InstallOLMOperator(ctx context.Context, upgrade bool) error
 		return err
 	}
 
-	if err := k.client.DoRolloutWait(ctx, types.NamespacedName{Namespace: OLMNamespace, Name: "packageserver"}); err != nil {
+	if err := k.client.DoRolloutWait(ctx, types.NamespacedName{Namespace: k.GetOLMNamespace(), Name: packageServerName}); err != nil {
 		return errors.Join(err, errors.New("error while waiting for deployment rollout"))
 	}
 
@@ -448,34 +666,47 @@ func (k *Kubernetes) applyCSVs(ctx context.Context, resources []unstructured.Uns
 	return nil
 }
 
-// InstallPerconaCatalog installs percona catalog and ensures that packages are available.
-func (k *Kubernetes) InstallPerconaCatalog(ctx context.Context) error {
-	data, err := fs.ReadFile(data.OLMCRDs, "crds/olm/everest-catalog.yaml")
+// InstallPerconaCatalog installs percona catalog with the provided image
+// and ensures that packages are available.
+func (k *Kubernetes) InstallPerconaCatalog(ctx context.Context, catalogImage string) error {
+	data, err := PerconaCatalogManifest(catalogImage, k.GetOLMNamespace())
 	if err != nil {
-		return errors.Join(err, errors.New("failed to read percona catalog file"))
-	}
-	o := make(map[string]interface{})
-	if err := yamlv3.Unmarshal(data, &o); err != nil {
 		return err
 	}
-
-	if err := unstructured.SetNestedField(o, everestVersion.CatalogImage(), "spec", "image"); err != nil {
-		return err
-	}
-	data, err = yamlv3.Marshal(o)
-	if err != nil {
+	if data, err = k.registry.CustomizeManifest(data); err != nil {
 		return err
 	}
 
 	if err := k.client.ApplyFile(data); err != nil {
 		return errors.Join(err, errors.New("cannot apply percona catalog file"))
 	}
-	if err := k.client.DoPackageWait(ctx, OLMNamespace, "everest-operator"); err != nil {
+	if err := k.client.DoPackageWait(ctx, k.GetOLMNamespace(), "everest-operator"); err != nil {
 		return errors.Join(err, errors.New("timeout waiting for package"))
 	}
 	return nil
 }
 
+// PerconaCatalogManifest returns the percona catalog source manifest
+// which uses the provided catalog image and is placed in the namespace.
+func PerconaCatalogManifest(catalogImage, namespace string) ([]byte, error) {
+	data, err := fs.ReadFile(data.OLMCRDs, "crds/olm/everest-catalog.yaml")
+	if err != nil {
+		return nil, errors.Join(err, errors.New("failed to read percona catalog file"))
+	}
+	o := make(map[string]interface{})
+	if err := yamlv3.Unmarshal(data, &o); err != nil {
+		return nil, err
+	}
+
+	if err := unstructured.SetNestedField(o, catalogImage, "spec", "image"); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(o, namespace, "metadata", "namespace"); err != nil {
+		return nil, err
+	}
+	return yamlv3.Marshal(o)
+}
+
 func (k *Kubernetes) applyResources(ctx context.Context) ([]unstructured.Unstructured, error) {
 	files := []string{
 		"crds/olm/crds.yaml",
@@ -492,6 +723,12 @@ func (k *Kubernetes) applyResources(ctx context.Context) ([]unstructured.Unstruc
 		if err != nil {
 			return nil, errors.Join(err, fmt.Errorf("failed to read %q file", f))
 		}
+		if data, err = RelocateOLMManifest(data, k.GetOLMNamespace()); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed to relocate %q file", f))
+		}
+		if data, err = k.registry.CustomizeManifest(data); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed to customize %q file", f))
+		}
 
 		applyFile := func(ctx context.Context) (bool, error) {
 			k.l.Debugf("Applying %q file", f)
@@ -519,12 +756,12 @@ func (k *Kubernetes) applyResources(ctx context.Context) ([]unstructured.Unstruc
 
 func (k *Kubernetes) waitForDeploymentRollout(ctx context.Context) error {
 	if err := k.client.DoRolloutWait(ctx, types.NamespacedName{
-		Namespace: OLMNamespace,
-		Name:      olmOperatorName,
+		Namespace: k.GetOLMNamespace(),
+		Name:      OLMOperatorName,
 	}); err != nil {
 		return errors.Join(err, errors.New("error while waiting for deployment rollout"))
 	}
-	if err := k.client.DoRolloutWait(ctx, types.NamespacedName{Namespace: OLMNamespace, Name: "catalog-operator"}); err != nil {
+	if err := k.client.DoRolloutWait(ctx, types.NamespacedName{Namespace: k.GetOLMNamespace(), Name: catalogOperatorName}); err != nil {
 		return errors.Join(err, errors.New("error while waiting for deployment rollout"))
 	}
 
@@ -578,6 +815,12 @@ type InstallOperatorRequest struct {
 	StartingCSV            string
 	TargetNamespaces       []string
 	SubscriptionConfig     *olmv1alpha1.SubscriptionConfig
+	// SwitchChannel switches an existing subscription to Channel.
+	// Otherwise Channel is used for new subscriptions only.
+	SwitchChannel bool
+	// ConfirmInstallPlan is called before a manual install plan is approved.
+	// The installation fails with ErrInstallPlanNotApproved if it returns false.
+	ConfirmInstallPlan func(ip *olmv1alpha1.InstallPlan) (bool, error)
 }
 
 func mergeNamespacesEnvVar(str1, str2 string) string {
@@ -643,6 +886,29 @@ func mergeSubscriptionConfig(sub *olmv1alpha1.SubscriptionConfig, cfg *olmv1alph
 	return sub
 }
 
+// NewSubscription returns a new subscription object for the install request.
+// The subscription config of the request is not included.
+func NewSubscription(req InstallOperatorRequest) *olmv1alpha1.Subscription {
+	return &olmv1alpha1.Subscription{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       olmv1alpha1.SubscriptionKind,
+			APIVersion: olmv1alpha1.SubscriptionCRDAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: req.Namespace,
+			Name:      req.Name,
+		},
+		Spec: &olmv1alpha1.SubscriptionSpec{
+			CatalogSource:          req.CatalogSource,
+			CatalogSourceNamespace: req.CatalogSourceNamespace,
+			Package:                req.Name,
+			Channel:                req.Channel,
+			StartingCSV:            req.StartingCSV,
+			InstallPlanApproval:    req.InstallPlanApproval,
+		},
+	}
+}
+
 // InstallOperator installs an operator via OLM.
 func (k *Kubernetes) InstallOperator(ctx context.Context, req InstallOperatorRequest) error { //nolint:funlen
 	subscription, err := k.client.GetSubscription(ctx, req.Namespace, req.Name)
@@ -650,27 +916,14 @@ func (k *Kubernetes) InstallOperator(ctx context.Context, req InstallOperatorReq
 		return errors.Join(err, errors.New("cannot get subscription"))
 	}
 	if apierrors.IsNotFound(err) {
-		subscription = &olmv1alpha1.Subscription{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       olmv1alpha1.SubscriptionKind,
-				APIVersion: olmv1alpha1.SubscriptionCRDAPIVersion,
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: req.Namespace,
-				Name:      req.Name,
-			},
-			Spec: &olmv1alpha1.SubscriptionSpec{
-				CatalogSource:          req.CatalogSource,
-				CatalogSourceNamespace: req.CatalogSourceNamespace,
-				Package:                req.Name,
-				Channel:                req.Channel,
-				StartingCSV:            req.StartingCSV,
-				InstallPlanApproval:    req.InstallPlanApproval,
-			},
-		}
+		subscription = NewSubscription(req)
 	}
 
 	subscription.Spec.Config = mergeSubscriptionConfig(subscription.Spec.Config, req.SubscriptionConfig)
+	if req.SwitchChannel && req.Channel != "" && subscription.Spec.Channel != req.Channel {
+		k.l.Infof("Switching %s subscription from channel %s to %s", req.Name, subscription.Spec.Channel, req.Channel)
+		subscription.Spec.Channel = req.Channel
+	}
 	if apierrors.IsNotFound(err) {
 		_, err := k.client.CreateSubscription(ctx, req.Namespace, subscription)
 		if err != nil {
@@ -683,6 +936,7 @@ func (k *Kubernetes) InstallOperator(ctx context.Context, req InstallOperatorReq
 		}
 	}
 
+	confirm := confirmOnce(req.ConfirmInstallPlan)
 	err = wait.PollUntilContextTimeout(ctx, pollInterval, pollDuration, false, func(ctx context.Context) (bool, error) {
 		k.l.Debugf("Polling subscription %s/%s", req.Namespace, req.Name)
 		subs, err := k.client.GetSubscription(ctx, req.Namespace, req.Name)
@@ -692,8 +946,12 @@ func (k *Kubernetes) InstallOperator(ctx context.Context, req InstallOperatorReq
 		if subs == nil || (subs != nil && subs.Status.InstallPlanRef == nil) {
 			return false, nil
 		}
+		if req.InstallPlanApproval == olmv1alpha1.ApprovalAutomatic {
+			// OLM approves the install plan.
+			return true, nil
+		}
 
-		return k.approveInstallPlan(ctx, req.Namespace, subs.Status.InstallPlanRef.Name)
+		return k.approveInstallPlan(ctx, req.Namespace, subs.Status.InstallPlanRef.Name, confirm)
 	})
 	if err != nil {
 		return err
@@ -711,11 +969,47 @@ func (k *Kubernetes) InstallOperator(ctx context.Context, req InstallOperatorReq
 	return k.client.DoRolloutWait(ctx, types.NamespacedName{Namespace: req.Namespace, Name: deploymentName})
 }
 
-func (k *Kubernetes) approveInstallPlan(ctx context.Context, namespace, installPlanName string) (bool, error) {
+// confirmOnce returns a confirmation function which remembers the answer
+// for every install plan so it's asked once if the approval is retried.
+func confirmOnce(confirm func(ip *olmv1alpha1.InstallPlan) (bool, error)) func(ip *olmv1alpha1.InstallPlan) (bool, error) {
+	if confirm == nil {
+		return nil
+	}
+	answers := make(map[string]bool)
+	return func(ip *olmv1alpha1.InstallPlan) (bool, error) {
+		if ok, found := answers[ip.Name]; found {
+			return ok, nil
+		}
+		ok, err := confirm(ip)
+		if err != nil {
+			return false, err
+		}
+		answers[ip.Name] = ok
+		return ok, nil
+	}
+}
+
+func (k *Kubernetes) approveInstallPlan(
+	ctx context.Context,
+	namespace, installPlanName string,
+	confirm func(ip *olmv1alpha1.InstallPlan) (bool, error),
+) (bool, error) {
 	ip, err := k.client.GetInstallPlan(ctx, namespace, installPlanName)
 	if err != nil {
 		return false, err
 	}
+	if ip.Spec.Approved {
+		return true, nil
+	}
+	if confirm != nil {
+		ok, err := confirm(ip)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, errors.Join(ErrInstallPlanNotApproved, fmt.Errorf("install plan %s/%s was not approved", namespace, installPlanName))
+		}
+	}
 
 	k.l.Debugf("Approving install plan %s/%s", namespace, installPlanName)
 
@@ -735,6 +1029,24 @@ func (k *Kubernetes) approveInstallPlan(ctx context.Context, namespace, installP
 	return true, nil
 }
 
+// NewOperatorGroup returns a new operator group object.
+// Same as CreateOperatorGroup, the namespace is added to the target namespaces.
+func NewOperatorGroup(name, namespace string, targetNamespaces []string) *olmv1.OperatorGroup {
+	return &olmv1.OperatorGroup{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       olmv1.OperatorGroupKind,
+			APIVersion: APIVersionCoreosV1,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: olmv1.OperatorGroupSpec{
+			TargetNamespaces: append(targetNamespaces, namespace),
+		},
+	}
+}
+
 // CreateOperatorGroup creates operator group in the given namespace.
 func (k *Kubernetes) CreateOperatorGroup(ctx context.Context, name, namespace string, targetNamespaces []string) error {
 	targetNamespaces = append(targetNamespaces, namespace)
@@ -770,6 +1082,116 @@ func (k *Kubernetes) ListSubscriptions(ctx context.Context, namespace string) (*
 	return k.client.ListSubscriptions(ctx, namespace)
 }
 
+// SwitchSubscriptionChannel updates the channel of an existing subscription in place.
+// Missing subscriptions are ignored.
+func (k *Kubernetes) SwitchSubscriptionChannel(ctx context.Context, namespace, name, channel string) error {
+	subscription, err := k.client.GetSubscription(ctx, namespace, name)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Join(err, errors.New("cannot get subscription"))
+	}
+	if subscription.Spec.Channel == channel {
+		return nil
+	}
+
+	k.l.Infof("Switching %s subscription in %s namespace from channel %s to %s",
+		name, namespace, subscription.Spec.Channel, channel)
+	subscription.Spec.Channel = channel
+	if _, err := k.client.UpdateSubscription(ctx, namespace, subscription); err != nil {
+		return errors.Join(err, errors.New("cannot update subscription"))
+	}
+	return nil
+}
+
+// SetSubscriptionEnvVar sets the environment variable in the config of an existing subscription.
+// Unlike InstallOperator, the namespaces are replaced instead of being merged.
+func (k *Kubernetes) SetSubscriptionEnvVar(ctx context.Context, namespace, name string, env corev1.EnvVar) error {
+	subscription, err := k.client.GetSubscription(ctx, namespace, name)
+	if err != nil {
+		return errors.Join(err, errors.New("cannot get subscription"))
+	}
+	if subscription.Spec.Config == nil {
+		subscription.Spec.Config = &olmv1alpha1.SubscriptionConfig{}
+	}
+
+	found := false
+	for i, e := range subscription.Spec.Config.Env {
+		if e.Name != env.Name {
+			continue
+		}
+		if e.Value == env.Value {
+			return nil
+		}
+		subscription.Spec.Config.Env[i].Value = env.Value
+		found = true
+		break
+	}
+	if !found {
+		subscription.Spec.Config.Env = append(subscription.Spec.Config.Env, env)
+	}
+
+	if _, err := k.client.UpdateSubscription(ctx, namespace, subscription); err != nil {
+		return errors.Join(err, errors.New("cannot update subscription"))
+	}
+	return nil
+}
+
+// SetOperatorGroupTargetNamespaces replaces the target namespaces of an existing operator group.
+// Same as CreateOperatorGroup, the namespace is added to the target namespaces.
+func (k *Kubernetes) SetOperatorGroupTargetNamespaces(ctx context.Context, name, namespace string, targetNamespaces []string) error {
+	og, err := k.client.GetOperatorGroup(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+	og.Kind = olmv1.OperatorGroupKind
+	og.APIVersion = APIVersionCoreosV1
+	// The caller's slice is copied so that appending does not overwrite its backing array.
+	og.Spec.TargetNamespaces = append(slices.Clone(targetNamespaces), namespace)
+	return k.client.ApplyObject(og)
+}
+
+// DeleteOperator deletes the subscription of an operator and the installed cluster service version.
+// Missing subscriptions are ignored.
+func (k *Kubernetes) DeleteOperator(ctx context.Context, namespace, name string) error {
+	subscription, err := k.client.GetSubscription(ctx, namespace, name)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Join(err, errors.New("cannot get subscription"))
+	}
+
+	subscription.Kind = olmv1alpha1.SubscriptionKind
+	subscription.APIVersion = olmv1alpha1.SubscriptionCRDAPIVersion
+	if err := k.client.DeleteObject(subscription); err != nil {
+		return errors.Join(err, errors.New("cannot delete subscription"))
+	}
+
+	if subscription.Status.InstalledCSV == "" {
+		return nil
+	}
+	err = k.client.DeleteClusterServiceVersion(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      subscription.Status.InstalledCSV,
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Join(err, errors.New("cannot delete cluster service version"))
+	}
+	return nil
+}
+
+// ListInstallPlans returns the install plans in the namespace.
+func (k *Kubernetes) ListInstallPlans(ctx context.Context, namespace string) (*olmv1alpha1.InstallPlanList, error) {
+	return k.client.ListInstallPlans(ctx, namespace)
+}
+
+// GetInstallPlan returns an install plan by name and namespace.
+func (k *Kubernetes) GetInstallPlan(ctx context.Context, namespace, name string) (*olmv1alpha1.InstallPlan, error) {
+	return k.client.GetInstallPlan(ctx, namespace, name)
+}
+
 // UpgradeOperator upgrades an operator to the next available version.
 func (k *Kubernetes) UpgradeOperator(ctx context.Context, namespace, name string) error {
 	ip, err := k.getInstallPlan(ctx, namespace, name)
@@ -788,6 +1210,11 @@ func (k *Kubernetes) UpgradeOperator(ctx context.Context, namespace, name string
 	return err
 }
 
+// WaitForCSV waits until the CSV in the namespace reaches the Succeeded phase.
+func (k *Kubernetes) WaitForCSV(ctx context.Context, namespace, name string) error {
+	return k.client.DoCSVWait(ctx, types.NamespacedName{Name: name, Namespace: namespace})
+}
+
 func (k *Kubernetes) getInstallPlan(ctx context.Context, namespace, name string) (*olmv1alpha1.InstallPlan, error) {
 	var subs *olmv1alpha1.Subscription
 
@@ -819,6 +1246,48 @@ func (k *Kubernetes) getInstallPlan(ctx context.Context, namespace, name string)
 	return ip, err
 }
 
+// IsForeignOLMInstalled returns true if the OLM CRDs exist in the cluster
+// but OLM is not installed into the OLM namespace.
+func (k *Kubernetes) IsForeignOLMInstalled(ctx context.Context) (bool, error) {
+	_, err := k.client.GetDeployment(ctx, OLMOperatorName, k.GetOLMNamespace())
+	if err == nil {
+		return false, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	crds, err := k.client.ListCRDs(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	for _, crd := range crds.Items {
+		if crd.Spec.Group == olmAPIGroup {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CanI returns true if the current user is allowed to perform the verb on the resource.
+// An empty namespace checks the permission in all namespaces.
+func (k *Kubernetes) CanI(ctx context.Context, verb, group, resource, namespace string) (bool, error) {
+	review, err := k.client.CreateSelfSubjectAccessReview(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     group,
+				Resource:  resource,
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
 // GetServerVersion returns server version.
 func (k *Kubernetes) GetServerVersion() (*version.Info, error) {
 	return k.client.GetServerVersion()
@@ -855,11 +1324,14 @@ func (k *Kubernetes) DeleteObject(obj runtime.Object) error {
 
 // ProvisionMonitoring provisions PMM monitoring.
 func (k *Kubernetes) ProvisionMonitoring(namespace string) error {
-	for _, path := range k.victoriaMetricsCRDFiles() {
+	for _, path := range VictoriaMetricsFiles() {
 		file, err := data.OLMCRDs.ReadFile(path)
 		if err != nil {
 			return err
 		}
+		if file, err = k.registry.CustomizeManifest(file); err != nil {
+			return err
+		}
 		// retry 3 times because applying vmagent spec might take some time.
 		for i := 0; i < 3; i++ {
 			k.l.Debugf("Applying file %s", path)
@@ -880,7 +1352,9 @@ func (k *Kubernetes) ProvisionMonitoring(namespace string) error {
 	return nil
 }
 
-func (k *Kubernetes) victoriaMetricsCRDFiles() []string {
+// VictoriaMetricsFiles returns the paths of the monitoring manifests
+// in the data package in the order they are applied.
+func VictoriaMetricsFiles() []string {
 	return []string{
 		"crds/victoriametrics/crs/vmagent_rbac_account.yaml",
 		"crds/victoriametrics/crs/vmagent_rbac_role.yaml",
@@ -918,6 +1392,10 @@ func (k *Kubernetes) RestartEverest(ctx context.Context, name, namespace string)
 			return err
 		}
 	}
+	if k.IsDryRun() {
+		// The pods are not deleted so there is nothing to wait for.
+		return nil
+	}
 
 	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
 		pods, err := k.getEverestPods(ctx, name, namespace)
@@ -980,10 +1458,16 @@ func (k *Kubernetes) ApplyObject(obj runtime.Object) error {
 
 // InstallEverest downloads the manifest file and applies it against provisioned k8s cluster.
 func (k *Kubernetes) InstallEverest(ctx context.Context, namespace string) error {
-	data, err := k.getManifestData(ctx)
+	data, err := k.GetEverestManifest(ctx)
 	if err != nil {
 		return errors.Join(err, errors.New("failed downloading everest monitoring file"))
 	}
+	if data, err = k.registry.CustomizeManifest(data); err != nil {
+		return err
+	}
+	if data, err = k.customizeServiceType(data); err != nil {
+		return err
+	}
 
 	err = k.client.ApplyManifestFile(data, namespace)
 	if err != nil {
@@ -995,8 +1479,28 @@ func (k *Kubernetes) InstallEverest(ctx context.Context, namespace string) error
 	return nil
 }
 
-func (k *Kubernetes) getManifestData(ctx context.Context) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, everestVersion.ManifestURL(), nil)
+// SetManifest sets the Everest manifest used instead of downloading it.
+func (k *Kubernetes) SetManifest(data []byte) {
+	k.manifest = data
+}
+
+// SetManifestURL sets the URL the Everest manifest is downloaded from
+// instead of the one matching the CLI version.
+func (k *Kubernetes) SetManifestURL(url string) {
+	k.manifestURL = url
+}
+
+// GetEverestManifest downloads the Everest manifest file matching the CLI version.
+// The manifest provided by SetManifest is returned if set.
+func (k *Kubernetes) GetEverestManifest(ctx context.Context) ([]byte, error) {
+	if k.manifest != nil {
+		return k.manifest, nil
+	}
+	url := everestVersion.ManifestURL()
+	if k.manifestURL != "" {
+		url = k.manifestURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1005,12 +1509,15 @@ func (k *Kubernetes) getManifestData(ctx context.Context) ([]byte, error) {
 		return nil, err
 	}
 	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download Everest manifest from %s: %s", url, resp.Status)
+	}
 	return io.ReadAll(resp.Body)
 }
 
 // DeleteEverest downloads the manifest file and deletes it from provisioned k8s cluster.
 func (k *Kubernetes) DeleteEverest(ctx context.Context, namespace string) error {
-	data, err := k.getManifestData(ctx)
+	data, err := k.GetEverestManifest(ctx)
 	if err != nil {
 		return errors.Join(err, errors.New("failed downloading everest monitoring file"))
 	}
@@ -1044,11 +1551,66 @@ func (k *Kubernetes) GetDBNamespaces(ctx context.Context, namespace string) ([]s
 	return nil, errors.New("failed to get watched namespaces")
 }
 
+// ListDeployments returns the deployments in the namespace.
+func (k *Kubernetes) ListDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error) {
+	return k.client.ListDeployments(ctx, namespace)
+}
+
 // GetDeployment returns k8s deployment by provided name and namespace.
 func (k *Kubernetes) GetDeployment(ctx context.Context, name, namespace string) (*appsv1.Deployment, error) {
 	return k.client.GetDeployment(ctx, name, namespace)
 }
 
+// GetEverestVersion parses the version of the Everest backend from its deployment image.
+func (k *Kubernetes) GetEverestVersion(ctx context.Context, namespace string) (string, error) {
+	deployment, err := k.client.GetDeployment(ctx, PerconaEverestDeploymentName, namespace)
+	if err != nil {
+		return "", err
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == PerconaEverestDeploymentName {
+			return imageTag(container.Image), nil
+		}
+	}
+	if len(deployment.Spec.Template.Spec.Containers) == 1 {
+		return imageTag(deployment.Spec.Template.Spec.Containers[0].Image), nil
+	}
+	return "", errors.New("unknown version of Everest")
+}
+
+// GetInstalledOperatorVersion returns the version of the CSV installed by the subscription.
+// An empty version is returned if the subscription does not exist or has not installed a CSV yet.
+func (k *Kubernetes) GetInstalledOperatorVersion(ctx context.Context, namespace, name string) (string, error) {
+	subscription, err := k.client.GetSubscription(ctx, namespace, name)
+	if err != nil && apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.Join(err, errors.New("cannot get subscription"))
+	}
+	if subscription.Status.InstalledCSV == "" {
+		return "", nil
+	}
+	csv, err := k.client.GetClusterServiceVersion(ctx, types.NamespacedName{
+		Name:      subscription.Status.InstalledCSV,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return "", errors.Join(err, fmt.Errorf("cannot get %s CSV", subscription.Status.InstalledCSV))
+	}
+	return csv.Spec.Version.String(), nil
+}
+
+// imageTag returns the tag of the image or an empty string if it is not tagged.
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	i := strings.LastIndex(image, ":")
+	if i == -1 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
+
 // WaitForRollout waits for rollout of a provided deployment in the provided namespace.
 func (k *Kubernetes) WaitForRollout(ctx context.Context, name, namespace string) error {
 	return k.client.DoRolloutWait(ctx, types.NamespacedName{Name: name, Namespace: namespace})
@@ -1082,6 +1644,28 @@ func (k *Kubernetes) UpdateClusterRoleBinding(ctx context.Context, name string,
 	return nil
 }
 
+// RemoveClusterRoleBindingNamespaces removes the subjects of the namespaces from the cluster role binding.
+func (k *Kubernetes) RemoveClusterRoleBindingNamespaces(ctx context.Context, name string, namespaces []string) error {
+	binding, err := k.client.GetClusterRoleBinding(ctx, name)
+	if err != nil {
+		return err
+	}
+	subjects := make([]rbacv1.Subject, 0, len(binding.Subjects))
+	for _, subject := range binding.Subjects {
+		if !arrayContains(namespaces, subject.Namespace) {
+			subjects = append(subjects, subject)
+		}
+	}
+	if len(subjects) == len(binding.Subjects) {
+		return nil
+	}
+
+	binding.Subjects = subjects
+	binding.Kind = "ClusterRoleBinding"
+	binding.APIVersion = "rbac.authorization.k8s.io/v1"
+	return k.client.ApplyObject(binding)
+}
+
 func arrayContains(s []string, e string) bool {
 	for _, a := range s {
 		a := a
@@ -17,9 +17,21 @@
 package kubernetes
 
 import (
+	"context"
 	"testing"
 
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
 )
 
 func TestMergeNamesspacesEnvVar(t *testing.T) {
@@ -102,3 +114,111 @@ func TestMergeNamesspacesEnvVar(t *testing.T) {
 		})
 	}
 }
+
+func TestApproveInstallPlan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	k8sclient := &client.MockKubeClientConnector{}
+	k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+
+	ip := &olmv1alpha1.InstallPlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "install-abc", Namespace: "dev"},
+		Spec:       olmv1alpha1.InstallPlanSpec{ClusterServiceVersionNames: []string{"percona-postgresql-operator.v2.3.1"}},
+	}
+	k8sclient.On("GetInstallPlan", ctx, "dev", "install-abc").Return(ip.DeepCopy(), nil)
+
+	asked := 0
+	decline := confirmOnce(func(*olmv1alpha1.InstallPlan) (bool, error) {
+		asked++
+		return false, nil
+	})
+	_, err := k.approveInstallPlan(ctx, "dev", "install-abc", decline)
+	require.ErrorIs(t, err, ErrInstallPlanNotApproved)
+	_, err = k.approveInstallPlan(ctx, "dev", "install-abc", decline)
+	require.ErrorIs(t, err, ErrInstallPlanNotApproved)
+	assert.Equal(t, 1, asked)
+
+	approved := ip.DeepCopy()
+	approved.Spec.Approved = true
+	k8sclient.On("UpdateInstallPlan", ctx, "dev", approved).Return(approved, nil)
+	ok, err := k.approveInstallPlan(ctx, "dev", "install-abc", nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	k8sclient.AssertExpectations(t)
+}
+
+func TestUnsetDefaultStorageClass(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	k8sclient := &client.MockKubeClientConnector{}
+	k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+
+	sc := storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gp2",
+			Annotations: map[string]string{
+				defaultStorageClassAnnotation: "true",
+				"owner":                       "ops",
+			},
+		},
+	}
+	k8sclient.On("GetStorageClasses", ctx).Return(&storagev1.StorageClassList{Items: []storagev1.StorageClass{sc}}, nil)
+	k8sclient.On("ApplyObject", mock.MatchedBy(func(obj *storagev1.StorageClass) bool {
+		return obj.Name == "gp2" && !IsDefaultStorageClass(*obj) && obj.Annotations["owner"] == "ops"
+	})).Return(nil).Once()
+
+	require.NoError(t, k.UnsetDefaultStorageClass(ctx, "gp2"))
+	require.NoError(t, k.UnsetDefaultStorageClass(ctx, "deleted"))
+	k8sclient.AssertExpectations(t)
+}
+
+func TestSetOperatorGroupTargetNamespaces(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	k8sclient := &client.MockKubeClientConnector{}
+	k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+
+	k8sclient.On("GetOperatorGroup", ctx, "everest-system", "everest-system").Return(&olmv1.OperatorGroup{}, nil)
+	k8sclient.On("ApplyObject", mock.MatchedBy(func(og *olmv1.OperatorGroup) bool {
+		return assert.ObjectsAreEqual([]string{"dev", "everest-system"}, og.Spec.TargetNamespaces)
+	})).Return(nil).Once()
+
+	// The spare capacity of the namespaces must not be written to.
+	namespaces := make([]string, 1, 2)
+	namespaces[0] = "dev"
+	spare := namespaces[:2]
+	spare[1] = "prod"
+	require.NoError(t, k.SetOperatorGroupTargetNamespaces(ctx, "everest-system", "everest-system", namespaces))
+	assert.Equal(t, "prod", spare[1])
+	k8sclient.AssertExpectations(t)
+}
+
+func TestCopySecret(t *testing.T) {
+	t.Parallel()
+
+	k8sclient := &client.MockKubeClientConnector{}
+	k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "everest-system", Labels: map[string]string{"app": "registry"}},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte("{}")},
+	}
+	k8sclient.On("GetSecret", mock.Anything, "registry", "everest-system").Return(secret, nil)
+	copied := func(namespace string) interface{} {
+		return mock.MatchedBy(func(s *corev1.Secret) bool {
+			return s.Namespace == namespace && s.Name == "registry" && s.Labels == nil &&
+				s.Type == secret.Type && assert.ObjectsAreEqual(secret.Data, s.Data)
+		})
+	}
+	exists := k8serrors.NewAlreadyExists(corev1.Resource("secrets"), "registry")
+	k8sclient.On("CreateObject", copied("olm")).Return(exists).Once()
+	k8sclient.On("CreateObject", copied("dev")).Return(nil).Once()
+
+	err := k.CopySecret(context.Background(), "registry", "everest-system", "olm", "everest-system", "dev")
+	require.NoError(t, err)
+	k8sclient.AssertExpectations(t)
+	k8sclient.AssertNumberOfCalls(t, "CreateObject", 2)
+}
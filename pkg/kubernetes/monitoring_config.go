@@ -67,3 +67,24 @@ func (k *Kubernetes) GetMonitoringConfigsBySecretName(
 
 	return res, nil
 }
+
+// IsMonitoringConfigUsed checks that a monitoring config by provided name is used
+// by database clusters in the namespaces managed by Everest.
+func (k *Kubernetes) IsMonitoringConfigUsed(ctx context.Context, systemNamespace, name string) (bool, error) {
+	namespaces, err := k.GetDBNamespaces(ctx, systemNamespace)
+	if err != nil {
+		return false, err
+	}
+	for _, namespace := range namespaces {
+		list, err := k.ListDatabaseClusters(ctx, namespace)
+		if err != nil {
+			return false, err
+		}
+		for _, db := range list.Items {
+			if db.Spec.Monitoring != nil && db.Spec.Monitoring.MonitoringConfigName == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,82 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+func TestIsMonitoringConfigUsed(t *testing.T) {
+	t.Parallel()
+
+	monitored := func(name string) everestv1alpha1.DatabaseCluster {
+		return everestv1alpha1.DatabaseCluster{
+			Spec: everestv1alpha1.DatabaseClusterSpec{
+				Monitoring: &everestv1alpha1.Monitoring{MonitoringConfigName: name},
+			},
+		}
+	}
+	type tcase struct {
+		name     string
+		clusters map[string][]everestv1alpha1.DatabaseCluster
+		want     bool
+	}
+	tcases := []tcase{
+		{name: "no clusters"},
+		{
+			name:     "unmonitored cluster",
+			clusters: map[string][]everestv1alpha1.DatabaseCluster{"dev": {{}}},
+		},
+		{
+			name:     "other instance",
+			clusters: map[string][]everestv1alpha1.DatabaseCluster{"dev": {monitored("pmm-2")}},
+		},
+		{
+			name:     "used in other namespace",
+			clusters: map[string][]everestv1alpha1.DatabaseCluster{"dev": {{}}, "prod": {monitored("pmm")}},
+			want:     true,
+		},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			k8sclient := &client.MockKubeClientConnector{}
+			k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+			k8sclient.On("GetDeployment", ctx, EverestOperatorDeploymentName, "everest-system").
+				Return(everestOperatorDeployment("dev,prod"), nil)
+			for _, ns := range []string{"dev", "prod"} {
+				k8sclient.On("ListDatabaseClusters", ctx, ns, mock.Anything).
+					Return(&everestv1alpha1.DatabaseClusterList{Items: tc.clusters[ns]}, nil)
+			}
+
+			used, err := k.IsMonitoringConfigUsed(ctx, "everest-system", "pmm")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, used)
+		})
+	}
+}
@@ -4,8 +4,22 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// NewNamespace returns a new namespace object.
+func NewNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
 // GetNamespace returns a namespace.
 func (k *Kubernetes) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
 	return k.client.GetNamespace(ctx, name)
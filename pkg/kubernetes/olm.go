@@ -0,0 +1,271 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	yamlv3 "gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	packageServerName   = "packageserver"
+	catalogOperatorName = "catalog-operator"
+	perconaCatalogName  = "everest-catalog"
+)
+
+// ErrOLMNotFound appears when no OLM installation is found in the cluster.
+var ErrOLMNotFound = errors.New("OLM is not installed in the cluster")
+
+// SetOLMNamespace makes OLM be installed into the namespace instead of OLMNamespace.
+// The catalog source and the package manifests are looked up in the namespace as well.
+func (k *Kubernetes) SetOLMNamespace(namespace string) {
+	k.olmNamespace = namespace
+}
+
+// GetOLMNamespace returns the namespace of OLM, the catalog source and the package manifests.
+func (k *Kubernetes) GetOLMNamespace() string {
+	if k.olmNamespace == "" {
+		return OLMNamespace
+	}
+	return k.olmNamespace
+}
+
+// FindOLMNamespace finds an OLM installation through the OLM CRDs and the packageserver CSV.
+// It returns the global catalog namespace of the installation which is
+// the namespace of the packageserver CSV unless the catalog operator is
+// configured to use another one.
+func (k *Kubernetes) FindOLMNamespace(ctx context.Context) (string, error) {
+	namespace, err := k.FindOLMDeploymentsNamespace(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	d, err := k.client.GetDeployment(ctx, catalogOperatorName, namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil {
+		if ns := catalogOperatorNamespace(d); ns != "" {
+			return ns, nil
+		}
+	}
+	return namespace, nil
+}
+
+// catalogOperatorNamespace returns the global catalog namespace
+// passed to the catalog operator with the -namespace argument.
+func catalogOperatorNamespace(d *appsv1.Deployment) string {
+	for _, c := range d.Spec.Template.Spec.Containers {
+		args := append(append([]string{}, c.Command...), c.Args...)
+		for i, arg := range args {
+			name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if !strings.HasPrefix(arg, "-") || name != "namespace" {
+				continue
+			}
+			if !hasValue && i+1 < len(args) {
+				value = args[i+1]
+			}
+			// Values referencing environment variables cannot be resolved here.
+			if strings.HasPrefix(value, "$(") {
+				return ""
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// RelocateOLMManifest moves the objects of the OLM manifests bundled with the CLI
+// from OLMNamespace to the namespace. The namespace of the objects, the subjects of the
+// cluster role bindings, the target namespaces of the operator groups and the container
+// arguments referring to OLMNamespace are set on the decoded objects.
+func RelocateOLMManifest(data []byte, namespace string) ([]byte, error) {
+	if namespace == "" || namespace == OLMNamespace {
+		return data, nil
+	}
+	objs, err := decodeResources(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for i := range objs {
+		o := &objs[i]
+		if len(o.Object) == 0 {
+			continue
+		}
+		if err := relocateOLMObject(o, namespace); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not relocate %s %s", o.GetKind(), o.GetName()))
+		}
+		out, err := yamlv3.Marshal(o.Object)
+		if err != nil {
+			return nil, err
+		}
+		if b.Len() > 0 {
+			b.WriteString("---\n")
+		}
+		b.Write(out)
+	}
+	return b.Bytes(), nil
+}
+
+// relocateOLMObject moves the object of the OLM manifests from OLMNamespace to the namespace.
+func relocateOLMObject(o *unstructured.Unstructured, namespace string) error {
+	if o.GetNamespace() == OLMNamespace {
+		o.SetNamespace(namespace)
+	}
+
+	switch o.GetKind() {
+	case "Namespace":
+		if o.GetName() == OLMNamespace {
+			o.SetName(namespace)
+		}
+	case "ClusterRoleBinding":
+		return relocateSlice(o.Object, func(item interface{}) interface{} {
+			if s, ok := item.(map[string]interface{}); ok && s["namespace"] == OLMNamespace {
+				s["namespace"] = namespace
+			}
+			return item
+		}, "subjects")
+	case "OperatorGroup":
+		return relocateSlice(o.Object, relocateString(namespace), "spec", "targetNamespaces")
+	case "Deployment":
+		return relocateContainers(o.Object, namespace, "spec", "template", "spec", "containers")
+	case olmv1alpha1.ClusterServiceVersionKind:
+		deployments, ok, err := unstructured.NestedSlice(o.Object, "spec", "install", "spec", "deployments")
+		if err != nil || !ok {
+			return err
+		}
+		for _, d := range deployments {
+			d, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := relocateContainers(d, namespace, "spec", "template", "spec", "containers"); err != nil {
+				return err
+			}
+		}
+		return unstructured.SetNestedSlice(o.Object, deployments, "spec", "install", "spec", "deployments")
+	}
+	return nil
+}
+
+// relocateContainers sets the namespace in the commands and the arguments
+// of the containers which refer to OLMNamespace.
+func relocateContainers(obj map[string]interface{}, namespace string, fields ...string) error {
+	containers, ok, err := unstructured.NestedSlice(obj, fields...)
+	if err != nil || !ok {
+		return err
+	}
+	for _, c := range containers {
+		c, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, field := range []string{"command", "args"} {
+			if err := relocateSlice(c, relocateString(namespace), field); err != nil {
+				return err
+			}
+		}
+	}
+	return unstructured.SetNestedSlice(obj, containers, fields...)
+}
+
+// relocateString returns a function replacing OLMNamespace with the namespace.
+func relocateString(namespace string) func(item interface{}) interface{} {
+	return func(item interface{}) interface{} {
+		if item == OLMNamespace {
+			return namespace
+		}
+		return item
+	}
+}
+
+// relocateSlice applies the relocation to the items of the slice in the fields of the object.
+func relocateSlice(obj map[string]interface{}, relocate func(item interface{}) interface{}, fields ...string) error {
+	items, ok, err := unstructured.NestedSlice(obj, fields...)
+	if err != nil || !ok {
+		return err
+	}
+	for i := range items {
+		items[i] = relocate(items[i])
+	}
+	return unstructured.SetNestedSlice(obj, items, fields...)
+}
+
+// FindOLMDeploymentsNamespace finds an OLM installation through the OLM CRDs and the packageserver CSV.
+// It returns the namespace of the OLM deployments and the packageserver CSV.
+func (k *Kubernetes) FindOLMDeploymentsNamespace(ctx context.Context) (string, error) {
+	crds, err := k.client.ListCRDs(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	found := false
+	for _, crd := range crds.Items {
+		if crd.Spec.Group == olmAPIGroup {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", ErrOLMNotFound
+	}
+
+	csvs, err := k.client.ListClusterServiceVersion(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	for _, csv := range csvs.Items {
+		if csv.Name == packageServerName && !csv.IsCopied() {
+			return csv.Namespace, nil
+		}
+	}
+	return "", errors.Join(ErrOLMNotFound, errors.New("packageserver CSV not found"))
+}
+
+// OLMDeploymentNames returns the names of the OLM deployments.
+func OLMDeploymentNames() []string {
+	return []string{OLMOperatorName, catalogOperatorName, packageServerName}
+}
+
+// GetPerconaCatalog returns the percona catalog source.
+func (k *Kubernetes) GetPerconaCatalog(ctx context.Context) (*olmv1alpha1.CatalogSource, error) {
+	return k.client.GetCatalogSource(ctx, k.GetOLMNamespace(), perconaCatalogName)
+}
+
+// DeletePerconaCatalog deletes the percona catalog source.
+func (k *Kubernetes) DeletePerconaCatalog() error {
+	return k.client.DeleteObject(&olmv1alpha1.CatalogSource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: olmv1alpha1.SchemeGroupVersion.String(),
+			Kind:       olmv1alpha1.CatalogSourceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      perconaCatalogName,
+			Namespace: k.GetOLMNamespace(),
+		},
+	})
+}
@@ -0,0 +1,154 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/percona/percona-everest-cli/data"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+func TestCatalogOperatorNamespace(t *testing.T) {
+	t.Parallel()
+
+	tcases := []struct {
+		name    string
+		command []string
+		args    []string
+		want    string
+	}{
+		{"openshift", []string{"/bin/catalog"}, []string{"-namespace", "openshift-marketplace", "-configmapServerImage=image"}, "openshift-marketplace"},
+		{"equals", []string{"/bin/catalog", "--namespace=olm"}, nil, "olm"},
+		{"env var", []string{"/bin/catalog"}, []string{"--namespace", "$(OPERATOR_NAMESPACE)"}, ""},
+		{"missing", []string{"/bin/catalog"}, []string{"-debug"}, ""},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			d := &appsv1.Deployment{}
+			d.Spec.Template.Spec.Containers = []corev1.Container{{Command: tc.command, Args: tc.args}}
+			assert.Equal(t, tc.want, catalogOperatorNamespace(d))
+		})
+	}
+}
+
+func TestRelocateOLMManifest(t *testing.T) {
+	t.Parallel()
+
+	manifest, err := fs.ReadFile(data.OLMCRDs, "crds/olm/olm.yaml")
+	require.NoError(t, err)
+
+	same, err := RelocateOLMManifest(manifest, OLMNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, same)
+
+	relocated, err := RelocateOLMManifest(manifest, "tenant-olm")
+	require.NoError(t, err)
+	assert.NotContains(t, string(relocated), OLMNamespace)
+	objs, err := decodeResources(relocated)
+	require.NoError(t, err)
+	for _, o := range objs {
+		if o.GetNamespace() != "" {
+			assert.Equal(t, "tenant-olm", o.GetNamespace(), o.GetName())
+		}
+		if o.GetKind() == "Namespace" {
+			assert.Equal(t, "tenant-olm", o.GetName())
+		}
+	}
+}
+
+func TestRelocateOLMManifestKeepsOtherValues(t *testing.T) {
+	t.Parallel()
+
+	manifest := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: catalog-operator
+  namespace: everest-olm
+  labels:
+    app: everest-olm-catalog
+spec:
+  template:
+    spec:
+      containers:
+      - name: catalog-operator
+        image: registry.example.com/everest-olm/olm:v0.26.0
+        args:
+        - --namespace
+        - everest-olm
+        - --configmapServerImage=registry.example.com/everest-olm/registry:latest
+`)
+
+	relocated, err := RelocateOLMManifest(manifest, "tenant-olm")
+	require.NoError(t, err)
+	objs, err := decodeResources(relocated)
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	d := objs[0]
+	assert.Equal(t, "tenant-olm", d.GetNamespace())
+	assert.Equal(t, "everest-olm-catalog", d.GetLabels()["app"])
+	containers, _, err := unstructured.NestedSlice(d.Object, "spec", "template", "spec", "containers")
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	c := containers[0].(map[string]interface{}) //nolint:forcetypeassert
+	assert.Equal(t, "registry.example.com/everest-olm/olm:v0.26.0", c["image"])
+	assert.Equal(t, []interface{}{
+		"--namespace",
+		"tenant-olm",
+		"--configmapServerImage=registry.example.com/everest-olm/registry:latest",
+	}, c["args"])
+}
+
+func TestFindOLMDeploymentsNamespace(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	k8sclient := &client.MockKubeClientConnector{}
+	k := &Kubernetes{client: k8sclient, l: zap.NewNop().Sugar()}
+
+	crd := apiextv1.CustomResourceDefinition{Spec: apiextv1.CustomResourceDefinitionSpec{Group: olmAPIGroup}}
+	k8sclient.On("ListCRDs", ctx, (*metav1.LabelSelector)(nil)).
+		Return(&apiextv1.CustomResourceDefinitionList{Items: []apiextv1.CustomResourceDefinition{crd}}, nil)
+	copied := olmv1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{Name: packageServerName, Namespace: "everest"}}
+	copied.Status.Reason = olmv1alpha1.CSVReasonCopied
+	csv := olmv1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{Name: packageServerName, Namespace: "openshift-operator-lifecycle-manager"}}
+	k8sclient.On("ListClusterServiceVersion", ctx, "").
+		Return(&olmv1alpha1.ClusterServiceVersionList{Items: []olmv1alpha1.ClusterServiceVersion{copied, csv}}, nil)
+	d := &appsv1.Deployment{}
+	d.Spec.Template.Spec.Containers = []corev1.Container{{Args: []string{"-namespace", "openshift-marketplace"}}}
+	k8sclient.On("GetDeployment", ctx, catalogOperatorName, "openshift-operator-lifecycle-manager").Return(d, nil)
+
+	ns, err := k.FindOLMDeploymentsNamespace(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "openshift-operator-lifecycle-manager", ns)
+	ns, err = k.FindOLMNamespace(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "openshift-marketplace", ns)
+}
@@ -0,0 +1,110 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	packagev1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
+)
+
+var (
+	// ErrOperatorVersionNotFound appears when the requested operator version
+	// is not available in the channel.
+	ErrOperatorVersionNotFound = errors.New("operator version not found")
+	// ErrOperatorChannelNotFound appears when the requested channel
+	// does not exist in the operator package.
+	ErrOperatorChannelNotFound = errors.New("operator channel not found")
+)
+
+// GetOperatorCSV returns the name of the CSV of the operator version
+// available in the channel of the Percona catalog.
+func (k *Kubernetes) GetOperatorCSV(ctx context.Context, name, channel, version string) (string, error) {
+	pm, err := k.client.GetPackageManifest(ctx, k.GetOLMNamespace(), name)
+	if err != nil {
+		return "", errors.Join(err, fmt.Errorf("cannot get package manifest of %s", name))
+	}
+	return FindOperatorCSV(pm, channel, version)
+}
+
+// FindOperatorCSV returns the name of the CSV of the version in the channel
+// of the package manifest. The error lists the available versions if the
+// version is not found.
+func FindOperatorCSV(pm *packagev1.PackageManifest, channel, version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	for _, ch := range pm.Status.Channels {
+		if ch.Name != channel {
+			continue
+		}
+		versions := make([]string, 0, len(ch.Entries))
+		for _, e := range channelEntries(ch) {
+			if e.Version == version {
+				return e.Name, nil
+			}
+			versions = append(versions, e.Version)
+		}
+		return "", errors.Join(ErrOperatorVersionNotFound, fmt.Errorf(
+			"version %s of %s is not available in channel %s. Available versions: %s",
+			version, pm.Name, channel, strings.Join(versions, ", "),
+		))
+	}
+
+	return "", FindOperatorChannel(pm, channel)
+}
+
+// channelEntries returns the entries of the channel.
+// Older OLM versions provide only the head of the channel.
+func channelEntries(ch packagev1.PackageChannel) []packagev1.ChannelEntry {
+	if len(ch.Entries) != 0 {
+		return ch.Entries
+	}
+	return []packagev1.ChannelEntry{{Name: ch.CurrentCSV, Version: ch.CurrentCSVDesc.Version.String()}}
+}
+
+// ValidateOperatorChannel checks that the channel exists in the package
+// of the operator in the Percona catalog.
+func (k *Kubernetes) ValidateOperatorChannel(ctx context.Context, name, channel string) error {
+	pm, err := k.client.GetPackageManifest(ctx, k.GetOLMNamespace(), name)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("cannot get package manifest of %s", name))
+	}
+	return FindOperatorChannel(pm, channel)
+}
+
+// FindOperatorChannel checks that the channel exists in the package manifest.
+// The error lists the available channels if the channel is not found.
+func FindOperatorChannel(pm *packagev1.PackageManifest, channel string) error {
+	channels := make([]string, 0, len(pm.Status.Channels))
+	for _, ch := range pm.Status.Channels {
+		if ch.Name == channel {
+			return nil
+		}
+		channels = append(channels, ch.Name)
+	}
+	return errors.Join(ErrOperatorChannelNotFound, fmt.Errorf(
+		"channel %s of %s does not exist. Available channels: %s",
+		channel, pm.Name, strings.Join(channels, ", "),
+	))
+}
+
+// OperatorCSVName returns the CSV name of the operator version following
+// the naming convention of the Percona catalog.
+func OperatorCSVName(name, version string) string {
+	return name + ".v" + strings.TrimPrefix(version, "v")
+}
@@ -0,0 +1,58 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	packagev1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindOperatorCSV(t *testing.T) {
+	t.Parallel()
+
+	pm := &packagev1.PackageManifest{
+		ObjectMeta: metav1.ObjectMeta{Name: "percona-postgresql-operator"},
+		Status: packagev1.PackageManifestStatus{
+			Channels: []packagev1.PackageChannel{
+				{
+					Name: "stable-v2",
+					Entries: []packagev1.ChannelEntry{
+						{Name: "percona-postgresql-operator.v2.3.1", Version: "2.3.1"},
+						{Name: "percona-postgresql-operator.v2.2.0", Version: "2.2.0"},
+					},
+				},
+			},
+		},
+	}
+
+	csv, err := FindOperatorCSV(pm, "stable-v2", "v2.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, "percona-postgresql-operator.v2.2.0", csv)
+
+	_, err = FindOperatorCSV(pm, "stable-v2", "2.4.0")
+	require.ErrorIs(t, err, ErrOperatorVersionNotFound)
+	assert.Contains(t, err.Error(), "Available versions: 2.3.1, 2.2.0")
+
+	_, err = FindOperatorCSV(pm, "fast-v2", "2.3.1")
+	require.ErrorIs(t, err, ErrOperatorChannelNotFound)
+	assert.Contains(t, err.Error(), "Available channels: stable-v2")
+
+	require.NoError(t, FindOperatorChannel(pm, "stable-v2"))
+}
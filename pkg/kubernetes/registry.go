@@ -0,0 +1,250 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	dockerHubDomain  = "docker.io"
+	dockerHubLibrary = "library/"
+)
+
+// RegistryConfig configures pulling of the images from a private registry.
+type RegistryConfig struct {
+	// Mirror maps source registries or repository prefixes to their mirrors,
+	// e.g. "docker.io" to "harbor.example.com/dockerhub".
+	Mirror map[string]string
+	// PullSecret is the name of the image pull secret attached to
+	// the catalog source and the deployments.
+	PullSecret string
+}
+
+// ParseRegistryMirror parses a list of "source=mirror" pairs.
+func ParseRegistryMirror(mirrors []string) (map[string]string, error) {
+	res := make(map[string]string, len(mirrors))
+	for _, m := range mirrors {
+		src, dst, ok := strings.Cut(m, "=")
+		src = strings.TrimSuffix(strings.TrimSpace(src), "/")
+		dst = strings.TrimSuffix(strings.TrimSpace(dst), "/")
+		if !ok || src == "" || dst == "" {
+			return nil, fmt.Errorf("invalid registry mirror %q. Expected format is source=mirror", m)
+		}
+		res[src] = dst
+	}
+	return res, nil
+}
+
+// NewRegistryConfig returns a registry config for the "source=mirror" pairs
+// and the name of the image pull secret.
+func NewRegistryConfig(mirrors []string, pullSecret string) (RegistryConfig, error) {
+	m, err := ParseRegistryMirror(mirrors)
+	if err != nil {
+		return RegistryConfig{}, err
+	}
+	return RegistryConfig{Mirror: m, PullSecret: strings.TrimSpace(pullSecret)}, nil
+}
+
+// MirrorList returns the mirrors as sorted "source=mirror" pairs accepted by NewRegistryConfig.
+func (r RegistryConfig) MirrorList() []string {
+	res := make([]string, 0, len(r.Mirror))
+	for src, dst := range r.Mirror {
+		res = append(res, src+"="+dst)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// SetRegistry makes the manifests applied to the cluster use the private registry.
+func (k *Kubernetes) SetRegistry(r RegistryConfig) {
+	k.registry = r
+}
+
+// IsEmpty returns true if the config does not change the manifests.
+func (r RegistryConfig) IsEmpty() bool {
+	return len(r.Mirror) == 0 && r.PullSecret == ""
+}
+
+// RewriteImage returns the reference of the image in the mirror.
+// The image is returned unchanged if none of the mirrors matches.
+func (r RegistryConfig) RewriteImage(image string) string {
+	name := normalizeImage(image)
+	match := ""
+	for src := range r.Mirror {
+		if (name == src || strings.HasPrefix(name, src+"/") ||
+			strings.HasPrefix(name, src+":") || strings.HasPrefix(name, src+"@")) &&
+			len(src) > len(match) {
+			match = src
+		}
+	}
+	if match == "" {
+		return image
+	}
+	return r.Mirror[match] + strings.TrimPrefix(name, match)
+}
+
+// normalizeImage returns the fully qualified reference of the image
+// the same way the container runtime resolves it.
+func normalizeImage(image string) string {
+	domain, rest, ok := strings.Cut(image, "/")
+	if ok && (strings.ContainsAny(domain, ".:") || domain == "localhost") {
+		return image
+	}
+	if !ok {
+		return dockerHubDomain + "/" + dockerHubLibrary + image
+	}
+	return dockerHubDomain + "/" + domain + "/" + rest
+}
+
+// CustomizeManifest rewrites the images of all objects in the manifest
+// and attaches the pull secret to them.
+func (r RegistryConfig) CustomizeManifest(data []byte) ([]byte, error) {
+	if r.IsEmpty() {
+		return data, nil
+	}
+
+	objs, err := decodeResources(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for i, o := range objs {
+		if err := r.customizeObject(&o); err != nil {
+			return nil, err
+		}
+		out, err := yaml.Marshal(o.Object)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		b.Write(out)
+	}
+	return b.Bytes(), nil
+}
+
+func (r RegistryConfig) customizeObject(u *unstructured.Unstructured) error {
+	r.rewriteImages(u.Object)
+	if r.PullSecret == "" {
+		return nil
+	}
+
+	switch u.GetKind() {
+	case "CatalogSource":
+		return r.addPullSecret(u.Object, func(string) interface{} { return r.PullSecret }, "spec", "secrets")
+	case "Deployment":
+		return r.addPodPullSecret(u.Object, "spec", "template", "spec")
+	case "ClusterServiceVersion":
+		deployments, ok, err := unstructured.NestedSlice(u.Object, "spec", "install", "spec", "deployments")
+		if err != nil || !ok {
+			return err
+		}
+		for _, d := range deployments {
+			if m, ok := d.(map[string]interface{}); ok {
+				if err := r.addPodPullSecret(m, "spec", "template", "spec"); err != nil {
+					return err
+				}
+			}
+		}
+		return unstructured.SetNestedSlice(u.Object, deployments, "spec", "install", "spec", "deployments")
+	}
+	return nil
+}
+
+func (r RegistryConfig) addPodPullSecret(obj map[string]interface{}, podSpec ...string) error {
+	return r.addPullSecret(obj, func(name string) interface{} {
+		return map[string]interface{}{"name": name}
+	}, append(podSpec, "imagePullSecrets")...)
+}
+
+// addPullSecret appends the pull secret to the list located at fields
+// unless it's already there.
+func (r RegistryConfig) addPullSecret(obj map[string]interface{}, item func(string) interface{}, fields ...string) error {
+	secrets, _, err := unstructured.NestedSlice(obj, fields...)
+	if err != nil {
+		return err
+	}
+	s := item(r.PullSecret)
+	for _, existing := range secrets {
+		if fmt.Sprint(existing) == fmt.Sprint(s) {
+			return nil
+		}
+	}
+	return unstructured.SetNestedSlice(obj, append(secrets, s), fields...)
+}
+
+// rewriteImages walks the object and rewrites the image fields
+// as well as the container arguments which pass images to the operators.
+func (r RegistryConfig) rewriteImages(obj interface{}) {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range o {
+			switch val := v.(type) {
+			case string:
+				if k == "image" && val != "" {
+					o[k] = r.RewriteImage(val)
+				}
+			case map[string]interface{}:
+				// Custom resources, e.g. VMAgent, define images as {repository, tag}.
+				if repo, ok := val["repository"].(string); ok && k == "image" {
+					val["repository"] = r.RewriteImage(repo)
+				}
+				r.rewriteImages(val)
+			case []interface{}:
+				if k == "args" {
+					r.rewriteArgs(val)
+				}
+				r.rewriteImages(val)
+			}
+		}
+	case []interface{}:
+		for _, v := range o {
+			r.rewriteImages(v)
+		}
+	}
+}
+
+// rewriteArgs rewrites the values of the flags whose name ends with "image",
+// e.g. "--util-image <image>" or "--configmapServerImage=<image>".
+func (r RegistryConfig) rewriteArgs(args []interface{}) {
+	isImageFlag := func(f string) bool {
+		return strings.HasPrefix(f, "-") && strings.HasSuffix(strings.ToLower(f), "image")
+	}
+	for i, a := range args {
+		s, ok := a.(string)
+		if !ok {
+			continue
+		}
+		if flag, val, ok := strings.Cut(s, "="); ok && isImageFlag(flag) {
+			args[i] = flag + "=" + r.RewriteImage(val)
+			continue
+		}
+		if isImageFlag(s) && i+1 < len(args) {
+			if val, ok := args[i+1].(string); ok {
+				args[i+1] = r.RewriteImage(strings.TrimSpace(val))
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/percona/percona-everest-cli/data"
+)
+
+func TestRewriteImage(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewRegistryConfig([]string{
+		"docker.io=harbor.example.com/dockerhub",
+		"docker.io/percona=harbor.example.com/percona/",
+		"quay.io=harbor.example.com/quay",
+	}, "")
+	require.NoError(t, err)
+
+	tcases := map[string]string{
+		"busybox":                                  "harbor.example.com/dockerhub/library/busybox",
+		"percona/percona-everest:0.8.0":            "harbor.example.com/percona/percona-everest:0.8.0",
+		"docker.io/perconalab/everest-catalog":     "harbor.example.com/dockerhub/perconalab/everest-catalog",
+		"quay.io/operator-framework/olm@sha256:ab": "harbor.example.com/quay/operator-framework/olm@sha256:ab",
+		"registry.k8s.io/kube-state-metrics:v2":    "registry.k8s.io/kube-state-metrics:v2",
+		"quay.io.example.com/olm":                  "quay.io.example.com/olm",
+	}
+	for image, want := range tcases {
+		assert.Equal(t, want, r.RewriteImage(image), image)
+	}
+
+	_, err = NewRegistryConfig([]string{"docker.io"}, "")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"docker.io/percona=harbor.example.com/percona",
+		"docker.io=harbor.example.com/dockerhub",
+		"quay.io=harbor.example.com/quay",
+	}, r.MirrorList())
+	parsed, err := NewRegistryConfig(r.MirrorList(), "")
+	require.NoError(t, err)
+	assert.Equal(t, r, parsed)
+}
+
+func TestRegistryCustomizeManifest(t *testing.T) {
+	t.Parallel()
+
+	r := RegistryConfig{
+		Mirror:     map[string]string{"quay.io": "harbor.example.com/quay"},
+		PullSecret: "harbor",
+	}
+
+	olm, err := data.OLMCRDs.ReadFile("crds/olm/olm.yaml")
+	require.NoError(t, err)
+	out, err := r.CustomizeManifest(olm)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "quay.io/")
+	assert.Contains(t, string(out), "--configmapServerImage=harbor.example.com/quay/operator-framework/configmap-operator-registry:latest")
+	assert.Contains(t, string(out), "- name: harbor")
+
+	catalog, err := PerconaCatalogManifest("quay.io/percona/everest-catalog:0.8.0", OLMNamespace)
+	require.NoError(t, err)
+	out, err = r.CustomizeManifest(catalog)
+	require.NoError(t, err)
+	objs, err := decodeResources(out)
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "harbor.example.com/quay/percona/everest-catalog:0.8.0", objs[0].Object["spec"].(map[string]interface{})["image"])
+	assert.Equal(t, []interface{}{"harbor"}, objs[0].Object["spec"].(map[string]interface{})["secrets"])
+}
@@ -23,7 +23,12 @@ import (
 
 // CreateRole creates a new role.
 func (k *Kubernetes) CreateRole(namespace, name string, rules []rbac.PolicyRule) error {
-	m := &rbac.Role{
+	return k.client.ApplyObject(NewRole(namespace, name, rules))
+}
+
+// NewRole returns a new role object.
+func NewRole(namespace, name string, rules []rbac.PolicyRule) *rbac.Role {
+	return &rbac.Role{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "rbac.authorization.k8s.io/v1",
 			Kind:       "Role",
@@ -34,13 +39,16 @@ func (k *Kubernetes) CreateRole(namespace, name string, rules []rbac.PolicyRule)
 		},
 		Rules: rules,
 	}
-
-	return k.client.ApplyObject(m)
 }
 
 // CreateRoleBinding binds a role to a service account.
 func (k *Kubernetes) CreateRoleBinding(namespace, name, roleName, serviceAccountName string) error {
-	m := &rbac.RoleBinding{
+	return k.client.ApplyObject(NewRoleBinding(namespace, name, roleName, serviceAccountName))
+}
+
+// NewRoleBinding returns a new role binding object for a service account.
+func NewRoleBinding(namespace, name, roleName, serviceAccountName string) *rbac.RoleBinding {
+	return &rbac.RoleBinding{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "rbac.authorization.k8s.io/v1",
 			Kind:       "RoleBinding",
@@ -59,8 +67,6 @@ func (k *Kubernetes) CreateRoleBinding(namespace, name, roleName, serviceAccount
 			Name: serviceAccountName,
 		}},
 	}
-
-	return k.client.ApplyObject(m)
 }
 
 // CreateClusterRole creates a new cluster role.
@@ -0,0 +1,112 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// EverestServiceName is the name of the service exposing Everest.
+const EverestServiceName = "everest"
+
+// ValidateServiceType validates the type of the Everest service.
+func ValidateServiceType(t string) error {
+	switch corev1.ServiceType(t) {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		return nil
+	}
+	return fmt.Errorf("invalid service type %q. Allowed values are %s, %s and %s",
+		t, corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer)
+}
+
+// SetEverestServiceType makes the Everest manifest applied to the cluster
+// expose Everest with a service of the given type.
+// The type from the manifest is kept if empty.
+func (k *Kubernetes) SetEverestServiceType(t corev1.ServiceType) {
+	k.everestServiceType = t
+}
+
+// GetEverestServiceType returns the type of the Everest service in the namespace.
+// It returns an empty type if the service does not exist.
+func (k *Kubernetes) GetEverestServiceType(ctx context.Context, namespace string) (corev1.ServiceType, error) {
+	s, err := k.client.GetService(ctx, namespace, EverestServiceName)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return s.Spec.Type, nil
+}
+
+// SetServiceType sets the type of the Everest service if the object is the Everest service.
+func SetServiceType(u *unstructured.Unstructured, t corev1.ServiceType) error {
+	if t == "" || u.GetKind() != "Service" || u.GetName() != EverestServiceName {
+		return nil
+	}
+	if err := unstructured.SetNestedField(u.Object, string(t), "spec", "type"); err != nil {
+		return err
+	}
+	if t == corev1.ServiceTypeClusterIP {
+		// Node ports are not allowed for the ClusterIP services.
+		ports, ok, err := unstructured.NestedSlice(u.Object, "spec", "ports")
+		if err != nil || !ok {
+			return err
+		}
+		for _, p := range ports {
+			if m, ok := p.(map[string]interface{}); ok {
+				delete(m, "nodePort")
+			}
+		}
+		return unstructured.SetNestedSlice(u.Object, ports, "spec", "ports")
+	}
+	return nil
+}
+
+// customizeServiceType sets the type of the Everest service in the manifest.
+func (k *Kubernetes) customizeServiceType(data []byte) ([]byte, error) {
+	if k.everestServiceType == "" {
+		return data, nil
+	}
+
+	objs, err := decodeResources(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for i, o := range objs {
+		if err := SetServiceType(&o, k.everestServiceType); err != nil {
+			return nil, err
+		}
+		out, err := yaml.Marshal(o.Object)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		b.Write(out)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,145 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// DatabaseEngines implements the main logic for the list databaseengines command.
+type DatabaseEngines struct {
+	config DatabaseEnginesConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// DatabaseEnginesConfig stores configuration for the list databaseengines command.
+	DatabaseEnginesConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// Namespace limits the output to a single namespace.
+		// All the namespaces managed by Everest are listed if empty.
+		Namespace string `mapstructure:"namespace"`
+	}
+
+	// DatabaseEngine describes a database engine and its supported versions.
+	DatabaseEngine struct {
+		Namespace       string `json:"namespace"`
+		Type            string `json:"type"`
+		Status          string `json:"status"`
+		OperatorVersion string `json:"operatorVersion"`
+		// EngineVersions, BackupVersions and ProxyVersions hold the supported versions,
+		// the recommended ones first.
+		EngineVersions []string            `json:"engineVersions"`
+		BackupVersions []string            `json:"backupVersions"`
+		ProxyVersions  map[string][]string `json:"proxyVersions"`
+	}
+
+	// DatabaseEnginesResponse is a response from the list databaseengines command.
+	DatabaseEnginesResponse struct {
+		DatabaseEngines []DatabaseEngine `json:"databaseEngines"`
+	}
+)
+
+func (r DatabaseEnginesResponse) String() string {
+	if len(r.DatabaseEngines) == 0 {
+		return "There are no database engines"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tTYPE\tSTATUS\tOPERATOR VERSION\tENGINE VERSIONS\tBACKUP VERSIONS\tPROXY VERSIONS")
+	for _, e := range r.DatabaseEngines {
+		proxies := make([]string, 0, len(e.ProxyVersions))
+		for _, proxy := range sortedKeys(e.ProxyVersions) {
+			proxies = append(proxies, proxy+":"+strings.Join(e.ProxyVersions[proxy], ","))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Namespace, e.Type, e.Status, e.OperatorVersion,
+			strings.Join(e.EngineVersions, ","), strings.Join(e.BackupVersions, ","), strings.Join(proxies, " "))
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewDatabaseEngines returns a new DatabaseEngines struct.
+func NewDatabaseEngines(c DatabaseEnginesConfig, l *zap.SugaredLogger) (*DatabaseEngines, error) {
+	cli := &DatabaseEngines{
+		config: c,
+		l:      l.With("component", "list/databaseengines"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the list databaseengines command.
+func (d *DatabaseEngines) Run(ctx context.Context) (*DatabaseEnginesResponse, error) {
+	engines, err := listDatabaseEngines(ctx, d.kubeClient, d.config.SystemNamespace, d.config.Namespace, "")
+	if err != nil {
+		return nil, err
+	}
+
+	res := &DatabaseEnginesResponse{DatabaseEngines: make([]DatabaseEngine, 0, len(engines))}
+	for _, e := range engines {
+		res.DatabaseEngines = append(res.DatabaseEngines, newDatabaseEngine(e))
+	}
+	return res, nil
+}
+
+func newDatabaseEngine(e everestv1alpha1.DatabaseEngine) DatabaseEngine {
+	versions := e.Status.AvailableVersions
+	proxies := make(map[string][]string, len(versions.Proxy))
+	for proxy, components := range versions.Proxy {
+		proxies[string(proxy)] = components.GetAllowedVersionsSorted()
+	}
+	return DatabaseEngine{
+		Namespace:       e.Namespace,
+		Type:            string(e.Spec.Type),
+		Status:          string(e.Status.State),
+		OperatorVersion: e.Status.OperatorVersion,
+		EngineVersions:  versions.Engine.GetAllowedVersionsSorted(),
+		BackupVersions:  versions.Backup.GetAllowedVersionsSorted(),
+		ProxyVersions:   proxies,
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
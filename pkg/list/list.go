@@ -0,0 +1,72 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package list holds the logic of the list commands.
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// ValidateEngineType validates the type of a database engine.
+// An empty type matches all the engines.
+func ValidateEngineType(t string) error {
+	switch everestv1alpha1.EngineType(t) {
+	case "", everestv1alpha1.DatabaseEnginePXC, everestv1alpha1.DatabaseEnginePSMDB, everestv1alpha1.DatabaseEnginePostgresql:
+		return nil
+	}
+	return fmt.Errorf("invalid engine type %q. Allowed values are %s, %s and %s", t,
+		everestv1alpha1.DatabaseEnginePXC, everestv1alpha1.DatabaseEnginePSMDB, everestv1alpha1.DatabaseEnginePostgresql)
+}
+
+// listDatabaseEngines returns the database engines of the given type in the namespace.
+// All the namespaces managed by Everest are used if the namespace is empty.
+func listDatabaseEngines(
+	ctx context.Context,
+	k *kubernetes.Kubernetes,
+	systemNamespace, namespace, engineType string,
+) ([]everestv1alpha1.DatabaseEngine, error) {
+	namespaces := []string{namespace}
+	if namespace == "" {
+		ns, err := k.GetDBNamespaces(ctx, systemNamespace)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+		}
+		sort.Strings(ns)
+		namespaces = ns
+	}
+
+	engines := []everestv1alpha1.DatabaseEngine{}
+	for _, ns := range namespaces {
+		list, err := k.ListDatabaseEngines(ctx, ns)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not list database engines in %s namespace", ns))
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Spec.Type < list.Items[j].Spec.Type })
+		for _, e := range list.Items {
+			if engineType == "" || string(e.Spec.Type) == engineType {
+				engines = append(engines, e)
+			}
+		}
+	}
+	return engines, nil
+}
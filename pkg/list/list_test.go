@@ -0,0 +1,82 @@
+package list
+
+import (
+	"testing"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testEngine() everestv1alpha1.DatabaseEngine {
+	return everestv1alpha1.DatabaseEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "percona-xtradb-cluster-operator", Namespace: "dev"},
+		Spec:       everestv1alpha1.DatabaseEngineSpec{Type: everestv1alpha1.DatabaseEnginePXC},
+		Status: everestv1alpha1.DatabaseEngineStatus{
+			State:           everestv1alpha1.DBEngineStateInstalled,
+			OperatorVersion: "1.13.0",
+			AvailableVersions: everestv1alpha1.Versions{
+				Engine: everestv1alpha1.ComponentsMap{
+					"8.0.32-24.2":  {Status: everestv1alpha1.DBEngineComponentAvailable},
+					"8.0.35-27.1":  {Status: everestv1alpha1.DBEngineComponentRecommended},
+					"5.7.44-31.65": {Status: everestv1alpha1.DBEngineComponentRecommended},
+					"8.0.19-10.1":  {Status: everestv1alpha1.DBEngineComponentUnavailable},
+				},
+				Backup: everestv1alpha1.ComponentsMap{
+					"8.0.35-30.1": {Status: everestv1alpha1.DBEngineComponentRecommended},
+				},
+				Proxy: map[everestv1alpha1.ProxyType]everestv1alpha1.ComponentsMap{
+					"haproxy":  {"2.8.5": {Status: everestv1alpha1.DBEngineComponentRecommended}},
+					"proxysql": {"2.5.5": {Status: everestv1alpha1.DBEngineComponentRecommended}},
+				},
+			},
+		},
+	}
+}
+
+func TestNewDatabaseEngine(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DatabaseEngine{
+		Namespace:       "dev",
+		Type:            "pxc",
+		Status:          "installed",
+		OperatorVersion: "1.13.0",
+		EngineVersions:  []string{"8.0.35-27.1", "5.7.44-31.65", "8.0.32-24.2"},
+		BackupVersions:  []string{"8.0.35-30.1"},
+		ProxyVersions: map[string][]string{
+			"haproxy":  {"2.8.5"},
+			"proxysql": {"2.5.5"},
+		},
+	}, newDatabaseEngine(testEngine()))
+}
+
+func TestEngineVersions(t *testing.T) {
+	t.Parallel()
+
+	versions := engineVersions(testEngine())
+	components := make([]string, 0, len(versions))
+	for _, v := range versions {
+		assert.Equal(t, "dev", v.Namespace)
+		assert.Equal(t, "pxc", v.Engine)
+		components = append(components, v.Component+" "+v.Version+" "+v.Status)
+	}
+	assert.Equal(t, []string{
+		"engine 8.0.35-27.1 recommended",
+		"engine 8.0.32-24.2 available",
+		"engine 8.0.19-10.1 unavailable",
+		"engine 5.7.44-31.65 recommended",
+		"backup 8.0.35-30.1 recommended",
+		"proxy/haproxy 2.8.5 recommended",
+		"proxy/proxysql 2.5.5 recommended",
+	}, components)
+}
+
+func TestValidateEngineType(t *testing.T) {
+	t.Parallel()
+
+	for _, engine := range []string{"", "pxc", "psmdb", "postgresql"} {
+		assert.NoError(t, ValidateEngineType(engine))
+	}
+	assert.Error(t, ValidateEngineType("mysql"))
+}
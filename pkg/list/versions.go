@@ -0,0 +1,160 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+const (
+	// ComponentEngine is the component of the database engine versions.
+	ComponentEngine = "engine"
+	// ComponentBackup is the component of the backup tool versions.
+	ComponentBackup = "backup"
+	// componentProxyPrefix prefixes the components of the proxy versions.
+	componentProxyPrefix = "proxy/"
+)
+
+// Versions implements the main logic for the list versions command.
+type Versions struct {
+	config VersionsConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// VersionsConfig stores configuration for the list versions command.
+	VersionsConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// Namespace limits the output to a single namespace.
+		// All the namespaces managed by Everest are listed if empty.
+		Namespace string `mapstructure:"namespace"`
+		// Engine limits the output to a single engine type.
+		Engine string `mapstructure:"engine"`
+	}
+
+	// Version describes a version of a database engine component.
+	Version struct {
+		Namespace string `json:"namespace"`
+		Engine    string `json:"engine"`
+		// Component is engine, backup or proxy/<proxy type>.
+		Component string `json:"component"`
+		Version   string `json:"version"`
+		Status    string `json:"status"`
+		ImagePath string `json:"imagePath"`
+	}
+
+	// VersionsResponse is a response from the list versions command.
+	VersionsResponse struct {
+		Versions []Version `json:"versions"`
+	}
+)
+
+func (r VersionsResponse) String() string {
+	if len(r.Versions) == 0 {
+		return "There are no versions"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tENGINE\tCOMPONENT\tVERSION\tSTATUS")
+	for _, v := range r.Versions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", v.Namespace, v.Engine, v.Component, v.Version, v.Status)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewVersions returns a new Versions struct.
+func NewVersions(c VersionsConfig, l *zap.SugaredLogger) (*Versions, error) {
+	if err := ValidateEngineType(c.Engine); err != nil {
+		return nil, err
+	}
+
+	cli := &Versions{
+		config: c,
+		l:      l.With("component", "list/versions"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the list versions command.
+func (v *Versions) Run(ctx context.Context) (*VersionsResponse, error) {
+	engines, err := listDatabaseEngines(ctx, v.kubeClient, v.config.SystemNamespace, v.config.Namespace, v.config.Engine)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &VersionsResponse{Versions: []Version{}}
+	for _, e := range engines {
+		res.Versions = append(res.Versions, engineVersions(e)...)
+	}
+	return res, nil
+}
+
+// engineVersions returns the versions of all the components of the engine.
+// The versions of a component are sorted from the most recent one.
+func engineVersions(e everestv1alpha1.DatabaseEngine) []Version {
+	versions := []Version{}
+	add := func(component string, components everestv1alpha1.ComponentsMap) {
+		for _, version := range components.GetSortedVersions() {
+			c := components[version]
+			if c == nil {
+				continue
+			}
+			versions = append(versions, Version{
+				Namespace: e.Namespace,
+				Engine:    string(e.Spec.Type),
+				Component: component,
+				Version:   version,
+				Status:    string(c.Status),
+				ImagePath: c.ImagePath,
+			})
+		}
+	}
+
+	available := e.Status.AvailableVersions
+	add(ComponentEngine, available.Engine)
+	add(ComponentBackup, available.Backup)
+	proxies := make(map[string]everestv1alpha1.ComponentsMap, len(available.Proxy))
+	for proxy, components := range available.Proxy {
+		proxies[string(proxy)] = components
+	}
+	for _, proxy := range sortedKeys(proxies) {
+		add(componentProxyPrefix+proxy, proxies[proxy])
+	}
+	return versions
+}
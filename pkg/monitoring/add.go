@@ -0,0 +1,162 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Add implements the main logic for the monitoring add command.
+type Add struct {
+	config AddConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// AddConfig stores configuration for the monitoring add command.
+type AddConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// Name is the name of the monitoring instance.
+	Name string `mapstructure:"-"`
+
+	// URL is the URL of the PMM instance.
+	URL string `mapstructure:"url"`
+	// APIKey is the PMM API key. Either the API key or the username
+	// and the password are expected.
+	APIKey string `mapstructure:"api-key"`
+	// Username and Password are the PMM credentials used to create an API key.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// SkipTLSVerify skips the verification of the PMM certificate.
+	SkipTLSVerify bool `mapstructure:"skip-tls-verify"`
+
+	// AllowedNamespaces is a comma-separated list of the namespaces allowed to use the instance.
+	// All the namespaces managed by Everest are allowed if empty.
+	AllowedNamespaces string `mapstructure:"allowed-namespaces"`
+}
+
+// NewAdd returns a new Add struct.
+func NewAdd(c AddConfig, l *zap.SugaredLogger) (*Add, error) {
+	cli := &Add{
+		config: c,
+		l:      l.With("component", "monitoring/add"),
+	}
+	if err := cli.config.validate(); err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// validate validates the config without contacting the cluster.
+func (c AddConfig) validate() error {
+	if errs := validation.IsDNS1035Label(c.Name); len(errs) != 0 {
+		return fmt.Errorf("invalid monitoring instance name %q: %s", c.Name, strings.Join(errs, ", "))
+	}
+	if err := validateURL(c.URL); err != nil {
+		return err
+	}
+	return c.credentials().validate()
+}
+
+func (c AddConfig) credentials() credentials {
+	return credentials{APIKey: c.APIKey, Username: c.Username, Password: c.Password}
+}
+
+// Run runs the monitoring add command.
+func (a *Add) Run(ctx context.Context) (*InstanceResponse, error) {
+	namespace, err := install.LoadMonitoringNamespace(ctx, a.kubeClient, a.config.SystemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	_, err = a.kubeClient.GetMonitoringConfig(ctx, namespace, a.config.Name)
+	if err == nil {
+		return nil, fmt.Errorf("monitoring instance %s already exists", a.config.Name)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s monitoring instance", a.config.Name))
+	}
+
+	namespaces, err := install.AllowedNamespaces(ctx, a.kubeClient, a.config.SystemNamespace, a.config.AllowedNamespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	a.l.Infof("Checking PMM API at %s", a.config.URL)
+	pmm := newPMMClient(a.config.URL, a.config.SkipTLSVerify)
+	apiKey, version, err := a.config.credentials().apiKey(ctx, pmm, a.config.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	mc := &everestv1alpha1.MonitoringConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      a.config.Name,
+			Namespace: namespace,
+		},
+		Spec: everestv1alpha1.MonitoringConfigSpec{
+			Type:                  everestv1alpha1.PMMMonitoringType,
+			CredentialsSecretName: a.config.Name,
+			AllowedNamespaces:     namespaces,
+			PMM: everestv1alpha1.PMMConfig{
+				URL: a.config.URL,
+			},
+		},
+	}
+
+	a.l.Infof("Creating %s monitoring instance", a.config.Name)
+	err = a.kubeClient.CreateSecret(newSecret(namespace, a.config.Name, apiKey.key))
+	if apierrors.IsAlreadyExists(err) {
+		err = fmt.Errorf("secret %s already exists in %s namespace. Delete it or choose another name for the monitoring instance",
+			a.config.Name, namespace)
+		return nil, a.config.credentials().revokeAPIKey(ctx, pmm, apiKey, err)
+	}
+	if err != nil {
+		err = errors.Join(err, errors.New("could not create the credentials secret"))
+		return nil, a.config.credentials().revokeAPIKey(ctx, pmm, apiKey, err)
+	}
+	if err := a.kubeClient.CreateMonitoringConfig(ctx, mc); err != nil {
+		err = errors.Join(err, fmt.Errorf("could not create %s monitoring instance", a.config.Name))
+		if dErr := a.kubeClient.DeleteSecret(namespace, a.config.Name); dErr != nil {
+			err = errors.Join(err, dErr, errors.New("could not delete the credentials secret"))
+		}
+		return nil, a.config.credentials().revokeAPIKey(ctx, pmm, apiKey, err)
+	}
+
+	return &InstanceResponse{Instance: newInstance(mc), PMMVersion: version}, nil
+}
@@ -0,0 +1,117 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/AlecAivazis/survey/v2"
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Delete implements the main logic for the monitoring delete command.
+type Delete struct {
+	config DeleteConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// DeleteConfig stores configuration for the monitoring delete command.
+type DeleteConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// Name is the name of the monitoring instance.
+	Name string `mapstructure:"-"`
+	// AssumeYes is true when all questions can be skipped.
+	AssumeYes bool `mapstructure:"assume-yes"`
+}
+
+// NewDelete returns a new Delete struct.
+func NewDelete(c DeleteConfig, l *zap.SugaredLogger) (*Delete, error) {
+	cli := &Delete{
+		config: c,
+		l:      l.With("component", "monitoring/delete"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the monitoring delete command.
+func (d *Delete) Run(ctx context.Context) error {
+	namespace, err := install.LoadMonitoringNamespace(ctx, d.kubeClient, d.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	mc, err := d.kubeClient.GetMonitoringConfig(ctx, namespace, d.config.Name)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not get %s monitoring instance", d.config.Name))
+	}
+	used, err := d.kubeClient.IsMonitoringConfigUsed(ctx, d.config.SystemNamespace, d.config.Name)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not check if %s monitoring instance is used", d.config.Name))
+	}
+	if used {
+		return fmt.Errorf("monitoring instance %s is used by database clusters. "+
+			"Disable their monitoring before deleting the instance", d.config.Name)
+	}
+
+	if !d.config.AssumeYes {
+		confirm := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to delete %s monitoring instance?", d.config.Name),
+		}
+		prompt := false
+		if err := survey.AskOne(confirm, &prompt); err != nil {
+			return err
+		}
+		if !prompt {
+			d.l.Info("Exiting")
+			return nil
+		}
+	}
+
+	if err := d.kubeClient.DeleteMonitoringConfig(ctx, namespace, d.config.Name); err != nil {
+		return errors.Join(err, fmt.Errorf("could not delete %s monitoring instance", d.config.Name))
+	}
+	// The secret is kept while other monitoring instances use it.
+	others, err := d.kubeClient.GetMonitoringConfigsBySecretName(ctx, namespace, mc.Spec.CredentialsSecretName)
+	if err != nil {
+		return errors.Join(err, errors.New("could not list monitoring instances"))
+	}
+	others = slices.DeleteFunc(others, func(o *everestv1alpha1.MonitoringConfig) bool { return o.Name == d.config.Name })
+	if len(others) == 0 {
+		if err := d.kubeClient.DeleteSecret(namespace, mc.Spec.CredentialsSecretName); err != nil {
+			return errors.Join(err, fmt.Errorf("could not delete the credentials secret of %s monitoring instance", d.config.Name))
+		}
+	}
+	d.l.Infof("Monitoring instance %s has been deleted", d.config.Name)
+	return nil
+}
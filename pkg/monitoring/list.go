@@ -0,0 +1,108 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// List implements the main logic for the monitoring list command.
+type List struct {
+	config ListConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// ListConfig stores configuration for the monitoring list command.
+	ListConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+	}
+
+	// ListResponse is a response from the monitoring list command.
+	ListResponse struct {
+		Instances []Instance `json:"instances"`
+	}
+)
+
+func (r ListResponse) String() string {
+	if len(r.Instances) == 0 {
+		return "There are no monitoring instances"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAME\tTYPE\tURL\tALLOWED NAMESPACES")
+	for _, i := range r.Instances {
+		allowed := "all"
+		if len(i.AllowedNamespaces) != 0 {
+			allowed = strings.Join(i.AllowedNamespaces, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", i.Name, i.Type, i.URL, allowed)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewList returns a new List struct.
+func NewList(c ListConfig, l *zap.SugaredLogger) (*List, error) {
+	cli := &List{
+		config: c,
+		l:      l.With("component", "monitoring/list"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the monitoring list command.
+func (l *List) Run(ctx context.Context) (*ListResponse, error) {
+	namespace, err := install.LoadMonitoringNamespace(ctx, l.kubeClient, l.config.SystemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	mcs, err := l.kubeClient.ListMonitoringConfigs(ctx, namespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not list monitoring instances"))
+	}
+	sort.Slice(mcs.Items, func(i, j int) bool { return mcs.Items[i].Name < mcs.Items[j].Name })
+
+	res := &ListResponse{Instances: make([]Instance, 0, len(mcs.Items))}
+	for i := range mcs.Items {
+		res.Instances = append(res.Instances, newInstance(&mcs.Items[i]))
+	}
+	return res, nil
+}
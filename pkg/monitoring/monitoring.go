@@ -0,0 +1,162 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package monitoring holds the logic of the monitoring commands managing PMM instances.
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pmmAPIKeyUsername is the username PMM expects together with an API key.
+const pmmAPIKeyUsername = "api_key"
+
+type (
+	// Instance describes a monitoring instance.
+	Instance struct {
+		Name              string   `json:"name"`
+		Type              string   `json:"type"`
+		URL               string   `json:"url"`
+		AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	}
+
+	// InstanceResponse is a response from the monitoring commands describing a single instance.
+	InstanceResponse struct {
+		Instance   Instance `json:"instance"`
+		PMMVersion string   `json:"pmmVersion,omitempty"`
+	}
+
+	// credentials are the flags used to authenticate in PMM.
+	credentials struct {
+		APIKey   string
+		Username string
+		Password string
+	}
+)
+
+func (r InstanceResponse) String() string {
+	i := r.Instance
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", i.Name)
+	fmt.Fprintf(w, "Type:\t%s\n", i.Type)
+	fmt.Fprintf(w, "URL:\t%s\n", i.URL)
+	if r.PMMVersion != "" {
+		fmt.Fprintf(w, "PMM version:\t%s\n", r.PMMVersion)
+	}
+	allowed := "all"
+	if len(i.AllowedNamespaces) != 0 {
+		allowed = strings.Join(i.AllowedNamespaces, ", ")
+	}
+	fmt.Fprintf(w, "Allowed namespaces:\t%s\n", allowed)
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// newInstance returns the description of the monitoring config.
+func newInstance(mc *everestv1alpha1.MonitoringConfig) Instance {
+	return Instance{
+		Name:              mc.Name,
+		Type:              string(mc.Spec.Type),
+		URL:               mc.Spec.PMM.URL,
+		AllowedNamespaces: mc.Spec.AllowedNamespaces,
+	}
+}
+
+// validateURL validates the URL of a PMM instance.
+func validateURL(pmmURL string) error {
+	u, err := url.Parse(pmmURL)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("invalid URL %q", pmmURL))
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q. The URL must start with http:// or https://", pmmURL)
+	}
+	return nil
+}
+
+// validate validates the credentials. Either the API key or the username
+// and the password are expected.
+func (c credentials) validate() error {
+	if c.APIKey != "" && (c.Username != "" || c.Password != "") {
+		return errors.New("either API key or username and password are expected, not both")
+	}
+	if c.APIKey == "" && (c.Username == "" || c.Password == "") {
+		return errors.New("API key or username and password are required")
+	}
+	return nil
+}
+
+// apiKey returns the API key of the credentials and the version of PMM.
+// A new API key is created in PMM if the username and the password are given.
+// It is revoked again if PMM does not accept it.
+func (c credentials) apiKey(ctx context.Context, pmm *pmmClient, name string) (pmmAPIKey, string, error) {
+	apiKey := pmmAPIKey{key: c.APIKey}
+	if apiKey.key == "" {
+		var err error
+		keyName := fmt.Sprintf("everest-%s-%d", name, time.Now().Unix())
+		if apiKey, err = pmm.createAPIKey(ctx, keyName, c.Username, c.Password); err != nil {
+			return pmmAPIKey{}, "", err
+		}
+	}
+	version, err := pmm.version(ctx, apiKey.key)
+	if err != nil {
+		return pmmAPIKey{}, "", c.revokeAPIKey(ctx, pmm, apiKey, err)
+	}
+	return apiKey, version, nil
+}
+
+// revokeAPIKey deletes the API key created from the username and the password
+// after a later step has failed with err. The given API keys are kept.
+// It returns err joined with the error of the deletion.
+func (c credentials) revokeAPIKey(ctx context.Context, pmm *pmmClient, apiKey pmmAPIKey, err error) error {
+	if apiKey.id == 0 {
+		return err
+	}
+	if dErr := pmm.deleteAPIKey(ctx, apiKey.id, c.Username, c.Password); dErr != nil {
+		return errors.Join(err, dErr)
+	}
+	return err
+}
+
+// newSecret returns the secret with the credentials of the monitoring config.
+func newSecret(namespace, name, apiKey string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			everestv1alpha1.MonitoringConfigCredentialsSecretUsernameKey: []byte(pmmAPIKeyUsername),
+			everestv1alpha1.MonitoringConfigCredentialsSecretAPIKeyKey:   []byte(apiKey),
+		},
+	}
+}
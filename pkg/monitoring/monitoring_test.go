@@ -0,0 +1,108 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, credentials{APIKey: "key"}.validate())
+	assert.NoError(t, credentials{Username: "admin", Password: "admin"}.validate())
+	assert.Error(t, credentials{}.validate())
+	assert.Error(t, credentials{Username: "admin"}.validate())
+	assert.Error(t, credentials{APIKey: "key", Username: "admin", Password: "admin"}.validate())
+}
+
+func TestValidateURL(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validateURL("https://pmm.example.com"))
+	assert.NoError(t, validateURL("http://10.0.0.1:8080/"))
+	assert.Error(t, validateURL("pmm.example.com"))
+	assert.Error(t, validateURL("ftp://pmm.example.com"))
+}
+
+func TestCredentialsAPIKey(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	deleted := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		authorized := ok && password == "secret" && (user == "admin" || user == "viewer")
+		switch r.URL.Path {
+		case "/graph/api/auth/keys":
+			if !authorized {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			// The keys created by viewers are not accepted by the PMM API.
+			res := map[string]interface{}{"id": 1, "key": "created-key"}
+			if user == "viewer" {
+				res = map[string]interface{}{"id": 2, "key": "viewer-key"}
+			}
+			json.NewEncoder(w).Encode(res) //nolint:errcheck
+		case "/graph/api/auth/keys/1", "/graph/api/auth/keys/2":
+			if !authorized || r.Method != http.MethodDelete {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mu.Unlock()
+			json.NewEncoder(w).Encode(map[string]string{"message": "API key deleted"}) //nolint:errcheck
+		case "/v1/version":
+			if r.Header.Get("Authorization") != "Bearer created-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"version": "2.41.0"}) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	pmm := newPMMClient(srv.URL+"/", false)
+	ctx := context.Background()
+
+	key, version, err := credentials{Username: "admin", Password: "secret"}.apiKey(ctx, pmm, "pmm")
+	require.NoError(t, err)
+	assert.Equal(t, pmmAPIKey{key: "created-key", id: 1}, key)
+	assert.Equal(t, "2.41.0", version)
+
+	key, _, err = credentials{APIKey: "created-key"}.apiKey(ctx, pmm, "pmm")
+	require.NoError(t, err)
+	assert.Equal(t, pmmAPIKey{key: "created-key"}, key)
+
+	_, _, err = credentials{APIKey: "invalid"}.apiKey(ctx, pmm, "pmm")
+	require.Error(t, err)
+
+	_, _, err = credentials{Username: "admin", Password: "wrong"}.apiKey(ctx, pmm, "pmm")
+	require.Error(t, err)
+	assert.Empty(t, deleted)
+
+	_, _, err = credentials{Username: "viewer", Password: "secret"}.apiKey(ctx, pmm, "pmm")
+	require.Error(t, err)
+	assert.Equal(t, []string{"/graph/api/auth/keys/2"}, deleted, "the rejected key is revoked")
+
+	failed := errors.New("could not create the credentials secret")
+	creds := credentials{Username: "admin", Password: "secret"}
+	err = creds.revokeAPIKey(ctx, pmm, pmmAPIKey{key: "created-key", id: 1}, failed)
+	require.ErrorIs(t, err, failed)
+	assert.Equal(t, []string{"/graph/api/auth/keys/2", "/graph/api/auth/keys/1"}, deleted)
+
+	err = credentials{APIKey: "created-key"}.revokeAPIKey(ctx, pmm, pmmAPIKey{key: "created-key"}, failed)
+	require.ErrorIs(t, err, failed)
+	assert.Len(t, deleted, 2, "the given keys are kept")
+}
@@ -0,0 +1,133 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// pmmRequestTimeout is the timeout of the requests to the PMM API.
+const pmmRequestTimeout = 30 * time.Second
+
+// pmmClient talks to the PMM API.
+type pmmClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+func newPMMClient(url string, skipTLSVerify bool) *pmmClient {
+	return &pmmClient{
+		url: strings.TrimSuffix(url, "/"),
+		httpClient: &http.Client{
+			Timeout: pmmRequestTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: skipTLSVerify}, //nolint:gosec
+			},
+		},
+	}
+}
+
+// pmmAPIKey is an API key of PMM.
+type pmmAPIKey struct {
+	key string
+	// id is the id of the API key created from the username and the password.
+	// It is zero if the API key was given.
+	id int64
+}
+
+// createAPIKey creates an admin API key authenticating with the username and password.
+func (c *pmmClient) createAPIKey(ctx context.Context, name, username, password string) (pmmAPIKey, error) {
+	body, err := json.Marshal(map[string]string{"name": name, "role": "Admin"})
+	if err != nil {
+		return pmmAPIKey{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/graph/api/auth/keys", bytes.NewReader(body))
+	if err != nil {
+		return pmmAPIKey{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(username, password)
+
+	var res struct {
+		ID  int64  `json:"id"`
+		Key string `json:"key"`
+	}
+	if err := c.do(req, &res); err != nil {
+		return pmmAPIKey{}, errors.Join(err, errors.New("could not create PMM API key"))
+	}
+	if res.Key == "" {
+		return pmmAPIKey{}, errors.New("PMM has not returned an API key")
+	}
+	return pmmAPIKey{key: res.Key, id: res.ID}, nil
+}
+
+// deleteAPIKey deletes the API key authenticating with the username and password.
+func (c *pmmClient) deleteAPIKey(ctx context.Context, id int64, username, password string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/graph/api/auth/keys/%d", c.url, id), nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(username, password)
+
+	var res struct{}
+	if err := c.do(req, &res); err != nil {
+		return errors.Join(err, fmt.Errorf("could not delete PMM API key %d", id))
+	}
+	return nil
+}
+
+// version returns the version of PMM checking the API key is valid.
+func (c *pmmClient) version(ctx context.Context, apiKey string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/v1/version", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	var res struct {
+		Version string `json:"version"`
+	}
+	if err := c.do(req, &res); err != nil {
+		return "", errors.Join(err, errors.New("could not get PMM version"))
+	}
+	return res.Version, nil
+}
+
+func (c *pmmClient) do(req *http.Request, res interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("PMM API returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, res)
+}
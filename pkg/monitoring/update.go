@@ -0,0 +1,152 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Update implements the main logic for the monitoring update command.
+type Update struct {
+	config UpdateConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// UpdateConfig stores configuration for the monitoring update command.
+// The empty fields keep the current values.
+type UpdateConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// Name is the name of the monitoring instance.
+	Name string `mapstructure:"-"`
+
+	// URL is the URL of the PMM instance.
+	URL string `mapstructure:"url"`
+	// APIKey is the PMM API key.
+	APIKey string `mapstructure:"api-key"`
+	// Username and Password are the PMM credentials used to create an API key.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// SkipTLSVerify skips the verification of the PMM certificate.
+	SkipTLSVerify bool `mapstructure:"skip-tls-verify"`
+
+	// AllowedNamespaces is a comma-separated list of the namespaces allowed to use the instance.
+	AllowedNamespaces string `mapstructure:"allowed-namespaces"`
+}
+
+// NewUpdate returns a new Update struct.
+func NewUpdate(c UpdateConfig, l *zap.SugaredLogger) (*Update, error) {
+	cli := &Update{
+		config: c,
+		l:      l.With("component", "monitoring/update"),
+	}
+	if err := cli.config.validate(); err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// validate validates the config without contacting the cluster.
+func (c UpdateConfig) validate() error {
+	if c.URL != "" {
+		if err := validateURL(c.URL); err != nil {
+			return err
+		}
+	}
+	if c.credentialsChanged() {
+		return c.credentials().validate()
+	}
+	return nil
+}
+
+func (c UpdateConfig) credentials() credentials {
+	return credentials{APIKey: c.APIKey, Username: c.Username, Password: c.Password}
+}
+
+func (c UpdateConfig) credentialsChanged() bool {
+	return c.APIKey != "" || c.Username != "" || c.Password != ""
+}
+
+// Run runs the monitoring update command.
+func (u *Update) Run(ctx context.Context) (*InstanceResponse, error) {
+	namespace, err := install.LoadMonitoringNamespace(ctx, u.kubeClient, u.config.SystemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	mc, err := u.kubeClient.GetMonitoringConfig(ctx, namespace, u.config.Name)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s monitoring instance", u.config.Name))
+	}
+
+	if u.config.AllowedNamespaces != "" {
+		namespaces, err := install.AllowedNamespaces(ctx, u.kubeClient, u.config.SystemNamespace, u.config.AllowedNamespaces)
+		if err != nil {
+			return nil, err
+		}
+		mc.Spec.AllowedNamespaces = namespaces
+	}
+	if u.config.URL != "" {
+		mc.Spec.PMM.URL = u.config.URL
+	}
+
+	creds := u.config.credentials()
+	if !u.config.credentialsChanged() {
+		secret, err := u.kubeClient.GetSecret(ctx, mc.Spec.CredentialsSecretName, namespace)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not get the credentials secret of %s monitoring instance", u.config.Name))
+		}
+		creds.APIKey = string(secret.Data[everestv1alpha1.MonitoringConfigCredentialsSecretAPIKeyKey])
+	}
+
+	u.l.Infof("Checking PMM API at %s", mc.Spec.PMM.URL)
+	pmm := newPMMClient(mc.Spec.PMM.URL, u.config.SkipTLSVerify)
+	apiKey, version, err := creds.apiKey(ctx, pmm, u.config.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	u.l.Infof("Updating %s monitoring instance", u.config.Name)
+	if u.config.credentialsChanged() {
+		if err := u.kubeClient.SetSecret(newSecret(namespace, mc.Spec.CredentialsSecretName, apiKey.key)); err != nil {
+			err = errors.Join(err, errors.New("could not update the credentials secret"))
+			return nil, creds.revokeAPIKey(ctx, pmm, apiKey, err)
+		}
+	}
+	if err := u.kubeClient.UpdateMonitoringConfig(ctx, mc); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not update %s monitoring instance", u.config.Name))
+	}
+
+	return &InstanceResponse{Instance: newInstance(mc), PMMVersion: version}, nil
+}
@@ -0,0 +1,109 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package namespaces holds the logic of the namespaces commands.
+package namespaces
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+// Add implements the main logic for the namespaces add command.
+type Add struct {
+	config    AddConfig
+	installer *install.Install
+	l         *zap.SugaredLogger
+}
+
+// AddConfig stores configuration for the namespaces add command.
+type AddConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// Namespaces is a comma-separated list of namespaces to add.
+	Namespaces string `mapstructure:"namespaces"`
+	// DisableTelemetry disables telemetry in the installed operators.
+	DisableTelemetry bool `mapstructure:"disable-telemetry"`
+	// DryRun records the changes instead of applying them to the cluster.
+	DryRun bool `mapstructure:"dry-run"`
+	// InstallPlanApproval is the approval policy of the install plans.
+	InstallPlanApproval string `mapstructure:"install-plan-approval"`
+	// NamespaceOperators is a raw mapping of namespaces to the operators
+	// installed into them such as "dev=pg,mongodb;prod=pxc".
+	NamespaceOperators string `mapstructure:"namespace-operators"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+	// RegistryMirror maps the source registries to the private registry mirrors.
+	// The registry of the installation is used if both RegistryMirror and ImagePullSecret are empty.
+	RegistryMirror []string `mapstructure:"registry-mirror"`
+	// ImagePullSecret is the name of the secret used to pull the images.
+	ImagePullSecret string `mapstructure:"image-pull-secret"`
+
+	Operator install.OperatorConfig
+	Channel  install.ChannelConfig
+}
+
+// NewAdd returns a new Add struct.
+func NewAdd(c AddConfig, l *zap.SugaredLogger) (*Add, error) {
+	cli := &Add{
+		config: c,
+		l:      l.With("component", "namespaces/add"),
+	}
+
+	i, err := install.NewInstall(c.installConfig(), cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.installer = i
+
+	return cli, nil
+}
+
+// installConfig returns the config of the installer adding the namespaces.
+func (c AddConfig) installConfig() install.Config {
+	return install.Config{
+		KubeconfigPath:      c.KubeconfigPath,
+		Namespaces:          c.Namespaces,
+		DisableTelemetry:    c.DisableTelemetry,
+		DryRun:              c.DryRun,
+		InstallPlanApproval: c.InstallPlanApproval,
+		NamespaceOperators:  c.NamespaceOperators,
+		SystemNamespace:     c.SystemNamespace,
+		RegistryMirror:      c.RegistryMirror,
+		ImagePullSecret:     c.ImagePullSecret,
+		Operator:            c.Operator,
+		Channel:             c.Channel,
+	}
+}
+
+// Plan returns the changes recorded in dry-run mode.
+func (a *Add) Plan() *client.Plan {
+	return a.installer.Plan()
+}
+
+// Run runs the namespaces add command.
+func (a *Add) Run(ctx context.Context) error {
+	namespaces, err := a.installer.AddNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	a.l.Infof("Everest manages %s namespaces", strings.Join(namespaces, ", "))
+	return nil
+}
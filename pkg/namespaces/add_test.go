@@ -0,0 +1,55 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespaces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+)
+
+func TestAddConfigInstallConfig(t *testing.T) {
+	t.Parallel()
+
+	c := AddConfig{
+		KubeconfigPath:      "~/.kube/config",
+		Namespaces:          "dev,prod",
+		DisableTelemetry:    true,
+		DryRun:              true,
+		InstallPlanApproval: install.InstallPlanApprovalAutomatic,
+		NamespaceOperators:  "dev=pg",
+		SystemNamespace:     "tenant-a",
+		RegistryMirror:      []string{"docker.io=harbor.example.com/dockerhub"},
+		ImagePullSecret:     "harbor",
+		Operator:            install.OperatorConfig{PG: true},
+		Channel:             install.ChannelConfig{PG: "fast-v2"},
+	}
+	assert.Equal(t, install.Config{
+		KubeconfigPath:      "~/.kube/config",
+		Namespaces:          "dev,prod",
+		DisableTelemetry:    true,
+		DryRun:              true,
+		InstallPlanApproval: install.InstallPlanApprovalAutomatic,
+		NamespaceOperators:  "dev=pg",
+		SystemNamespace:     "tenant-a",
+		RegistryMirror:      []string{"docker.io=harbor.example.com/dockerhub"},
+		ImagePullSecret:     "harbor",
+		Operator:            install.OperatorConfig{PG: true},
+		Channel:             install.ChannelConfig{PG: "fast-v2"},
+	}, c.installConfig())
+}
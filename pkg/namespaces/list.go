@@ -0,0 +1,116 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespaces
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// List implements the main logic for the namespaces list command.
+type List struct {
+	config ListConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// ListConfig stores configuration for the namespaces list command.
+	ListConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+	}
+
+	// ManagedNamespace describes a namespace managed by Everest.
+	ManagedNamespace struct {
+		Name string `json:"name"`
+		// Operators holds the installed cluster service versions of the database operators.
+		Operators []string `json:"operators"`
+	}
+
+	// ListResponse is a response from the namespaces list command.
+	ListResponse struct {
+		Namespaces []ManagedNamespace `json:"namespaces"`
+	}
+)
+
+func (r ListResponse) String() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tOPERATORS")
+	for _, ns := range r.Namespaces {
+		fmt.Fprintf(w, "%s\t%s\n", ns.Name, strings.Join(ns.Operators, ","))
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewList returns a new List struct.
+func NewList(c ListConfig, l *zap.SugaredLogger) (*List, error) {
+	cli := &List{
+		config: c,
+		l:      l.With("component", "namespaces/list"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the namespaces list command.
+func (l *List) Run(ctx context.Context) (*ListResponse, error) {
+	dbNamespaces, err := l.kubeClient.GetDBNamespaces(ctx, l.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+	sort.Strings(dbNamespaces)
+
+	namespaces := make([]ManagedNamespace, 0, len(dbNamespaces))
+	for _, ns := range dbNamespaces {
+		subs, err := l.kubeClient.ListSubscriptions(ctx, ns)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not list subscriptions in %s namespace", ns))
+		}
+		operators := make([]string, 0, len(subs.Items))
+		for _, s := range subs.Items {
+			if s.Status.InstalledCSV == "" {
+				operators = append(operators, s.Name)
+				continue
+			}
+			operators = append(operators, s.Status.InstalledCSV)
+		}
+		sort.Strings(operators)
+		namespaces = append(namespaces, ManagedNamespace{Name: ns, Operators: operators})
+	}
+
+	return &ListResponse{Namespaces: namespaces}, nil
+}
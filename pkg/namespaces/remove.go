@@ -0,0 +1,100 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespaces
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+)
+
+// Remove implements the main logic for the namespaces remove command.
+type Remove struct {
+	config    RemoveConfig
+	installer *install.Install
+	l         *zap.SugaredLogger
+}
+
+// RemoveConfig stores configuration for the namespaces remove command.
+type RemoveConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// Namespaces is a comma-separated list of namespaces to remove.
+	Namespaces string `mapstructure:"namespaces"`
+	// AssumeYes is true when all questions can be skipped.
+	AssumeYes bool `mapstructure:"assume-yes"`
+	// DryRun records the changes instead of applying them to the cluster.
+	DryRun bool `mapstructure:"dry-run"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+}
+
+// NewRemove returns a new Remove struct.
+func NewRemove(c RemoveConfig, l *zap.SugaredLogger) (*Remove, error) {
+	cli := &Remove{
+		config: c,
+		l:      l.With("component", "namespaces/remove"),
+	}
+
+	i, err := install.NewInstall(install.Config{
+		KubeconfigPath:  c.KubeconfigPath,
+		Namespaces:      c.Namespaces,
+		DryRun:          c.DryRun,
+		SystemNamespace: c.SystemNamespace,
+	}, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.installer = i
+
+	return cli, nil
+}
+
+// Plan returns the changes recorded in dry-run mode.
+func (r *Remove) Plan() *client.Plan {
+	return r.installer.Plan()
+}
+
+// Run runs the namespaces remove command.
+func (r *Remove) Run(ctx context.Context) error {
+	if !r.config.AssumeYes && !r.config.DryRun {
+		confirm := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to remove the operators from %s namespaces?", r.config.Namespaces),
+		}
+		prompt := false
+		if err := survey.AskOne(confirm, &prompt); err != nil {
+			return err
+		}
+
+		if !prompt {
+			r.l.Info("Exiting")
+			return nil
+		}
+	}
+
+	namespaces, err := r.installer.RemoveNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	r.l.Infof("Everest manages %s namespaces", strings.Join(namespaces, ", "))
+	return nil
+}
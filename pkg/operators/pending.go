@@ -0,0 +1,161 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package operators holds the logic of the operators commands.
+package operators
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Pending implements the main logic for the pending command.
+type Pending struct {
+	config PendingConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// PendingConfig stores configuration for the pending command.
+	PendingConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// Approve asks to approve every pending install plan.
+		Approve bool `mapstructure:"approve"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+	}
+
+	// PendingInstallPlan describes an install plan waiting for approval.
+	PendingInstallPlan struct {
+		Namespace   string   `json:"namespace"`
+		Operator    string   `json:"operator"`
+		InstallPlan string   `json:"installPlan"`
+		CSVs        []string `json:"csvs"`
+		Approved    bool     `json:"approved"`
+
+		upgrade PendingUpgrade
+	}
+
+	// PendingResponse is a response from the pending command.
+	PendingResponse struct {
+		InstallPlans []PendingInstallPlan `json:"installPlans"`
+	}
+)
+
+func (r PendingResponse) String() string {
+	if len(r.InstallPlans) == 0 {
+		return "There are no pending install plans"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tOPERATOR\tINSTALL PLAN\tCSV\tAPPROVED")
+	for _, ip := range r.InstallPlans {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", ip.Namespace, ip.Operator, ip.InstallPlan, strings.Join(ip.CSVs, ","), ip.Approved)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewPending returns a new Pending struct.
+func NewPending(c PendingConfig, l *zap.SugaredLogger) (*Pending, error) {
+	cli := &Pending{
+		config: c,
+		l:      l.With("component", "operators/pending"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the pending command.
+func (p *Pending) Run(ctx context.Context) (*PendingResponse, error) {
+	pending, err := p.listPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PendingResponse{InstallPlans: pending}
+	if p.config.Approve {
+		for i := range pending {
+			if err := p.approve(ctx, &pending[i]); err != nil {
+				// The install plans approved so far are reported along with the error.
+				return res, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// listPending returns the unapproved install plans of the Everest subscriptions.
+func (p *Pending) listPending(ctx context.Context) ([]PendingInstallPlan, error) {
+	dbNamespaces, err := p.kubeClient.GetDBNamespaces(ctx, p.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+	monitoringNamespace, err := install.LoadMonitoringNamespace(ctx, p.kubeClient, p.config.SystemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := append([]string{p.config.SystemNamespace, monitoringNamespace}, dbNamespaces...)
+
+	upgrades, err := ListPendingUpgrades(ctx, p.kubeClient, namespaces, nil)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]PendingInstallPlan, 0, len(upgrades))
+	for _, u := range upgrades {
+		pending = append(pending, PendingInstallPlan{
+			Namespace:   u.Subscription.Namespace,
+			Operator:    u.Subscription.Name,
+			InstallPlan: u.InstallPlan.Name,
+			CSVs:        u.CSVs(),
+			upgrade:     u,
+		})
+	}
+
+	return pending, nil
+}
+
+func (p *Pending) approve(ctx context.Context, ip *PendingInstallPlan) error {
+	message := fmt.Sprintf("Approve install plan %s of %s in %s namespace installing %s?",
+		ip.InstallPlan, ip.Operator, ip.Namespace, strings.Join(ip.CSVs, ", "))
+	approved, err := ApproveUpgrade(ctx, p.kubeClient, ip.upgrade, message, false)
+	if err != nil || !approved {
+		return err
+	}
+	p.l.Infof("Install plan %s of %s has been approved", ip.InstallPlan, ip.Operator)
+	ip.Approved = true
+
+	return nil
+}
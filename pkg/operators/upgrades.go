@@ -0,0 +1,109 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// PendingUpgrade is an install plan of a subscription waiting for approval.
+type PendingUpgrade struct {
+	Subscription olmv1alpha1.Subscription
+	InstallPlan  *olmv1alpha1.InstallPlan
+}
+
+// CSVs returns the CSVs installed by the install plan.
+func (u PendingUpgrade) CSVs() []string {
+	if u.InstallPlan == nil {
+		return nil
+	}
+	return u.InstallPlan.Spec.ClusterServiceVersionNames
+}
+
+// OtherCSVs returns the CSVs installed by the install plan besides the provided one.
+// OLM may resolve the upgrades of several operators of a namespace into one install plan
+// so approving it for one operator upgrades the others as well.
+func (u PendingUpgrade) OtherCSVs(csv string) []string {
+	others := []string{}
+	for _, name := range u.CSVs() {
+		if name != csv {
+			others = append(others, name)
+		}
+	}
+	return others
+}
+
+// ListPendingUpgrades returns the unapproved install plans of the subscriptions in the namespaces.
+// The subscriptions rejected by the filter are skipped. A nil filter accepts every subscription.
+func ListPendingUpgrades(
+	ctx context.Context,
+	k *kubernetes.Kubernetes,
+	namespaces []string,
+	filter func(s olmv1alpha1.Subscription) bool,
+) ([]PendingUpgrade, error) {
+	pending := []PendingUpgrade{}
+	for _, ns := range namespaces {
+		subs, err := k.ListSubscriptions(ctx, ns)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not list subscriptions in %s namespace", ns))
+		}
+		for _, s := range subs.Items {
+			if filter != nil && !filter(s) {
+				continue
+			}
+			if s.Status.Install == nil || s.Status.Install.Name == "" {
+				continue
+			}
+			ip, err := k.GetInstallPlan(ctx, ns, s.Status.Install.Name)
+			if err != nil {
+				return nil, errors.Join(err, fmt.Errorf("could not get install plan of %s", s.Name))
+			}
+			if ip.Spec.Approved {
+				continue
+			}
+			pending = append(pending, PendingUpgrade{Subscription: s, InstallPlan: ip})
+		}
+	}
+
+	return pending, nil
+}
+
+// ApproveUpgrade approves the pending install plan of the operator.
+// The user is asked with the message unless assumeYes is true.
+// It returns false if the user declines the install plan.
+func ApproveUpgrade(ctx context.Context, k *kubernetes.Kubernetes, u PendingUpgrade, message string, assumeYes bool) (bool, error) {
+	if !assumeYes {
+		confirm := false
+		if err := survey.AskOne(&survey.Confirm{Message: message}, &confirm); err != nil {
+			return false, err
+		}
+		if !confirm {
+			return false, nil
+		}
+	}
+
+	if err := k.UpgradeOperator(ctx, u.Subscription.Namespace, u.Subscription.Name); err != nil {
+		return false, errors.Join(err, fmt.Errorf("could not approve install plan %s", u.InstallPlan.Name))
+	}
+	return true, nil
+}
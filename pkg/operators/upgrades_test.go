@@ -0,0 +1,70 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingUpgradeOtherCSVs(t *testing.T) {
+	t.Parallel()
+
+	plan := func(csvs ...string) *olmv1alpha1.InstallPlan {
+		return &olmv1alpha1.InstallPlan{Spec: olmv1alpha1.InstallPlanSpec{ClusterServiceVersionNames: csvs}}
+	}
+
+	tests := []struct {
+		name string
+		plan *olmv1alpha1.InstallPlan
+		csv  string
+		want []string
+	}{
+		{name: "no install plan", plan: nil, csv: "pxc.v1.14.0", want: []string{}},
+		{name: "single csv", plan: plan("pxc.v1.14.0"), csv: "pxc.v1.14.0", want: []string{}},
+		{
+			name: "shared install plan",
+			plan: plan("pxc.v1.14.0", "psmdb.v1.16.0", "pg.v2.3.1"),
+			csv:  "psmdb.v1.16.0",
+			want: []string{"pxc.v1.14.0", "pg.v2.3.1"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, PendingUpgrade{InstallPlan: tt.plan}.OtherCSVs(tt.csv))
+		})
+	}
+}
+
+func TestPendingResponseString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "There are no pending install plans", PendingResponse{}.String())
+
+	out := PendingResponse{InstallPlans: []PendingInstallPlan{{
+		Namespace:   "dev",
+		Operator:    "percona-xtradb-cluster-operator",
+		InstallPlan: "install-abcde",
+		CSVs:        []string{"pxc.v1.14.0", "psmdb.v1.16.0"},
+		Approved:    true,
+	}}}.String()
+	assert.Contains(t, out, "install-abcde")
+	assert.Contains(t, out, "pxc.v1.14.0,psmdb.v1.16.0")
+}
@@ -0,0 +1,418 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preflight checks that a cluster is ready for Everest installation.
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	goversion "github.com/hashicorp/go-version"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+const (
+	// StatusPass means the check has passed.
+	StatusPass = "pass"
+	// StatusWarn means the installation can proceed but may not work as expected.
+	StatusWarn = "warn"
+	// StatusFail means the installation cannot proceed.
+	StatusFail = "fail"
+
+	// minKubernetesVersion is the oldest supported Kubernetes version.
+	minKubernetesVersion = "1.24"
+	// untestedKubernetesVersion is the first Kubernetes version Everest is not tested with.
+	untestedKubernetesVersion = "1.30"
+	// minCPU and minMemory are the resources recommended for Everest and a small database cluster.
+	minCPU    = "4"
+	minMemory = "8Gi"
+)
+
+// ErrChecksFailed appears when at least one of the checks has failed.
+var ErrChecksFailed = errors.New("preflight checks failed")
+
+// Preflight implements the main logic for the preflight command.
+type Preflight struct {
+	config Config
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// Config stores configuration for the preflight command.
+	Config struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// ReservedNamespaces are the namespaces reserved for Everest internals.
+		ReservedNamespaces []ReservedNamespace `mapstructure:"-"`
+		// UseExistingOLM expects an OLM installation in the cluster.
+		UseExistingOLM bool `mapstructure:"use-existing-olm"`
+		// OLMNamespace is the namespace OLM is installed into.
+		OLMNamespace string `mapstructure:"olm-namespace"`
+	}
+
+	// ReservedNamespace is a namespace reserved for Everest internals.
+	// The namespace is considered used by Everest if the subscription
+	// or the deployment exists in it.
+	ReservedNamespace struct {
+		Name         string
+		Subscription string
+		Deployment   string
+	}
+
+	// Check is the result of a single check.
+	Check struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		// Hint explains how to fix the problem if the check has not passed.
+		Hint string `json:"hint,omitempty"`
+	}
+
+	// Response is a response from the preflight command.
+	Response struct {
+		Checks []Check `json:"checks"`
+	}
+)
+
+func (r Response) String() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "STATUS\tCHECK\tMESSAGE")
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.ToUpper(c.Status), c.Name, c.Message)
+		if c.Hint != "" {
+			fmt.Fprintf(w, "\t\t  hint: %s\n", c.Hint)
+		}
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Failed returns true if at least one of the checks has failed.
+func (r Response) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPreflight returns a new Preflight struct.
+func NewPreflight(c Config, l *zap.SugaredLogger) (*Preflight, error) {
+	cli := &Preflight{
+		config: c,
+		l:      l.With("component", "preflight"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	k.SetOLMNamespace(c.OLMNamespace)
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the checks. Failed checks are reported in the response, not as an error.
+func (p *Preflight) Run(ctx context.Context) (*Response, error) {
+	checks := []func(ctx context.Context) ([]Check, error){
+		p.checkServerVersion,
+		p.checkStorageClass,
+		p.checkResources,
+		p.checkPermissions,
+		p.checkOLM,
+		p.checkReservedNamespaces,
+	}
+
+	res := &Response{Checks: []Check{}}
+	for _, check := range checks {
+		c, err := check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.Checks = append(res.Checks, c...)
+	}
+
+	return res, nil
+}
+
+func (p *Preflight) checkServerVersion(_ context.Context) ([]Check, error) {
+	info, err := p.kubeClient.GetServerVersion()
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get Kubernetes version"))
+	}
+
+	return []Check{kubernetesVersionCheck(info.GitVersion)}, nil
+}
+
+// kubernetesVersionCheck checks the version against the supported range.
+func kubernetesVersionCheck(gitVersion string) Check {
+	c := Check{Name: "kubernetes-version"}
+	v, err := goversion.NewVersion(gitVersion)
+	if err != nil {
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("Kubernetes version %q cannot be parsed", gitVersion)
+		return c
+	}
+
+	core := v.Core()
+	switch {
+	case core.LessThan(goversion.Must(goversion.NewVersion(minKubernetesVersion))):
+		c.Status = StatusFail
+		c.Message = fmt.Sprintf("Kubernetes %s is not supported", core)
+		c.Hint = fmt.Sprintf("Upgrade the cluster to Kubernetes %s or newer", minKubernetesVersion)
+	case !core.LessThan(goversion.Must(goversion.NewVersion(untestedKubernetesVersion))):
+		c.Status = StatusWarn
+		c.Message = fmt.Sprintf("Kubernetes %s has not been tested with Everest", core)
+		c.Hint = fmt.Sprintf("Use Kubernetes older than %s if you run into problems", untestedKubernetesVersion)
+	default:
+		c.Status = StatusPass
+		c.Message = fmt.Sprintf("Kubernetes %s is supported", core)
+	}
+	return c
+}
+
+func (p *Preflight) checkStorageClass(ctx context.Context) ([]Check, error) {
+	c := Check{Name: "storage-class"}
+	name, err := p.kubeClient.GetDefaultStorageClassName(ctx)
+	if err != nil {
+		c.Status = StatusFail
+		c.Message = "No storage class available"
+		c.Hint = "Install a storage provisioner and create a default storage class"
+		return []Check{c}, nil //nolint:nilerr
+	}
+
+	storageClasses, err := p.kubeClient.GetStorageClasses(ctx)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not list storage classes"))
+	}
+	for _, sc := range storageClasses.Items {
+		if sc.Name == name && kubernetes.IsDefaultStorageClass(sc) {
+			c.Status = StatusPass
+			c.Message = fmt.Sprintf("Default storage class is %s", name)
+			return []Check{c}, nil
+		}
+	}
+
+	c.Status = StatusWarn
+	c.Message = fmt.Sprintf("No storage class is marked as default. Database clusters use %s", name)
+	c.Hint = "Mark a storage class as default with the storageclass.kubernetes.io/is-default-class=true annotation"
+	return []Check{c}, nil
+}
+
+func (p *Preflight) checkResources(ctx context.Context) ([]Check, error) {
+	nodes, err := p.kubeClient.GetWorkerNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Check{resourcesCheck(nodes)}, nil
+}
+
+// resourcesCheck checks the allocatable resources of the worker nodes.
+func resourcesCheck(nodes []corev1.Node) Check {
+	c := Check{Name: "resources"}
+	if len(nodes) == 0 {
+		c.Status = StatusFail
+		c.Message = "No schedulable worker nodes found"
+		c.Hint = "Add worker nodes or remove the NoSchedule taints from the existing ones"
+		return c
+	}
+
+	cpu := resource.Quantity{}
+	memory := resource.Quantity{}
+	for _, node := range nodes {
+		cpu.Add(*node.Status.Allocatable.Cpu())
+		memory.Add(*node.Status.Allocatable.Memory())
+	}
+
+	c.Message = fmt.Sprintf("%d worker nodes with %s CPU and %s memory allocatable",
+		len(nodes), cpu.String(), memory.String())
+	if cpu.Cmp(resource.MustParse(minCPU)) < 0 || memory.Cmp(resource.MustParse(minMemory)) < 0 {
+		c.Status = StatusWarn
+		c.Hint = fmt.Sprintf("At least %s CPU and %s memory are recommended to run Everest and a database cluster",
+			minCPU, minMemory)
+		return c
+	}
+	c.Status = StatusPass
+	return c
+}
+
+// permission is a permission required to install Everest.
+type permission struct {
+	verb     string
+	group    string
+	resource string
+}
+
+func (p *Preflight) checkPermissions(ctx context.Context) ([]Check, error) {
+	required := []permission{
+		{"create", "", "namespaces"},
+		{"create", "apiextensions.k8s.io", "customresourcedefinitions"},
+		{"create", "rbac.authorization.k8s.io", "clusterroles"},
+		{"create", "rbac.authorization.k8s.io", "clusterrolebindings"},
+		{"create", "apps", "deployments"},
+		{"create", "operators.coreos.com", "subscriptions"},
+		{"update", "operators.coreos.com", "installplans"},
+		{"delete", "", "pods"},
+	}
+
+	missing := []string{}
+	for _, perm := range required {
+		allowed, err := p.kubeClient.CanI(ctx, perm.verb, perm.group, perm.resource, "")
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not check permissions"))
+		}
+		if !allowed {
+			missing = append(missing, perm.verb+" "+perm.resource)
+		}
+	}
+
+	c := Check{Name: "permissions"}
+	if len(missing) != 0 {
+		c.Status = StatusFail
+		c.Message = "Missing permissions to " + strings.Join(missing, ", ")
+		c.Hint = "Run the installation as a user bound to the cluster-admin cluster role"
+		return []Check{c}, nil
+	}
+	c.Status = StatusPass
+	c.Message = "The current user has the required permissions"
+	return []Check{c}, nil
+}
+
+func (p *Preflight) checkOLM(ctx context.Context) ([]Check, error) {
+	if p.config.UseExistingOLM {
+		return p.checkExistingOLM(ctx)
+	}
+
+	foreign, err := p.kubeClient.IsForeignOLMInstalled(ctx)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not check OLM installation"))
+	}
+
+	c := Check{Name: "olm"}
+	if foreign {
+		c.Status = StatusFail
+		c.Message = "OLM is already installed outside of " + p.kubeClient.GetOLMNamespace() + " namespace"
+		c.Hint = "Run the installation with --use-existing-olm to use the existing OLM"
+		return []Check{c}, nil
+	}
+	c.Status = StatusPass
+	c.Message = "No conflicting OLM installation found"
+	return []Check{c}, nil
+}
+
+func (p *Preflight) checkExistingOLM(ctx context.Context) ([]Check, error) {
+	c := Check{Name: "olm"}
+	ns, err := p.kubeClient.FindOLMNamespace(ctx)
+	if errors.Is(err, kubernetes.ErrOLMNotFound) {
+		c.Status = StatusFail
+		c.Message = "No existing OLM installation found"
+		c.Hint = "Install OLM or run the installation without --use-existing-olm"
+		return []Check{c}, nil
+	}
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not check OLM installation"))
+	}
+	c.Status = StatusPass
+	c.Message = "Found OLM with the global catalog namespace " + ns
+	return []Check{c}, nil
+}
+
+func (p *Preflight) checkReservedNamespaces(ctx context.Context) ([]Check, error) {
+	checks := make([]Check, 0, len(p.config.ReservedNamespaces))
+	for _, ns := range p.config.ReservedNamespaces {
+		c, err := p.checkReservedNamespace(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+func (p *Preflight) checkReservedNamespace(ctx context.Context, ns ReservedNamespace) (Check, error) {
+	c := Check{Name: "namespace " + ns.Name}
+	_, err := p.kubeClient.GetNamespace(ctx, ns.Name)
+	if err != nil && k8serrors.IsNotFound(err) {
+		c.Status = StatusPass
+		c.Message = "Namespace does not exist yet"
+		return c, nil
+	}
+	if err != nil {
+		return c, errors.Join(err, fmt.Errorf("could not get %s namespace", ns.Name))
+	}
+
+	if p.usedByEverest(ctx, ns) {
+		c.Status = StatusPass
+		c.Message = "Namespace is used by Everest"
+		return c, nil
+	}
+
+	deployments, err := p.kubeClient.ListDeployments(ctx, ns.Name)
+	if err != nil {
+		return c, errors.Join(err, fmt.Errorf("could not list deployments in %s namespace", ns.Name))
+	}
+	if len(deployments.Items) != 0 {
+		names := make([]string, 0, len(deployments.Items))
+		for _, d := range deployments.Items {
+			names = append(names, d.Name)
+		}
+		c.Status = StatusFail
+		c.Message = "Namespace is occupied by deployments not managed by Everest: " + strings.Join(names, ", ")
+		c.Hint = fmt.Sprintf("Move the workloads out of %s namespace and delete it", ns.Name)
+		return c, nil
+	}
+
+	c.Status = StatusPass
+	c.Message = "Namespace exists and is empty"
+	return c, nil
+}
+
+// usedByEverest returns true if the resources identifying the namespace as used by Everest exist.
+func (p *Preflight) usedByEverest(ctx context.Context, ns ReservedNamespace) bool {
+	if ns.Deployment != "" {
+		if _, err := p.kubeClient.GetDeployment(ctx, ns.Deployment, ns.Name); err == nil {
+			return true
+		}
+	}
+	if ns.Subscription != "" {
+		// The subscriptions cannot be listed before OLM is installed.
+		subs, err := p.kubeClient.ListSubscriptions(ctx, ns.Name)
+		if err != nil {
+			return false
+		}
+		for _, s := range subs.Items {
+			if s.Name == ns.Subscription {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestKubernetesVersionCheck(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		version string
+		status  string
+	}
+	tcases := []tcase{
+		{"v1.23.17", StatusFail},
+		{"v1.24.0", StatusPass},
+		{"v1.27.8-eks-8cb36c9", StatusPass},
+		{"v1.29.1+k3s2", StatusPass},
+		{"v1.30.0", StatusWarn},
+		{"unknown", StatusWarn},
+	}
+
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.status, kubernetesVersionCheck(tc.version).Status)
+		})
+	}
+}
+
+func TestResourcesCheck(t *testing.T) {
+	t.Parallel()
+
+	node := func(cpu, memory string) corev1.Node {
+		return corev1.Node{Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}}}
+	}
+
+	assert.Equal(t, StatusFail, resourcesCheck(nil).Status)
+	assert.Equal(t, StatusWarn, resourcesCheck([]corev1.Node{node("2", "16Gi")}).Status)
+	assert.Equal(t, StatusWarn, resourcesCheck([]corev1.Node{node("8", "4Gi")}).Status)
+
+	c := resourcesCheck([]corev1.Node{node("2", "4Gi"), node("2500m", "4Gi")})
+	assert.Equal(t, StatusPass, c.Status)
+	assert.Equal(t, "2 worker nodes with 4500m CPU and 8Gi memory allocatable", c.Message)
+}
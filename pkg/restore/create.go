@@ -0,0 +1,352 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/db"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// restoreNameTimeFormat is the format of the time suffix of the restore names.
+const restoreNameTimeFormat = "20060102150405"
+
+// ownerEngineTypes maps the kinds of the upstream backups owning the scheduled backups
+// to the engine types.
+var ownerEngineTypes = map[string]everestv1alpha1.EngineType{ //nolint:gochecknoglobals
+	"PerconaXtraDBClusterBackup": everestv1alpha1.DatabaseEnginePXC,
+	"PerconaServerMongoDBBackup": everestv1alpha1.DatabaseEnginePSMDB,
+	"PerconaPGBackup":            everestv1alpha1.DatabaseEnginePostgresql,
+}
+
+// pitrTypes maps the engine types to the supported types of point-in-time recovery.
+var pitrTypes = map[everestv1alpha1.EngineType][]everestv1alpha1.PITRType{ //nolint:gochecknoglobals
+	everestv1alpha1.DatabaseEnginePXC:        {everestv1alpha1.PITRTypeDate, everestv1alpha1.PITRTypeLatest},
+	everestv1alpha1.DatabaseEnginePSMDB:      {everestv1alpha1.PITRTypeDate, everestv1alpha1.PITRTypeLatest},
+	everestv1alpha1.DatabaseEnginePostgresql: {everestv1alpha1.PITRTypeDate},
+}
+
+// Create implements the main logic for the restore create command.
+type Create struct {
+	config CreateConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// CreateConfig stores configuration for the restore create command.
+type CreateConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// Namespace is the namespace of the backup.
+	Namespace string `mapstructure:"namespace"`
+	// Backup is the name of the backup to restore.
+	Backup string `mapstructure:"backup"`
+	// NewCluster is the name of a new database cluster the backup is restored into.
+	// The backup is restored into the cluster it was taken from if empty.
+	NewCluster string `mapstructure:"new-cluster"`
+	// StorageSize is the storage size of the new database cluster.
+	// It defaults to the storage size of the cluster the backup was taken from.
+	StorageSize string `mapstructure:"storage-size"`
+
+	// PITRType is the type of the point-in-time recovery, date or latest.
+	// The backup is restored as is if empty.
+	PITRType string `mapstructure:"pitr-type"`
+	// PITRDate is the UTC date to recover to when PITRType is date.
+	PITRDate string `mapstructure:"pitr-date"`
+
+	// Wait follows the restore until it succeeds or fails.
+	Wait bool `mapstructure:"wait"`
+	// WaitTimeout is the time to wait for the restore to complete.
+	WaitTimeout time.Duration `mapstructure:"wait-timeout"`
+}
+
+// NewCreate returns a new Create struct.
+func NewCreate(c CreateConfig, l *zap.SugaredLogger) (*Create, error) {
+	cli := &Create{
+		config: c,
+		l:      l.With("component", "restore/create"),
+	}
+	if cli.config.PITRType == "" && cli.config.PITRDate != "" {
+		cli.config.PITRType = string(everestv1alpha1.PITRTypeDate)
+	}
+	if err := cli.config.validate(); err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// validate validates the config without contacting the cluster.
+func (c CreateConfig) validate() error {
+	if c.Namespace == "" {
+		return errors.New("namespace is required")
+	}
+	if c.Backup == "" {
+		return errors.New("backup is required")
+	}
+	if c.NewCluster != "" {
+		if err := db.ValidateName(c.NewCluster); err != nil {
+			return err
+		}
+	}
+	if c.StorageSize != "" {
+		if c.NewCluster == "" {
+			return errors.New("storage size is only allowed with a new cluster")
+		}
+		if _, err := resource.ParseQuantity(c.StorageSize); err != nil {
+			return errors.Join(err, fmt.Errorf("invalid storage-size %q", c.StorageSize))
+		}
+	}
+
+	switch everestv1alpha1.PITRType(c.PITRType) {
+	case "":
+	case everestv1alpha1.PITRTypeLatest:
+		if c.PITRDate != "" {
+			return errors.New("point-in-time recovery date is not allowed with latest type")
+		}
+	case everestv1alpha1.PITRTypeDate:
+		if c.PITRDate == "" {
+			return errors.New("point-in-time recovery date is required with date type")
+		}
+		if _, err := time.Parse(everestv1alpha1.DateFormat, c.PITRDate); err != nil {
+			return errors.Join(err, fmt.Errorf("invalid point-in-time recovery date %q. The expected format is %s",
+				c.PITRDate, everestv1alpha1.DateFormat))
+		}
+	default:
+		return fmt.Errorf("invalid point-in-time recovery type %q. Allowed values are %s and %s",
+			c.PITRType, everestv1alpha1.PITRTypeDate, everestv1alpha1.PITRTypeLatest)
+	}
+	return nil
+}
+
+// Run runs the restore create command.
+func (c *Create) Run(ctx context.Context) (*RestoreResponse, error) {
+	backup, err := c.kubeClient.GetDatabaseClusterBackup(ctx, c.config.Namespace, c.config.Backup)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s backup", c.config.Backup))
+	}
+	// A backup is restored into a new cluster after the cluster it was taken from is deleted.
+	source, err := c.kubeClient.GetDatabaseCluster(ctx, c.config.Namespace, backup.Spec.DBClusterName)
+	if k8serrors.IsNotFound(err) && c.config.NewCluster != "" {
+		source, err = nil, nil
+	}
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get %s database cluster the backup was taken from", backup.Spec.DBClusterName))
+	}
+	spec, err := sourceSpec(backup, source)
+	if err != nil {
+		return nil, err
+	}
+	engineType := spec.Engine.Type
+	if backup.Status.State != succeededBackupStates[engineType] {
+		return nil, fmt.Errorf("backup %s is in %q state. Only succeeded backups can be restored", backup.Name, backup.Status.State)
+	}
+	pitr, err := c.pitr(spec, source, backup)
+	if err != nil {
+		return nil, err
+	}
+	dataSource := everestv1alpha1.DataSource{
+		DBClusterBackupName: backup.Name,
+		PITR:                pitr,
+	}
+
+	var restore *everestv1alpha1.DatabaseClusterRestore
+	if c.config.NewCluster == "" {
+		restore = &everestv1alpha1.DatabaseClusterRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", backup.Spec.DBClusterName, time.Now().UTC().Format(restoreNameTimeFormat)),
+				Namespace: c.config.Namespace,
+			},
+			Spec: everestv1alpha1.DatabaseClusterRestoreSpec{
+				DBClusterName: backup.Spec.DBClusterName,
+				DataSource:    dataSource,
+			},
+		}
+		c.l.Infof("Restoring %s backup into %s database cluster", backup.Name, backup.Spec.DBClusterName)
+		err := c.kubeClient.CreateRestore(restore)
+		if k8serrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("restore %s already exists in %s namespace", restore.Name, restore.Namespace)
+		}
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not create %s restore", restore.Name))
+		}
+	} else {
+		// The restore of an earlier cluster with the same name would be taken for the new one.
+		_, err := c.kubeClient.GetDatabaseClusterRestore(ctx, c.config.Namespace, c.config.NewCluster)
+		if err == nil {
+			return nil, fmt.Errorf("restore %s already exists in %s namespace. Delete it or choose another name for the new cluster",
+				c.config.NewCluster, c.config.Namespace)
+		}
+		if !k8serrors.IsNotFound(err) {
+			return nil, errors.Join(err, fmt.Errorf("could not get %s restore", c.config.NewCluster))
+		}
+
+		if c.config.StorageSize != "" {
+			spec.Engine.Storage.Size = resource.MustParse(c.config.StorageSize)
+		}
+		if spec.Engine.Storage.Size.IsZero() {
+			return nil, fmt.Errorf("storage size of %s backup is unknown. Set it with --storage-size", backup.Name)
+		}
+		cluster := newClusterFrom(spec, c.config.Namespace, c.config.NewCluster, dataSource)
+		c.l.Infof("Restoring %s backup into new %s database cluster", backup.Name, cluster.Name)
+		err = c.kubeClient.CreateDatabaseCluster(cluster)
+		if k8serrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("database cluster %s already exists in %s namespace", cluster.Name, cluster.Namespace)
+		}
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not create %s database cluster", cluster.Name))
+		}
+		// The operator creates the restore named after the new cluster once the cluster is ready.
+		restore = &everestv1alpha1.DatabaseClusterRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+			Spec: everestv1alpha1.DatabaseClusterRestoreSpec{
+				DBClusterName: cluster.Name,
+				DataSource:    dataSource,
+			},
+		}
+	}
+
+	if c.config.Wait {
+		restore, err = waitForRestore(ctx, c.kubeClient, c.l, restore.Namespace, restore.Name, engineType, c.config.WaitTimeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &RestoreResponse{Restore: newRestore(restore)}, nil
+}
+
+// pitr returns the point-in-time recovery configuration of the restore.
+// Whether point-in-time recovery was enabled is only checked if the source cluster still exists.
+func (c *Create) pitr(
+	spec *everestv1alpha1.DatabaseClusterSpec,
+	source *everestv1alpha1.DatabaseCluster,
+	backup *everestv1alpha1.DatabaseClusterBackup,
+) (*everestv1alpha1.PITR, error) {
+	if c.config.PITRType == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	pitrType := everestv1alpha1.PITRType(c.config.PITRType)
+	if !slices.Contains(pitrTypes[spec.Engine.Type], pitrType) {
+		return nil, fmt.Errorf("point-in-time recovery of %s type is not supported by %s engine", pitrType, spec.Engine.Type)
+	}
+	if source != nil && !source.Spec.Backup.PITR.Enabled {
+		return nil, fmt.Errorf("point-in-time recovery is not enabled in %s database cluster", source.Name)
+	}
+
+	pitr := &everestv1alpha1.PITR{Type: pitrType}
+	if pitrType == everestv1alpha1.PITRTypeDate {
+		date, err := time.Parse(everestv1alpha1.DateFormat, c.config.PITRDate)
+		if err != nil {
+			return nil, err
+		}
+		if backup.Status.CompletedAt != nil && date.Before(backup.Status.CompletedAt.Time) {
+			return nil, fmt.Errorf("point-in-time recovery date must be after %s when %s backup has completed",
+				backup.Status.CompletedAt.UTC().Format(everestv1alpha1.DateFormat), backup.Name)
+		}
+		pitr.Date = &everestv1alpha1.RestoreDate{Time: metav1.NewTime(date)}
+	}
+	return pitr, nil
+}
+
+// sourceSpec returns the spec of the database cluster the backup was taken from.
+// The spec is built from the engine and storage recorded in the backup annotations
+// if the source cluster does not exist anymore. The operator picks the recommended
+// version if no version is recorded.
+func sourceSpec(
+	backup *everestv1alpha1.DatabaseClusterBackup,
+	source *everestv1alpha1.DatabaseCluster,
+) (*everestv1alpha1.DatabaseClusterSpec, error) {
+	if source != nil {
+		return source.Spec.DeepCopy(), nil
+	}
+
+	engineType := everestv1alpha1.EngineType(backup.Annotations[kubernetes.EngineTypeAnnotationKey])
+	if engineType == "" {
+		for _, o := range backup.OwnerReferences {
+			if t, ok := ownerEngineTypes[o.Kind]; ok {
+				engineType = t
+				break
+			}
+		}
+	}
+	if _, ok := pitrTypes[engineType]; !ok {
+		return nil, fmt.Errorf("engine of %s backup is unknown and %s database cluster it was taken from does not exist",
+			backup.Name, backup.Spec.DBClusterName)
+	}
+
+	spec := &everestv1alpha1.DatabaseClusterSpec{
+		Engine: everestv1alpha1.Engine{
+			Type:    engineType,
+			Version: backup.Annotations[kubernetes.EngineVersionAnnotationKey],
+		},
+	}
+	if size, ok := backup.Annotations[kubernetes.StorageSizeAnnotationKey]; ok {
+		q, err := resource.ParseQuantity(size)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("invalid storage size %q of %s backup", size, backup.Name))
+		}
+		spec.Engine.Storage.Size = q
+	}
+	return spec, nil
+}
+
+// newClusterFrom returns a new database cluster with the engine and proxy of the spec
+// bootstrapped from the data source.
+// The credentials are copied from the backup by the operator. The backups, monitoring
+// and external access are not copied.
+func newClusterFrom(
+	source *everestv1alpha1.DatabaseClusterSpec,
+	namespace, name string,
+	dataSource everestv1alpha1.DataSource,
+) *everestv1alpha1.DatabaseCluster {
+	spec := source.DeepCopy()
+	spec.Paused = false
+	spec.Engine.UserSecretsName = ""
+	spec.Proxy.Expose = everestv1alpha1.Expose{Type: everestv1alpha1.ExposeTypeInternal}
+	spec.Backup = everestv1alpha1.Backup{}
+	spec.Monitoring = nil
+	spec.DataSource = &dataSource
+
+	return &everestv1alpha1.DatabaseCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: *spec,
+	}
+}
@@ -0,0 +1,124 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// List implements the main logic for the restore list command.
+type List struct {
+	config ListConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// ListConfig stores configuration for the restore list command.
+	ListConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// Namespace limits the output to a single namespace.
+		// All the namespaces managed by Everest are listed if empty.
+		Namespace string `mapstructure:"namespace"`
+		// Cluster limits the output to the restores of a single database cluster.
+		Cluster string `mapstructure:"cluster"`
+	}
+
+	// ListResponse is a response from the restore list command.
+	ListResponse struct {
+		Restores []Restore `json:"restores"`
+	}
+)
+
+func (r ListResponse) String() string {
+	if len(r.Restores) == 0 {
+		return "There are no restores"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tCLUSTER\tBACKUP\tPOINT IN TIME\tSTATE\tCOMPLETED")
+	for _, rs := range r.Restores {
+		completed := ""
+		if rs.Completed != nil {
+			completed = rs.Completed.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", rs.Namespace, rs.Name, rs.Cluster, rs.Backup, rs.PITR, rs.State, completed)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewList returns a new List struct.
+func NewList(c ListConfig, l *zap.SugaredLogger) (*List, error) {
+	cli := &List{
+		config: c,
+		l:      l.With("component", "restore/list"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run runs the restore list command.
+func (l *List) Run(ctx context.Context) (*ListResponse, error) {
+	namespaces := []string{l.config.Namespace}
+	if l.config.Namespace == "" {
+		ns, err := l.kubeClient.GetDBNamespaces(ctx, l.config.SystemNamespace)
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+		}
+		sort.Strings(ns)
+		namespaces = ns
+	}
+
+	res := &ListResponse{Restores: []Restore{}}
+	for _, ns := range namespaces {
+		restores, err := l.kubeClient.ListDatabaseClusterRestores(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not list restores in %s namespace", ns))
+		}
+		sort.Slice(restores.Items, func(i, j int) bool { return restores.Items[i].Name < restores.Items[j].Name })
+		for i := range restores.Items {
+			if l.config.Cluster != "" && restores.Items[i].Spec.DBClusterName != l.config.Cluster {
+				continue
+			}
+			res.Restores = append(res.Restores, newRestore(&restores.Items[i]))
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,163 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package restore holds the logic of the restore commands managing database cluster restores.
+package restore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	pgv2 "github.com/percona/percona-postgresql-operator/pkg/apis/pgv2.percona.com/v2"
+	psmdbv1 "github.com/percona/percona-server-mongodb-operator/pkg/apis/psmdb/v1"
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// pollInterval is the interval between the checks of the restore state while waiting.
+const pollInterval = 5 * time.Second
+
+// succeededBackupStates maps the engine types to the state of their succeeded backups.
+var succeededBackupStates = map[everestv1alpha1.EngineType]everestv1alpha1.BackupState{ //nolint:gochecknoglobals
+	everestv1alpha1.DatabaseEnginePXC:        everestv1alpha1.BackupState(pxcv1.BackupSucceeded),
+	everestv1alpha1.DatabaseEnginePSMDB:      everestv1alpha1.BackupState(psmdbv1.BackupStateReady),
+	everestv1alpha1.DatabaseEnginePostgresql: everestv1alpha1.BackupState(pgv2.BackupSucceeded),
+}
+
+// succeededRestoreStates maps the engine types to the state of their succeeded restores.
+var succeededRestoreStates = map[everestv1alpha1.EngineType]everestv1alpha1.RestoreState{ //nolint:gochecknoglobals
+	everestv1alpha1.DatabaseEnginePXC:        everestv1alpha1.RestoreState(pxcv1.RestoreSucceeded),
+	everestv1alpha1.DatabaseEnginePSMDB:      everestv1alpha1.RestoreState(psmdbv1.RestoreStateReady),
+	everestv1alpha1.DatabaseEnginePostgresql: everestv1alpha1.RestoreState(pgv2.RestoreSucceeded),
+}
+
+type (
+	// Restore describes a restore of a database cluster.
+	Restore struct {
+		Name      string     `json:"name"`
+		Namespace string     `json:"namespace"`
+		Cluster   string     `json:"cluster"`
+		Backup    string     `json:"backup"`
+		PITR      string     `json:"pitr,omitempty"`
+		State     string     `json:"state"`
+		Completed *time.Time `json:"completed,omitempty"`
+		Message   string     `json:"message,omitempty"`
+	}
+
+	// RestoreResponse is a response from the restore commands describing a single restore.
+	RestoreResponse struct {
+		Restore Restore `json:"restore"`
+	}
+)
+
+func (r RestoreResponse) String() string {
+	rs := r.Restore
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", rs.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", rs.Namespace)
+	fmt.Fprintf(w, "Cluster:\t%s\n", rs.Cluster)
+	fmt.Fprintf(w, "Backup:\t%s\n", rs.Backup)
+	if rs.PITR != "" {
+		fmt.Fprintf(w, "Point in time:\t%s\n", rs.PITR)
+	}
+	fmt.Fprintf(w, "State:\t%s\n", rs.State)
+	if rs.Completed != nil {
+		fmt.Fprintf(w, "Completed:\t%s\n", rs.Completed.Format(time.RFC3339))
+	}
+	if rs.Message != "" {
+		fmt.Fprintf(w, "Message:\t%s\n", rs.Message)
+	}
+	w.Flush() //nolint:errcheck,gosec
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// newRestore returns the description of the restore.
+func newRestore(restore *everestv1alpha1.DatabaseClusterRestore) Restore {
+	r := Restore{
+		Name:      restore.Name,
+		Namespace: restore.Namespace,
+		Cluster:   restore.Spec.DBClusterName,
+		Backup:    restore.Spec.DataSource.DBClusterBackupName,
+		PITR:      pitrString(restore.Spec.DataSource.PITR),
+		State:     string(restore.Status.State),
+		Message:   restore.Status.Message,
+	}
+	if restore.Status.CompletedAt != nil {
+		r.Completed = &restore.Status.CompletedAt.Time
+	}
+	return r
+}
+
+// pitrString returns the point in time the restore recovers to.
+func pitrString(pitr *everestv1alpha1.PITR) string {
+	switch {
+	case pitr == nil:
+		return ""
+	case pitr.Type == everestv1alpha1.PITRTypeLatest:
+		return string(everestv1alpha1.PITRTypeLatest)
+	case pitr.Date != nil:
+		return pitr.Date.Format(everestv1alpha1.DateFormat)
+	}
+	return ""
+}
+
+// waitForRestore follows the restore until it succeeds or fails.
+// The restore may not exist yet since the operator creates the restores
+// of the new clusters once they are ready.
+func waitForRestore(
+	ctx context.Context,
+	k *kubernetes.Kubernetes,
+	l *zap.SugaredLogger,
+	namespace, name string,
+	engineType everestv1alpha1.EngineType,
+	timeout time.Duration,
+) (*everestv1alpha1.DatabaseClusterRestore, error) {
+	l.Infof("Waiting for %s restore to complete", name)
+	var restore *everestv1alpha1.DatabaseClusterRestore
+	state := ""
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, timeout, false, func(ctx context.Context) (bool, error) {
+		var err error
+		restore, err = k.GetDatabaseClusterRestore(ctx, namespace, name)
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if s := string(restore.Status.State); s != state {
+			state = s
+			l.Infof("Restore %s is %s", name, state)
+		}
+		return restore.IsComplete(engineType), nil
+	})
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("restore %s has not completed", name))
+	}
+	if restore.Status.State != succeededRestoreStates[engineType] {
+		return nil, fmt.Errorf("restore %s has failed with %s state: %s", name, restore.Status.State, restore.Status.Message)
+	}
+	return restore, nil
+}
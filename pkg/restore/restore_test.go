@@ -0,0 +1,185 @@
+package restore
+
+import (
+	"testing"
+	"time"
+
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+func TestCreateConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	type tcase struct {
+		name    string
+		config  CreateConfig
+		wantErr bool
+	}
+	tcases := []tcase{
+		{name: "backup", config: CreateConfig{Namespace: "dev", Backup: "b1"}},
+		{name: "new cluster", config: CreateConfig{Namespace: "dev", Backup: "b1", NewCluster: "mysql-2"}},
+		{name: "new cluster storage", config: CreateConfig{Namespace: "dev", Backup: "b1", NewCluster: "mysql-2", StorageSize: "10Gi"}},
+		{name: "latest", config: CreateConfig{Namespace: "dev", Backup: "b1", PITRType: "latest"}},
+		{name: "date", config: CreateConfig{Namespace: "dev", Backup: "b1", PITRType: "date", PITRDate: "2024-03-01T12:00:00Z"}},
+		{name: "no backup", config: CreateConfig{Namespace: "dev"}, wantErr: true},
+		{name: "invalid new cluster", config: CreateConfig{Namespace: "dev", Backup: "b1", NewCluster: "MySQL"}, wantErr: true},
+		{name: "storage without new cluster", config: CreateConfig{Namespace: "dev", Backup: "b1", StorageSize: "10Gi"}, wantErr: true},
+		{name: "invalid storage", config: CreateConfig{Namespace: "dev", Backup: "b1", NewCluster: "mysql-2", StorageSize: "ten"}, wantErr: true},
+		{name: "invalid type", config: CreateConfig{Namespace: "dev", Backup: "b1", PITRType: "time"}, wantErr: true},
+		{name: "date without date", config: CreateConfig{Namespace: "dev", Backup: "b1", PITRType: "date"}, wantErr: true},
+		{name: "invalid date", config: CreateConfig{Namespace: "dev", Backup: "b1", PITRType: "date", PITRDate: "2024-03-01"}, wantErr: true},
+		{name: "latest with date", config: CreateConfig{Namespace: "dev", Backup: "b1", PITRType: "latest", PITRDate: "2024-03-01T12:00:00Z"}, wantErr: true},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.config.validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPITR(t *testing.T) {
+	t.Parallel()
+
+	source := &everestv1alpha1.DatabaseCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-1"},
+		Spec: everestv1alpha1.DatabaseClusterSpec{
+			Engine: everestv1alpha1.Engine{Type: everestv1alpha1.DatabaseEnginePostgresql},
+		},
+	}
+	completed := metav1.NewTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	backup := &everestv1alpha1.DatabaseClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg-1-backup"},
+		Status:     everestv1alpha1.DatabaseClusterBackupStatus{CompletedAt: &completed},
+	}
+
+	c := &Create{config: CreateConfig{PITRType: "date", PITRDate: "2024-03-01T13:00:00Z"}}
+	_, err := c.pitr(&source.Spec, source, backup)
+	require.Error(t, err, "PITR is not enabled")
+
+	source.Spec.Backup.PITR.Enabled = true
+	pitr, err := c.pitr(&source.Spec, source, backup)
+	require.NoError(t, err)
+	assert.Equal(t, everestv1alpha1.PITRTypeDate, pitr.Type)
+	assert.Equal(t, "2024-03-01T13:00:00Z", pitrString(pitr))
+
+	c.config.PITRDate = "2024-03-01T11:00:00Z"
+	_, err = c.pitr(&source.Spec, source, backup)
+	require.Error(t, err, "date before the backup")
+
+	c.config = CreateConfig{PITRType: "latest"}
+	_, err = c.pitr(&source.Spec, source, backup)
+	require.Error(t, err, "latest is not supported by postgresql")
+
+	c.config = CreateConfig{PITRType: "date", PITRDate: "2024-03-01T13:00:00Z"}
+	source.Spec.Backup.PITR.Enabled = false
+	_, err = c.pitr(&source.Spec, nil, backup)
+	require.NoError(t, err, "the source cluster is deleted")
+
+	c.config = CreateConfig{}
+	pitr, err = c.pitr(&source.Spec, source, backup)
+	require.NoError(t, err)
+	assert.Nil(t, pitr)
+}
+
+func TestNewClusterFrom(t *testing.T) {
+	t.Parallel()
+
+	source := &everestv1alpha1.DatabaseCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql-1", Namespace: "dev"},
+		Spec: everestv1alpha1.DatabaseClusterSpec{
+			Engine: everestv1alpha1.Engine{Type: everestv1alpha1.DatabaseEnginePXC, Replicas: 3, UserSecretsName: "mysql-1-secrets"},
+			Proxy: everestv1alpha1.Proxy{
+				Type:   everestv1alpha1.ProxyTypeHAProxy,
+				Expose: everestv1alpha1.Expose{Type: everestv1alpha1.ExposeTypeExternal},
+			},
+			Backup: everestv1alpha1.Backup{Enabled: true},
+		},
+	}
+
+	db := newClusterFrom(&source.Spec, "dev", "mysql-2", everestv1alpha1.DataSource{DBClusterBackupName: "mysql-1-backup"})
+	assert.Equal(t, "mysql-2", db.Name)
+	assert.Equal(t, "dev", db.Namespace)
+	assert.Equal(t, int32(3), db.Spec.Engine.Replicas)
+	assert.Empty(t, db.Spec.Engine.UserSecretsName)
+	assert.Equal(t, everestv1alpha1.ExposeTypeInternal, db.Spec.Proxy.Expose.Type)
+	assert.False(t, db.Spec.Backup.Enabled)
+	require.NotNil(t, db.Spec.DataSource)
+	assert.Equal(t, "mysql-1-backup", db.Spec.DataSource.DBClusterBackupName)
+	assert.Equal(t, "mysql-1-secrets", source.Spec.Engine.UserSecretsName)
+}
+
+func TestSourceSpec(t *testing.T) {
+	t.Parallel()
+
+	source := &everestv1alpha1.DatabaseCluster{
+		Spec: everestv1alpha1.DatabaseClusterSpec{
+			Engine: everestv1alpha1.Engine{Type: everestv1alpha1.DatabaseEnginePXC, Replicas: 3},
+		},
+	}
+	type tcase struct {
+		name        string
+		annotations map[string]string
+		owners      []metav1.OwnerReference
+		source      *everestv1alpha1.DatabaseCluster
+		wantEngine  everestv1alpha1.EngineType
+		wantVersion string
+		wantSize    string
+		wantErr     bool
+	}
+	tcases := []tcase{
+		{name: "source", source: source, wantEngine: everestv1alpha1.DatabaseEnginePXC, wantSize: "0"},
+		{
+			name: "annotations",
+			annotations: map[string]string{
+				kubernetes.EngineTypeAnnotationKey:    "psmdb",
+				kubernetes.EngineVersionAnnotationKey: "6.0.9-7",
+				kubernetes.StorageSizeAnnotationKey:   "15Gi",
+			},
+			wantEngine:  everestv1alpha1.DatabaseEnginePSMDB,
+			wantVersion: "6.0.9-7",
+			wantSize:    "15Gi",
+		},
+		{
+			name:       "scheduled backup",
+			owners:     []metav1.OwnerReference{{Kind: "PerconaPGBackup", Name: "pg-1-backup"}},
+			wantEngine: everestv1alpha1.DatabaseEnginePostgresql,
+			wantSize:   "0",
+		},
+		{name: "unknown engine", wantErr: true},
+		{
+			name:        "invalid size",
+			annotations: map[string]string{kubernetes.EngineTypeAnnotationKey: "pxc", kubernetes.StorageSizeAnnotationKey: "ten"},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			backup := &everestv1alpha1.DatabaseClusterBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "b1", Annotations: tc.annotations, OwnerReferences: tc.owners},
+			}
+			spec, err := sourceSpec(backup, tc.source)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantEngine, spec.Engine.Type)
+			assert.Equal(t, tc.wantVersion, spec.Engine.Version)
+			assert.Equal(t, tc.wantSize, spec.Engine.Storage.Size.String())
+		})
+	}
+}
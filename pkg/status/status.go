@@ -0,0 +1,362 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package status reports the health of an Everest installation.
+package status
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+const (
+	// StatusHealthy means the component works as expected.
+	StatusHealthy = "healthy"
+	// StatusDegraded means the component is missing or not ready.
+	StatusDegraded = "degraded"
+
+	// catalogSourceReady is the state of a catalog source connected to its registry.
+	catalogSourceReady = "READY"
+	// unknownState is reported for the database clusters without a state.
+	unknownState = "unknown"
+)
+
+// Status implements the main logic for the status command.
+type Status struct {
+	config Config
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// Config stores configuration for the status command.
+	Config struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+	}
+
+	// Component is the health of a single Everest component.
+	Component struct {
+		Name      string `json:"name"`
+		Kind      string `json:"kind"`
+		Namespace string `json:"namespace"`
+		Status    string `json:"status"`
+		Message   string `json:"message"`
+	}
+
+	// Response is a response from the status command.
+	Response struct {
+		Components []Component `json:"components"`
+		// DatabaseClusters counts the database clusters by state.
+		DatabaseClusters map[string]int `json:"databaseClusters"`
+	}
+)
+
+func (r Response) String() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "STATUS\tKIND\tNAME\tNAMESPACE\tMESSAGE")
+	for _, c := range r.Components {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", strings.ToUpper(c.Status), c.Kind, c.Name, c.Namespace, c.Message)
+	}
+	w.Flush() //nolint:errcheck,gosec
+
+	if len(r.DatabaseClusters) == 0 {
+		b.WriteString("\nThere are no database clusters")
+		return b.String()
+	}
+	states := make([]string, 0, len(r.DatabaseClusters))
+	for state := range r.DatabaseClusters {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	counts := make([]string, 0, len(states))
+	for _, state := range states {
+		counts = append(counts, fmt.Sprintf("%s=%d", state, r.DatabaseClusters[state]))
+	}
+	b.WriteString("\nDatabase clusters: " + strings.Join(counts, ", "))
+	return b.String()
+}
+
+// Degraded returns true if at least one of the components is degraded.
+func (r Response) Degraded() bool {
+	for _, c := range r.Components {
+		if c.Status == StatusDegraded {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStatus returns a new Status struct.
+func NewStatus(c Config, l *zap.SugaredLogger) (*Status, error) {
+	cli := &Status{
+		config: c,
+		l:      l.With("component", "status"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run collects the health of the components.
+// Degraded components are reported in the response, not as an error.
+func (s *Status) Run(ctx context.Context) (*Response, error) {
+	dbNamespaces, err := s.kubeClient.GetDBNamespaces(ctx, s.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest. Make sure Everest is installed"))
+	}
+	olm, err := install.LoadOLMState(ctx, s.kubeClient, s.config.SystemNamespace)
+	if err != nil {
+		return nil, err
+	}
+	s.kubeClient.SetOLMNamespace(olm.Namespace)
+
+	res := &Response{Components: []Component{}}
+	olmComponents, err := s.olmComponents(ctx, olm)
+	if err != nil {
+		return nil, err
+	}
+	res.Components = append(res.Components, olmComponents...)
+
+	c, err := s.catalogComponent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res.Components = append(res.Components, c)
+
+	for _, ns := range dbNamespaces {
+		c, err := s.operatorComponents(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		res.Components = append(res.Components, c...)
+	}
+
+	for _, name := range []string{kubernetes.PerconaEverestDeploymentName, kubernetes.EverestOperatorDeploymentName} {
+		c, err := s.deploymentComponent(ctx, name, s.config.SystemNamespace)
+		if err != nil {
+			return nil, err
+		}
+		res.Components = append(res.Components, c)
+	}
+
+	if res.DatabaseClusters, err = s.countDatabaseClusters(ctx, dbNamespaces); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// olmComponents reports the health of the OLM deployments and the packageserver CSV.
+// The existing OLM installation is looked up since its deployments may run
+// in another namespace than the catalog source.
+func (s *Status) olmComponents(ctx context.Context, olm install.OLMState) ([]Component, error) {
+	namespace := olm.Namespace
+	if olm.Existing {
+		ns, err := s.kubeClient.FindOLMDeploymentsNamespace(ctx)
+		if errors.Is(err, kubernetes.ErrOLMNotFound) {
+			return []Component{{
+				Name:    "olm",
+				Kind:    "OperatorLifecycleManager",
+				Status:  StatusDegraded,
+				Message: "Existing OLM installation not found",
+			}}, nil
+		}
+		if err != nil {
+			return nil, errors.Join(err, errors.New("could not find the existing OLM installation"))
+		}
+		namespace = ns
+	}
+
+	components := []Component{}
+	for _, name := range kubernetes.OLMDeploymentNames() {
+		c, err := s.deploymentComponent(ctx, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+
+	c := Component{Name: "packageserver", Kind: "ClusterServiceVersion", Namespace: namespace}
+	csv, err := s.kubeClient.GetClusterServiceVersion(ctx, types.NamespacedName{Name: c.Name, Namespace: namespace})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, errors.Join(err, errors.New("could not get packageserver CSV"))
+	}
+	c.Status, c.Message = csvHealth(csv)
+	return append(components, c), nil
+}
+
+func (s *Status) catalogComponent(ctx context.Context) (Component, error) {
+	catalog, err := s.kubeClient.GetPerconaCatalog(ctx)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return Component{}, errors.Join(err, errors.New("could not get the percona catalog source"))
+	}
+	c := Component{Kind: olmv1alpha1.CatalogSourceKind, Namespace: s.kubeClient.GetOLMNamespace()}
+	if catalog == nil || err != nil {
+		c.Name = "everest-catalog"
+		c.Status = StatusDegraded
+		c.Message = "Catalog source not found"
+		return c, nil
+	}
+	c.Name = catalog.Name
+
+	state := ""
+	if catalog.Status.GRPCConnectionState != nil {
+		state = catalog.Status.GRPCConnectionState.LastObservedState
+	}
+	c.Status = StatusDegraded
+	if state == catalogSourceReady {
+		c.Status = StatusHealthy
+	}
+	if state == "" {
+		state = unknownState
+	}
+	c.Message = "Connection state is " + state
+	return c, nil
+}
+
+// operatorComponents reports the health of the operators subscribed to in the namespace.
+func (s *Status) operatorComponents(ctx context.Context, namespace string) ([]Component, error) {
+	subs, err := s.kubeClient.ListSubscriptions(ctx, namespace)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not list subscriptions in %s namespace", namespace))
+	}
+
+	components := make([]Component, 0, len(subs.Items))
+	for _, sub := range subs.Items {
+		c, err := s.operatorComponent(ctx, sub)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+	return components, nil
+}
+
+func (s *Status) operatorComponent(ctx context.Context, sub olmv1alpha1.Subscription) (Component, error) {
+	c := Component{Name: sub.Name, Kind: "Operator", Namespace: sub.Namespace, Status: StatusDegraded}
+	if sub.Status.InstalledCSV == "" {
+		c.Message = fmt.Sprintf("Subscription has no installed CSV. Subscription state is %q", sub.Status.State)
+		return c, nil
+	}
+
+	csv, err := s.kubeClient.GetClusterServiceVersion(ctx, types.NamespacedName{
+		Name:      sub.Status.InstalledCSV,
+		Namespace: sub.Namespace,
+	})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return c, errors.Join(err, fmt.Errorf("could not get %s CSV", sub.Status.InstalledCSV))
+	}
+	status, message := csvHealth(csv)
+	if status != StatusHealthy {
+		c.Message = fmt.Sprintf("CSV %s: %s", sub.Status.InstalledCSV, message)
+		return c, nil
+	}
+
+	for _, d := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		dc, err := s.deploymentComponent(ctx, d.Name, sub.Namespace)
+		if err != nil {
+			return c, err
+		}
+		if dc.Status != StatusHealthy {
+			c.Message = fmt.Sprintf("Deployment %s: %s", d.Name, dc.Message)
+			return c, nil
+		}
+	}
+
+	c.Status = StatusHealthy
+	c.Message = fmt.Sprintf("CSV %s: %s", sub.Status.InstalledCSV, message)
+	return c, nil
+}
+
+func (s *Status) deploymentComponent(ctx context.Context, name, namespace string) (Component, error) {
+	c := Component{Name: name, Kind: "Deployment", Namespace: namespace}
+	d, err := s.kubeClient.GetDeployment(ctx, name, namespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return c, errors.Join(err, fmt.Errorf("could not get %s deployment", name))
+	}
+	if err != nil {
+		c.Status = StatusDegraded
+		c.Message = "Deployment not found"
+		return c, nil
+	}
+	c.Status, c.Message = deploymentHealth(d)
+	return c, nil
+}
+
+func (s *Status) countDatabaseClusters(ctx context.Context, namespaces []string) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, ns := range namespaces {
+		clusters, err := s.kubeClient.ListDatabaseClusters(ctx, ns)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("could not list database clusters in %s namespace", ns))
+		}
+		for _, db := range clusters.Items {
+			state := string(db.Status.Status)
+			if state == "" {
+				state = unknownState
+			}
+			counts[state]++
+		}
+	}
+	return counts, nil
+}
+
+// csvHealth returns the status of the CSV and a message describing it.
+func csvHealth(csv *olmv1alpha1.ClusterServiceVersion) (string, string) {
+	if csv == nil || csv.Name == "" {
+		return StatusDegraded, "CSV not found"
+	}
+	if csv.Status.Phase != olmv1alpha1.CSVPhaseSucceeded {
+		return StatusDegraded, strings.TrimSpace(fmt.Sprintf("Phase is %s. %s", csv.Status.Phase, csv.Status.Message))
+	}
+	return StatusHealthy, fmt.Sprintf("Phase is %s", csv.Status.Phase)
+}
+
+// deploymentHealth returns the status of the deployment and a message describing it.
+func deploymentHealth(d *appsv1.Deployment) (string, string) {
+	desired := int32(1)
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+	message := fmt.Sprintf("%d/%d replicas available", d.Status.AvailableReplicas, desired)
+	if d.Status.AvailableReplicas < desired || d.Status.UpdatedReplicas < desired {
+		return StatusDegraded, message
+	}
+	return StatusHealthy, message
+}
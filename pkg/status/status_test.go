@@ -0,0 +1,72 @@
+package status
+
+import (
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentHealth(t *testing.T) {
+	t.Parallel()
+
+	deployment := func(replicas *int32, available, updated int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec:   appsv1.DeploymentSpec{Replicas: replicas},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: available, UpdatedReplicas: updated},
+		}
+	}
+	two := int32(2)
+
+	type tcase struct {
+		name   string
+		d      *appsv1.Deployment
+		status string
+	}
+	tcases := []tcase{
+		{"default replicas available", deployment(nil, 1, 1), StatusHealthy},
+		{"no replicas available", deployment(nil, 0, 0), StatusDegraded},
+		{"partially available", deployment(&two, 1, 2), StatusDegraded},
+		{"rolling out", deployment(&two, 2, 1), StatusDegraded},
+		{"all available", deployment(&two, 2, 2), StatusHealthy},
+	}
+
+	for _, tc := range tcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			status, _ := deploymentHealth(tc.d)
+			assert.Equal(t, tc.status, status)
+		})
+	}
+}
+
+func TestCSVHealth(t *testing.T) {
+	t.Parallel()
+
+	csv := func(phase olmv1alpha1.ClusterServiceVersionPhase) *olmv1alpha1.ClusterServiceVersion {
+		return &olmv1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "packageserver"},
+			Status:     olmv1alpha1.ClusterServiceVersionStatus{Phase: phase},
+		}
+	}
+
+	status, _ := csvHealth(csv(olmv1alpha1.CSVPhaseSucceeded))
+	assert.Equal(t, StatusHealthy, status)
+	status, _ = csvHealth(csv(olmv1alpha1.CSVPhaseInstalling))
+	assert.Equal(t, StatusDegraded, status)
+	status, message := csvHealth(&olmv1alpha1.ClusterServiceVersion{})
+	assert.Equal(t, StatusDegraded, status)
+	assert.Equal(t, "CSV not found", message)
+}
+
+func TestResponseDegraded(t *testing.T) {
+	t.Parallel()
+
+	r := Response{Components: []Component{{Status: StatusHealthy}, {Status: StatusHealthy}}}
+	assert.False(t, r.Degraded())
+	r.Components = append(r.Components, Component{Status: StatusDegraded})
+	assert.True(t, r.Degraded())
+}
@@ -0,0 +1,69 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"path"
+	"time"
+)
+
+// archive writes the files into a gzipped tarball under a single directory.
+type archive struct {
+	gz    *gzip.Writer
+	tw    *tar.Writer
+	dir   string
+	now   time.Time
+	files int
+}
+
+func newArchive(w io.Writer, dir string) *archive {
+	gz := gzip.NewWriter(w)
+	return &archive{
+		gz:  gz,
+		tw:  tar.NewWriter(gz),
+		dir: dir,
+		now: time.Now(),
+	}
+}
+
+// Add adds a file to the archive.
+func (a *archive) Add(name string, content []byte) error {
+	err := a.tw.WriteHeader(&tar.Header{
+		Name:    path.Join(a.dir, name),
+		Mode:    0o644, //nolint:gomnd
+		Size:    int64(len(content)),
+		ModTime: a.now,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := a.tw.Write(content); err != nil {
+		return err
+	}
+	a.files++
+	return nil
+}
+
+// Close flushes the archive. It does not close the underlying writer.
+func (a *archive) Close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
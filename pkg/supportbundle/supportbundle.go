@@ -0,0 +1,324 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package supportbundle collects the diagnostics of an Everest installation.
+package supportbundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// bundleDir is the directory in the archive holding the collected files.
+const bundleDir = "everest-support-bundle"
+
+// SupportBundle implements the main logic for the support-bundle command.
+type SupportBundle struct {
+	config Config
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+	archive    *archive
+	// errs are the errors of the collectors. They are stored in the bundle
+	// instead of failing the command so that the rest is still collected.
+	errs []string
+}
+
+type (
+	// Config stores configuration for the support-bundle command.
+	Config struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// Output is the path of the created archive.
+		// An existing file is not overwritten.
+		Output string `mapstructure:"output"`
+	}
+
+	// Response is a response from the support-bundle command.
+	Response struct {
+		Path  string `json:"path"`
+		Files int    `json:"files"`
+		// Errors lists what could not be collected.
+		Errors []string `json:"errors,omitempty"`
+	}
+
+	// nodeSummary describes a node without the details irrelevant for troubleshooting.
+	nodeSummary struct {
+		Name             string            `json:"name"`
+		Ready            bool              `json:"ready"`
+		Unschedulable    bool              `json:"unschedulable"`
+		KubeletVersion   string            `json:"kubeletVersion"`
+		OSImage          string            `json:"osImage"`
+		ContainerRuntime string            `json:"containerRuntime"`
+		Allocatable      map[string]string `json:"allocatable"`
+		Labels           map[string]string `json:"labels"`
+		Taints           []corev1.Taint    `json:"taints,omitempty"`
+	}
+)
+
+func (r Response) String() string {
+	msg := fmt.Sprintf("Support bundle with %d files has been written to %s", r.Files, r.Path)
+	if len(r.Errors) != 0 {
+		msg += fmt.Sprintf(". %d items could not be collected, see errors.txt in the bundle", len(r.Errors))
+	}
+	return msg
+}
+
+// NewSupportBundle returns a new SupportBundle struct.
+func NewSupportBundle(c Config, l *zap.SugaredLogger) (*SupportBundle, error) {
+	cli := &SupportBundle{
+		config: c,
+		l:      l.With("component", "support-bundle"),
+	}
+	if cli.config.Output == "" {
+		cli.config.Output = fmt.Sprintf("%s-%s.tar.gz", bundleDir, time.Now().UTC().Format("20060102-150405"))
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run collects the diagnostics into the archive.
+func (s *SupportBundle) Run(ctx context.Context) (*Response, error) {
+	// The bundle is created only readable by the user since it may still hold sensitive data.
+	f, err := os.OpenFile(s.config.Output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("%s already exists. Delete it or choose another path with --output", s.config.Output)
+	}
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not create %s", s.config.Output))
+	}
+	defer f.Close() //nolint:errcheck
+	s.archive = newArchive(f, bundleDir)
+
+	s.collectCluster(ctx)
+	olmNamespace, monitoringNamespace, dbNamespaces := s.namespaces(ctx)
+	seen := map[string]struct{}{}
+	for _, ns := range append([]string{s.config.SystemNamespace, olmNamespace, monitoringNamespace}, dbNamespaces...) {
+		if _, ok := seen[ns]; ok {
+			continue
+		}
+		seen[ns] = struct{}{}
+		s.l.Infof("Collecting %s namespace", ns)
+		s.collectPods(ctx, ns)
+		s.collectOLM(ctx, ns)
+	}
+	for _, ns := range dbNamespaces {
+		s.collectDatabases(ctx, ns)
+	}
+
+	if len(s.errs) != 0 {
+		s.add("errors.txt", []byte(strings.Join(s.errs, "\n")+"\n"))
+	}
+	if err := s.archive.Close(); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not write %s", s.config.Output))
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not write %s", s.config.Output))
+	}
+
+	return &Response{Path: s.config.Output, Files: s.archive.files, Errors: s.errs}, nil
+}
+
+// namespaces returns the namespaces of the installation.
+// The defaults are used for what cannot be loaded so that a broken
+// installation can still be diagnosed.
+func (s *SupportBundle) namespaces(ctx context.Context) (string, string, []string) {
+	olmNamespace := kubernetes.OLMNamespace
+	if olm, err := install.LoadOLMState(ctx, s.kubeClient, s.config.SystemNamespace); err != nil {
+		s.fail("olm state", err)
+	} else {
+		olmNamespace = olm.Namespace
+	}
+
+	monitoringNamespace, err := install.LoadMonitoringNamespace(ctx, s.kubeClient, s.config.SystemNamespace)
+	if err != nil {
+		s.fail("monitoring namespace", err)
+		monitoringNamespace = install.MonitoringNamespace
+	}
+
+	dbNamespaces, err := s.kubeClient.GetDBNamespaces(ctx, s.config.SystemNamespace)
+	if err != nil {
+		s.fail("database namespaces", err)
+	}
+
+	return olmNamespace, monitoringNamespace, dbNamespaces
+}
+
+func (s *SupportBundle) collectCluster(ctx context.Context) {
+	if info, err := s.kubeClient.GetServerVersion(); err != nil {
+		s.fail("server version", err)
+	} else {
+		s.addYAML("cluster/server-version.yaml", info)
+	}
+
+	nodes, err := s.kubeClient.GetNodes(ctx)
+	if err != nil {
+		s.fail("nodes", err)
+		return
+	}
+	summaries := make([]nodeSummary, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		summaries = append(summaries, summarizeNode(node))
+	}
+	s.addYAML("cluster/nodes.yaml", summaries)
+}
+
+func (s *SupportBundle) collectPods(ctx context.Context, namespace string) {
+	pods, err := s.kubeClient.GetPods(ctx, namespace, nil)
+	if err != nil {
+		s.fail("pods in "+namespace, err)
+		return
+	}
+	s.addObject(path.Join("namespaces", namespace, "pods.yaml"), pods)
+
+	for _, pod := range pods.Items {
+		events, err := s.kubeClient.GetEvents(ctx, namespace, pod.Name)
+		if err != nil {
+			s.fail("events of "+pod.Name, err)
+		} else {
+			s.addText(path.Join("namespaces", namespace, "events", pod.Name+".txt"), events)
+		}
+
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			logs, err := s.kubeClient.GetLogs(ctx, statuses, namespace, pod.Name, container.Name)
+			if err != nil {
+				s.fail(fmt.Sprintf("logs of %s/%s", pod.Name, container.Name), err)
+				continue
+			}
+			s.addText(path.Join("namespaces", namespace, "logs", pod.Name, container.Name+".log"), logs)
+		}
+	}
+}
+
+func (s *SupportBundle) collectOLM(ctx context.Context, namespace string) {
+	dir := path.Join("namespaces", namespace)
+	if subs, err := s.kubeClient.ListSubscriptions(ctx, namespace); err != nil {
+		s.fail("subscriptions in "+namespace, err)
+	} else {
+		s.addObject(path.Join(dir, "subscriptions.yaml"), subs)
+	}
+	if csvs, err := s.kubeClient.ListClusterServiceVersion(ctx, namespace); err != nil {
+		s.fail("cluster service versions in "+namespace, err)
+	} else {
+		s.addObject(path.Join(dir, "clusterserviceversions.yaml"), csvs)
+	}
+	if ips, err := s.kubeClient.ListInstallPlans(ctx, namespace); err != nil {
+		s.fail("install plans in "+namespace, err)
+	} else {
+		s.addObject(path.Join(dir, "installplans.yaml"), ips)
+	}
+}
+
+func (s *SupportBundle) collectDatabases(ctx context.Context, namespace string) {
+	dir := path.Join("namespaces", namespace)
+	if dbs, err := s.kubeClient.ListDatabaseClusters(ctx, namespace); err != nil {
+		s.fail("database clusters in "+namespace, err)
+	} else {
+		s.addObject(path.Join(dir, "databaseclusters.yaml"), dbs)
+	}
+	if backups, err := s.kubeClient.ListDatabaseClusterBackups(ctx, namespace, metav1.ListOptions{}); err != nil {
+		s.fail("database cluster backups in "+namespace, err)
+	} else {
+		s.addObject(path.Join(dir, "databaseclusterbackups.yaml"), backups)
+	}
+	if restores, err := s.kubeClient.ListDatabaseClusterRestores(ctx, namespace, metav1.ListOptions{}); err != nil {
+		s.fail("database cluster restores in "+namespace, err)
+	} else {
+		s.addObject(path.Join(dir, "databaseclusterrestores.yaml"), restores)
+	}
+	if engines, err := s.kubeClient.ListDatabaseEngines(ctx, namespace); err != nil {
+		s.fail("database engines in "+namespace, err)
+	} else {
+		s.addObject(path.Join(dir, "databaseengines.yaml"), engines)
+	}
+}
+
+// fail records an item which could not be collected.
+func (s *SupportBundle) fail(item string, err error) {
+	s.l.Warnf("Could not collect %s: %s", item, err)
+	s.errs = append(s.errs, fmt.Sprintf("%s: %s", item, err))
+}
+
+func (s *SupportBundle) add(name string, content []byte) {
+	if err := s.archive.Add(name, content); err != nil {
+		s.fail(name, err)
+	}
+}
+
+// addText adds the lines to the bundle with the secrets redacted.
+func (s *SupportBundle) addText(name string, lines []string) {
+	s.add(name, []byte(redactText(strings.Join(lines, "\n"))))
+}
+
+// addObject adds the Kubernetes object to the bundle as YAML with the secrets redacted.
+func (s *SupportBundle) addObject(name string, obj runtime.Object) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		s.fail(name, err)
+		return
+	}
+	s.addYAML(name, redactObject(u))
+}
+
+func (s *SupportBundle) addYAML(name string, v interface{}) {
+	content, err := yaml.Marshal(v)
+	if err != nil {
+		s.fail(name, err)
+		return
+	}
+	s.add(name, content)
+}
+
+func summarizeNode(node corev1.Node) nodeSummary {
+	return nodeSummary{
+		Name:             node.Name,
+		Ready:            kubernetes.IsNodeInCondition(node, corev1.NodeReady),
+		Unschedulable:    node.Spec.Unschedulable,
+		KubeletVersion:   node.Status.NodeInfo.KubeletVersion,
+		OSImage:          node.Status.NodeInfo.OSImage,
+		ContainerRuntime: node.Status.NodeInfo.ContainerRuntimeVersion,
+		Allocatable: map[string]string{
+			corev1.ResourceCPU.String():    node.Status.Allocatable.Cpu().String(),
+			corev1.ResourceMemory.String(): node.Status.Allocatable.Memory().String(),
+		},
+		Labels: node.Labels,
+		Taints: node.Spec.Taints,
+	}
+}
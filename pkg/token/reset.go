@@ -21,7 +21,6 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
-	"net/url"
 
 	"github.com/dchest/uniuri"
 	"go.uber.org/zap"
@@ -70,13 +69,8 @@ func NewReset(c ResetConfig, l *zap.SugaredLogger) (*Reset, error) {
 		l:      l.With("component", "token/reset"),
 	}
 
-	k, err := kubernetes.New(c.KubeconfigPath, cli.l)
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
 	if err != nil {
-		var u *url.Error
-		if errors.As(err, &u) {
-			cli.l.Error("Could not connect to Kubernetes. " +
-				"Make sure Kubernetes is running and is accessible from this computer/server.")
-		}
 		return nil, err
 	}
 	cli.kubeClient = k
@@ -84,6 +78,15 @@ func NewReset(c ResetConfig, l *zap.SugaredLogger) (*Reset, error) {
 	return cli, nil
 }
 
+// NewResetWithClient returns a new Reset struct which uses the provided Kubernetes client.
+func NewResetWithClient(c ResetConfig, k *kubernetes.Kubernetes, l *zap.SugaredLogger) *Reset {
+	return &Reset{
+		config:     c,
+		l:          l.With("component", "token/reset"),
+		kubeClient: k,
+	}
+}
+
 // Run runs the reset command.
 func (r *Reset) Run(ctx context.Context) (*ResetResponse, error) {
 	ns, err := r.kubeClient.GetNamespace(ctx, r.config.Namespace)
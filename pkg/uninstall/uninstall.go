@@ -18,6 +18,7 @@ package uninstall
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/percona/percona-everest-cli/pkg/install"
 	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
 )
 
 // Uninstall implements logic for the cluster command.
@@ -38,6 +40,8 @@ type Uninstall struct {
 	config     Config
 	kubeClient *kubernetes.Kubernetes
 	l          *zap.SugaredLogger
+	// monitoringNamespace is the monitoring namespace of the installation.
+	monitoringNamespace string
 }
 
 // Config stores configuration for the Uninstall command.
@@ -48,6 +52,10 @@ type Config struct {
 	AssumeYes bool `mapstructure:"assume-yes"`
 	// Force is true when we shall not prompt for removal.
 	Force bool
+	// DryRun records the changes instead of applying them to the cluster.
+	DryRun bool `mapstructure:"dry-run"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
 }
 
 // NewUninstall returns a new Uninstall struct.
@@ -57,6 +65,10 @@ func NewUninstall(c Config, l *zap.SugaredLogger) (*Uninstall, error) {
 		return nil, err
 	}
 
+	if c.DryRun {
+		kubeClient.EnableDryRun()
+	}
+
 	cli := &Uninstall{
 		config:     c,
 		kubeClient: kubeClient,
@@ -65,9 +77,14 @@ func NewUninstall(c Config, l *zap.SugaredLogger) (*Uninstall, error) {
 	return cli, nil
 }
 
+// Plan returns the changes recorded in dry-run mode.
+func (u *Uninstall) Plan() *client.Plan {
+	return u.kubeClient.DryRunPlan()
+}
+
 // Run runs the cluster command.
 func (u *Uninstall) Run(ctx context.Context) error {
-	if !u.config.AssumeYes {
+	if !u.config.AssumeYes && !u.config.DryRun {
 		msg := `You are about to uninstall Everest from the Kubernetes cluster.
 This will uninstall Everest and all its components from the cluster.`
 		fmt.Printf("\n%s\n\n", msg) //nolint:forbidigo
@@ -85,6 +102,24 @@ This will uninstall Everest and all its components from the cluster.`
 		}
 	}
 
+	// The installation state is deleted along with the system namespace.
+	olm, err := install.LoadOLMState(ctx, u.kubeClient, u.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	u.kubeClient.SetOLMNamespace(olm.Namespace)
+	if u.monitoringNamespace, err = install.LoadMonitoringNamespace(ctx, u.kubeClient, u.config.SystemNamespace); err != nil {
+		return err
+	}
+	defaultStorageClass, err := install.LoadDefaultStorageClass(ctx, u.kubeClient, u.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	others, err := install.OtherInstallations(ctx, u.kubeClient, u.config.SystemNamespace, olm.Namespace)
+	if err != nil {
+		return err
+	}
+
 	dbsExist, err := u.dbsExist(ctx)
 	if err != nil {
 		return err
@@ -121,27 +156,54 @@ This will uninstall Everest and all its components from the cluster.`
 
 	// There are no resources with finalizers in the monitoring namespace, so
 	// we can delete it directly
-	if err := u.deleteNamespaces(ctx, []string{install.MonitoringNamespace}); err != nil {
+	if err := u.deleteNamespaces(ctx, []string{u.monitoringNamespace}); err != nil {
 		return err
 	}
 
 	// All resources with finalizers in the system namespace (DBCs and
 	// BackupStorages) have already been deleted, so we can delete the
 	// namespace directly
-	if err := u.deleteNamespaces(ctx, []string{install.SystemNamespace}); err != nil {
+	if err := u.deleteNamespaces(ctx, []string{u.config.SystemNamespace}); err != nil {
 		return err
 	}
 
+	if defaultStorageClass != "" {
+		u.l.Infof("Reverting %s storage class made default by the installation", defaultStorageClass)
+		if err := u.kubeClient.UnsetDefaultStorageClass(ctx, defaultStorageClass); err != nil {
+			return errors.Join(err, fmt.Errorf("could not revert %s storage class", defaultStorageClass))
+		}
+	}
+
 	// There are no resources with finalizers in the monitoring namespace, so
-	// we can delete it directly
-	if err := u.deleteOLM(ctx); err != nil {
+	// we can delete it directly. OLM installed by someone else is kept.
+	if len(others) != 0 {
+		u.l.Infof("Keeping OLM and Percona catalog in %s namespace used by Everest in %s namespace(s)",
+			olm.Namespace, strings.Join(others, ", "))
+	} else if olm.Existing {
+		u.l.Infof("Deleting Percona catalog from %s namespace", olm.Namespace)
+		if err := u.kubeClient.DeletePerconaCatalog(); err != nil {
+			return err
+		}
+	} else if err := u.deleteOLM(ctx); err != nil {
 		return err
 	}
 
+	if u.config.DryRun {
+		return nil
+	}
 	u.l.Info("Everest has been uninstalled successfully")
 	return nil
 }
 
+// waitFor polls the condition every 5 seconds until it's met, or timeouts after 5 minutes.
+// In dry-run mode nothing is deleted so it returns immediately.
+func (u *Uninstall) waitFor(ctx context.Context, condition wait.ConditionWithContextFunc) error {
+	if u.config.DryRun {
+		return nil
+	}
+	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, false, condition)
+}
+
 func (u *Uninstall) confirmForce() (bool, error) {
 	if u.config.Force {
 		return true, nil
@@ -159,7 +221,7 @@ func (u *Uninstall) confirmForce() (bool, error) {
 }
 
 func (u *Uninstall) getDBs(ctx context.Context) (map[string]*everestv1alpha1.DatabaseClusterList, error) {
-	namespaces, err := u.kubeClient.GetDBNamespaces(ctx, install.SystemNamespace)
+	namespaces, err := u.kubeClient.GetDBNamespaces(ctx, u.config.SystemNamespace)
 	if err != nil {
 		return nil, err
 	}
@@ -229,8 +291,10 @@ func (u *Uninstall) deleteDBs(ctx context.Context) error {
 	// CR. Until this is implemented, we work around this by sleeping for two
 	// minutes to give the DB operators a chance to delete the resources they
 	// manage before we delete the namespaces.
-	time.Sleep(2 * time.Minute)
-	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, false, func(ctx context.Context) (bool, error) {
+	if !u.config.DryRun {
+		time.Sleep(2 * time.Minute)
+	}
+	return u.waitFor(ctx, func(ctx context.Context) (bool, error) {
 		allDBs, err := u.getDBs(ctx)
 		if err != nil {
 			return false, err
@@ -258,7 +322,7 @@ func (u *Uninstall) deleteNamespaces(ctx context.Context, namespaces []string) e
 
 	// Wait for all namespaces to be deleted, or timeout after 5 minutes.
 	u.l.Infof("Waiting for namespace(s) '%s' to be deleted", strings.Join(namespaces, "', '"))
-	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, false, func(ctx context.Context) (bool, error) {
+	return u.waitFor(ctx, func(ctx context.Context) (bool, error) {
 		for _, ns := range namespaces {
 			_, err := u.kubeClient.GetNamespace(ctx, ns)
 			if err != nil && !k8serrors.IsNotFound(err) {
@@ -276,16 +340,42 @@ func (u *Uninstall) deleteNamespaces(ctx context.Context, namespaces []string) e
 }
 
 func (u *Uninstall) deleteDBNamespaces(ctx context.Context) error {
-	namespaces, err := u.kubeClient.GetDBNamespaces(ctx, install.SystemNamespace)
+	namespaces, err := u.kubeClient.GetDBNamespaces(ctx, u.config.SystemNamespace)
 	if err != nil {
 		return err
 	}
 
+	if err := u.deleteDBOperators(ctx, namespaces); err != nil {
+		return err
+	}
+
 	return u.deleteNamespaces(ctx, namespaces)
 }
 
+// deleteDBOperators deletes the database operators installed into the namespaces
+// according to the namespace operators stored by the installation. The operators
+// are deleted before the namespaces so OLM stops reconciling them.
+func (u *Uninstall) deleteDBOperators(ctx context.Context, namespaces []string) error {
+	m, err := install.LoadNamespaceOperators(ctx, u.kubeClient, u.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+
+	all := install.OperatorConfig{PG: true, PSMDB: true, PXC: true}
+	for _, ns := range namespaces {
+		for _, op := range m.Operators(ns, all).Names() {
+			u.l.Infof("Deleting operator '%s' in namespace '%s'", op, ns)
+			if err := u.kubeClient.DeleteOperator(ctx, ns, op); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (u *Uninstall) deleteBackupStorages(ctx context.Context) error { //nolint:dupl
-	storages, err := u.kubeClient.ListBackupStorages(ctx, install.SystemNamespace)
+	storages, err := u.kubeClient.ListBackupStorages(ctx, u.config.SystemNamespace)
 	if err != nil {
 		return err
 	}
@@ -296,15 +386,15 @@ func (u *Uninstall) deleteBackupStorages(ctx context.Context) error { //nolint:d
 
 	for _, storage := range storages.Items {
 		u.l.Infof("Deleting backup storage '%s'", storage.Name)
-		if err := u.kubeClient.DeleteBackupStorage(ctx, install.SystemNamespace, storage.Name); err != nil {
+		if err := u.kubeClient.DeleteBackupStorage(ctx, u.config.SystemNamespace, storage.Name); err != nil {
 			return err
 		}
 	}
 
 	// Wait for all backup storages to be deleted, or timeout after 5 minutes.
 	u.l.Infof("Waiting for backup storages to be deleted")
-	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, false, func(ctx context.Context) (bool, error) {
-		storages, err := u.kubeClient.ListBackupStorages(ctx, install.SystemNamespace)
+	return u.waitFor(ctx, func(ctx context.Context) (bool, error) {
+		storages, err := u.kubeClient.ListBackupStorages(ctx, u.config.SystemNamespace)
 		if err != nil {
 			return false, err
 		}
@@ -320,7 +410,7 @@ func (u *Uninstall) deleteBackupStorages(ctx context.Context) error { //nolint:d
 }
 
 func (u *Uninstall) deleteMonitoringConfigs(ctx context.Context) error { //nolint:dupl
-	monitoringConfigs, err := u.kubeClient.ListMonitoringConfigs(ctx, install.MonitoringNamespace)
+	monitoringConfigs, err := u.kubeClient.ListMonitoringConfigs(ctx, u.monitoringNamespace)
 	if err != nil {
 		return err
 	}
@@ -331,15 +421,15 @@ func (u *Uninstall) deleteMonitoringConfigs(ctx context.Context) error { //nolin
 
 	for _, config := range monitoringConfigs.Items {
 		u.l.Infof("Deleting monitoring config '%s'", config.Name)
-		if err := u.kubeClient.DeleteMonitoringConfig(ctx, install.MonitoringNamespace, config.Name); err != nil {
+		if err := u.kubeClient.DeleteMonitoringConfig(ctx, u.monitoringNamespace, config.Name); err != nil {
 			return err
 		}
 	}
 
 	// Wait for all monitoring configs to be deleted, or timeout after 5 minutes.
 	u.l.Infof("Waiting for monitoring configs to be deleted")
-	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, false, func(ctx context.Context) (bool, error) {
-		monitoringConfigs, err := u.kubeClient.ListMonitoringConfigs(ctx, install.MonitoringNamespace)
+	return u.waitFor(ctx, func(ctx context.Context) (bool, error) {
+		monitoringConfigs, err := u.kubeClient.ListMonitoringConfigs(ctx, u.monitoringNamespace)
 		if err != nil {
 			return false, err
 		}
@@ -355,14 +445,14 @@ func (u *Uninstall) deleteMonitoringConfigs(ctx context.Context) error { //nolin
 }
 
 func (u *Uninstall) deleteOLM(ctx context.Context) error {
-	packageServerName := types.NamespacedName{Name: "packageserver", Namespace: kubernetes.OLMNamespace}
+	packageServerName := types.NamespacedName{Name: "packageserver", Namespace: u.kubeClient.GetOLMNamespace()}
 	if err := u.kubeClient.DeleteClusterServiceVersion(ctx, packageServerName); err != nil {
 		return err
 	}
 
 	// Wait for the packageserver CSV to be deleted, or timeout after 5 minutes.
 	u.l.Infof("Waiting for packageserver CSV to be deleted")
-	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, false, func(ctx context.Context) (bool, error) {
+	err := u.waitFor(ctx, func(ctx context.Context) (bool, error) {
 		_, err := u.kubeClient.GetClusterServiceVersion(ctx, packageServerName)
 		if err != nil && !k8serrors.IsNotFound(err) {
 			return false, err
@@ -380,5 +470,5 @@ func (u *Uninstall) deleteOLM(ctx context.Context) error {
 		return err
 	}
 
-	return u.deleteNamespaces(ctx, []string{kubernetes.OLMNamespace})
+	return u.deleteNamespaces(ctx, []string{u.kubeClient.GetOLMNamespace()})
 }
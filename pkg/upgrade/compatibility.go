@@ -0,0 +1,319 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	goversion "github.com/hashicorp/go-version"
+	"gopkg.in/yaml.v3"
+
+	"github.com/percona/percona-everest-cli/data"
+	"github.com/percona/percona-everest-cli/pkg/version"
+)
+
+const everestOperatorName = "everest-operator"
+
+type (
+	// Matrix describes the Everest releases supported by the upgrade
+	// and the paths between them.
+	Matrix struct {
+		Releases []Release `yaml:"releases"`
+	}
+
+	// Release describes an Everest release in the compatibility matrix.
+	Release struct {
+		// Version is the version of the Everest release.
+		Version string `yaml:"version"`
+		// UpgradeFrom is a version constraint of the installed versions
+		// which can be upgraded to the release in a single step.
+		UpgradeFrom string `yaml:"upgradeFrom"`
+		// EverestOperator is the version of the Everest operator shipped with the release.
+		EverestOperator string `yaml:"everestOperator"`
+		// Backend is the version of the Everest backend shipped with the release.
+		Backend string `yaml:"backend"`
+		// DBOperators maps the database operators to the version constraints
+		// supported by the release.
+		DBOperators map[string]string `yaml:"dbOperators"`
+
+		version     *goversion.Version
+		upgradeFrom goversion.Constraints
+	}
+)
+
+// LoadMatrix parses the compatibility matrix.
+// The releases are sorted by version.
+func LoadMatrix(b []byte) (*Matrix, error) {
+	m := &Matrix{}
+	if err := yaml.Unmarshal(b, m); err != nil {
+		return nil, errors.Join(err, errors.New("could not parse compatibility matrix"))
+	}
+
+	for i := range m.Releases {
+		r := &m.Releases[i]
+		v, err := goversion.NewSemver(r.Version)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("invalid version %q in compatibility matrix", r.Version))
+		}
+		r.version = v
+		if r.UpgradeFrom != "" {
+			if r.upgradeFrom, err = goversion.NewConstraint(r.UpgradeFrom); err != nil {
+				return nil, errors.Join(err, fmt.Errorf("invalid upgradeFrom of %s in compatibility matrix", r.Version))
+			}
+		}
+		for op, c := range r.DBOperators {
+			if _, err := goversion.NewConstraint(c); err != nil {
+				return nil, errors.Join(err, fmt.Errorf("invalid %s constraint of %s in compatibility matrix", op, r.Version))
+			}
+		}
+	}
+	sort.Slice(m.Releases, func(i, j int) bool {
+		return m.Releases[i].version.LessThan(m.Releases[j].version)
+	})
+
+	return m, nil
+}
+
+// Release returns the release of the given version.
+func (m *Matrix) Release(v string) (*Release, bool) {
+	sv, err := goversion.NewSemver(v)
+	if err != nil {
+		return nil, false
+	}
+	for i := range m.Releases {
+		if m.Releases[i].version.Equal(sv) {
+			return &m.Releases[i], true
+		}
+	}
+	return nil, false
+}
+
+// Path returns the releases to upgrade through one at a time to get from
+// the installed version to the target version. Each step goes to the newest
+// release not newer than the target which supports the upgrade from the previous step.
+// An empty path is returned if the installed version is the target version.
+func (m *Matrix) Path(from, to string) ([]Release, error) {
+	current, err := goversion.NewSemver(from)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("invalid version %q", from))
+	}
+	target, ok := m.Release(to)
+	if !ok {
+		return nil, fmt.Errorf("version %s is not a supported Everest release. Supported releases are %s",
+			to, strings.Join(m.versions(), ", "))
+	}
+	if current.GreaterThan(target.version) {
+		return nil, fmt.Errorf("installed Everest %s cannot be downgraded to %s", from, to)
+	}
+
+	path := []Release{}
+	for current.LessThan(target.version) {
+		var next *Release
+		for i := range m.Releases {
+			r := &m.Releases[i]
+			if r.version.GreaterThan(target.version) {
+				break
+			}
+			if r.upgradeFrom != nil && r.upgradeFrom.Check(current) {
+				next = r
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("there is no supported upgrade path from Everest %s to %s", from, to)
+		}
+		path = append(path, *next)
+		current = next.version
+	}
+
+	return path, nil
+}
+
+// CheckDBOperator returns an error if the version of the database operator
+// is not supported by the release. Operators not listed in the release are not checked.
+func (r Release) CheckDBOperator(name, v string) error {
+	c, ok := r.DBOperators[name]
+	if !ok {
+		return nil
+	}
+	constraints, err := goversion.NewConstraint(c)
+	if err != nil {
+		return err
+	}
+	sv, err := goversion.NewSemver(v)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("invalid version %q of %s", v, name))
+	}
+	if !constraints.Check(sv) {
+		return fmt.Errorf("%s %s is not supported by Everest %s which requires %s", name, v, r.Version, c)
+	}
+	return nil
+}
+
+func (m *Matrix) versions() []string {
+	versions := make([]string, 0, len(m.Releases))
+	for _, r := range m.Releases {
+		versions = append(versions, r.Version)
+	}
+	return versions
+}
+
+// stepsMessage returns the commands upgrading through the path one step at a time.
+func stepsMessage(path []Release) string {
+	steps := make([]string, 0, len(path))
+	for _, r := range path {
+		steps = append(steps, "  everestctl upgrade --to-version "+r.Version)
+	}
+	return strings.Join(steps, "\n")
+}
+
+// checkCompatibility verifies the installed Everest can be upgraded to the target version.
+// The upgrade is refused if it skips releases, downgrades Everest, fixes a mismatch
+// between the Everest operator and the backend by moving to another release or
+// if the installed database operators are not supported by the target release.
+func (u *Upgrade) checkCompatibility(ctx context.Context) error {
+	target := u.targetVersion()
+	if target == "" {
+		u.l.Warn("Development build of everestctl. Skipping the compatibility checks of the upgrade")
+		return nil
+	}
+	if version.IsRelease() {
+		cli, err := goversion.NewSemver(version.Version)
+		if err != nil {
+			return err
+		}
+		if tv, err := goversion.NewSemver(target); err == nil && tv.GreaterThan(cli) {
+			return fmt.Errorf("everestctl %s cannot upgrade Everest to %s. Use everestctl %s or newer", version.Version, target, target)
+		}
+	}
+
+	m, err := LoadMatrix(data.Compatibility)
+	if err != nil {
+		return err
+	}
+	installed, err := u.kubeClient.GetInstalledOperatorVersion(ctx, u.config.SystemNamespace, everestOperatorName)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the installed version of the Everest operator"))
+	}
+	if installed == "" {
+		return errors.New("could not find the installed Everest operator. Make sure Everest is installed")
+	}
+	backend, err := u.kubeClient.GetEverestVersion(ctx, u.config.SystemNamespace)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the installed version of Everest"))
+	}
+	if _, err := goversion.NewSemver(backend); err != nil {
+		u.l.Warnf("Everest %s is a development build. Skipping the compatibility checks of the upgrade", backend)
+		return nil
+	}
+
+	path, err := m.Path(installed, target)
+	if err != nil {
+		return err
+	}
+	if err := u.checkSkew(m, installed, backend, target); err != nil {
+		return err
+	}
+	if len(path) > 1 {
+		return fmt.Errorf("installed Everest %s cannot be upgraded to %s directly. "+
+			"Upgrade through the intermediate releases one at a time:\n%s", installed, target, stepsMessage(path))
+	}
+
+	release, _ := m.Release(target)
+	return u.checkDBOperators(ctx, *release)
+}
+
+// checkSkew returns an error if the installed Everest operator and backend
+// do not belong to the same release. Only re-applying the release of the
+// Everest operator is allowed in that case.
+func (u *Upgrade) checkSkew(m *Matrix, operator, backend, target string) error {
+	expected := operator
+	if r, ok := m.Release(operator); ok {
+		expected = r.Backend
+	}
+	ev, err := goversion.NewSemver(expected)
+	if err != nil {
+		return err
+	}
+	bv, err := goversion.NewSemver(backend)
+	if err != nil {
+		return err
+	}
+	if ev.Equal(bv) {
+		return nil
+	}
+	if tv, err := goversion.NewSemver(target); err == nil && tv.Equal(ev) {
+		u.l.Warnf("Everest backend %s does not match the Everest operator %s. Re-applying Everest %s", backend, operator, target)
+		return nil
+	}
+	return fmt.Errorf("installed Everest backend %s does not match the Everest operator %s. "+
+		"Repair the installation before upgrading:\n  everestctl upgrade --to-version %s", backend, operator, operator)
+}
+
+// checkDBOperators returns an error if the database operators installed
+// in the namespaces managed by Everest are not supported by the release.
+func (u *Upgrade) checkDBOperators(ctx context.Context, r Release) error {
+	namespaces, err := u.kubeClient.GetDBNamespaces(ctx, u.config.SystemNamespace)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+
+	operators := make([]string, 0, len(r.DBOperators))
+	for op := range r.DBOperators {
+		operators = append(operators, op)
+	}
+	sort.Strings(operators)
+
+	var errs []error
+	for _, ns := range namespaces {
+		for _, op := range operators {
+			v, err := u.kubeClient.GetInstalledOperatorVersion(ctx, ns, op)
+			if err != nil {
+				return errors.Join(err, fmt.Errorf("could not get the installed version of %s in %s namespace", op, ns))
+			}
+			if v == "" {
+				continue
+			}
+			if err := r.CheckDBOperator(op, v); err != nil {
+				errs = append(errs, fmt.Errorf("namespace %s: %w", ns, err))
+			}
+		}
+	}
+	if len(errs) != 0 {
+		errs = append(errs, errors.New("upgrade the database operators with `everestctl upgrade operators` before upgrading Everest"))
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// targetVersion returns the Everest version the upgrade moves to.
+// An empty version is returned for development builds of everestctl.
+func (u *Upgrade) targetVersion() string {
+	if u.config.ToVersion != "" {
+		return u.config.ToVersion
+	}
+	if u.bundleVersion != "" {
+		return u.bundleVersion
+	}
+	if version.IsRelease() {
+		return strings.TrimPrefix(version.Version, "v")
+	}
+	return ""
+}
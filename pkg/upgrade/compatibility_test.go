@@ -0,0 +1,93 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/percona/percona-everest-cli/data"
+)
+
+const testMatrix = `
+releases:
+  - version: 0.2.0
+  - version: 0.3.0
+    upgradeFrom: ">= 0.2.0, < 0.3.0"
+  - version: 0.4.0
+    upgradeFrom: ">= 0.2.0, < 0.4.0"
+  - version: 0.5.0
+    upgradeFrom: ">= 0.4.0, < 0.5.0"
+    dbOperators:
+      percona-xtradb-cluster-operator: ">= 1.13.0"
+`
+
+func TestMatrixPath(t *testing.T) {
+	t.Parallel()
+
+	m, err := LoadMatrix([]byte(testMatrix))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "same version", from: "0.4.0", to: "0.4.0", want: []string{}},
+		{name: "single step", from: "0.4.0", to: "0.5.0", want: []string{"0.5.0"}},
+		{name: "patch release", from: "0.4.1", to: "0.5.0", want: []string{"0.5.0"}},
+		{name: "skips releases allowed to", from: "0.2.0", to: "0.4.0", want: []string{"0.4.0"}},
+		{name: "intermediate release", from: "0.2.0", to: "0.5.0", want: []string{"0.4.0", "0.5.0"}},
+		{name: "stops at target", from: "0.2.0", to: "0.3.0", want: []string{"0.3.0"}},
+		{name: "downgrade", from: "0.5.0", to: "0.4.0", wantErr: true},
+		{name: "unknown target", from: "0.4.0", to: "0.6.0", wantErr: true},
+		{name: "no path", from: "0.1.0", to: "0.5.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path, err := m.Path(tt.from, tt.to)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got := make([]string, 0, len(path))
+			for _, r := range path {
+				got = append(got, r.Version)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReleaseCheckDBOperator(t *testing.T) {
+	t.Parallel()
+
+	m, err := LoadMatrix([]byte(testMatrix))
+	require.NoError(t, err)
+	r, ok := m.Release("0.5.0")
+	require.True(t, ok)
+
+	require.NoError(t, r.CheckDBOperator("percona-xtradb-cluster-operator", "1.13.0"))
+	require.NoError(t, r.CheckDBOperator("percona-server-mongodb-operator", "1.0.0"))
+	require.Error(t, r.CheckDBOperator("percona-xtradb-cluster-operator", "1.12.0"))
+}
+
+func TestEmbeddedMatrix(t *testing.T) {
+	t.Parallel()
+
+	m, err := LoadMatrix(data.Compatibility)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.Releases)
+
+	// Every release except the oldest one has to be reachable from the oldest one.
+	oldest := m.Releases[0].Version
+	for _, r := range m.Releases[1:] {
+		_, err := m.Path(oldest, r.Version)
+		assert.NoError(t, err, r.Version)
+	}
+}
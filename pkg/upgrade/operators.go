@@ -0,0 +1,335 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	goversion "github.com/hashicorp/go-version"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	everestv1alpha1 "github.com/percona/everest-operator/api/v1alpha1"
+	"go.uber.org/zap"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/operators"
+)
+
+// operatorEngines maps the database operators to the engines they manage.
+var operatorEngines = map[string]everestv1alpha1.EngineType{ //nolint:gochecknoglobals
+	"percona-xtradb-cluster-operator": everestv1alpha1.DatabaseEnginePXC,
+	"percona-server-mongodb-operator": everestv1alpha1.DatabaseEnginePSMDB,
+	"percona-postgresql-operator":     everestv1alpha1.DatabaseEnginePostgresql,
+}
+
+// Operators implements the logic of the upgrade operators command.
+type Operators struct {
+	config OperatorsConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+type (
+	// OperatorsConfig stores configuration for the upgrade operators command.
+	OperatorsConfig struct {
+		// KubeconfigPath is a path to a kubeconfig
+		KubeconfigPath string `mapstructure:"kubeconfig"`
+		// Operator limits the upgrade to a single operator.
+		// If empty, all the database operators are upgraded.
+		Operator string `mapstructure:"-"`
+		// Namespace limits the upgrade to a single namespace.
+		// If empty, the operators in all the namespaces managed by Everest are upgraded.
+		Namespace string `mapstructure:"namespace"`
+		// List only lists the pending upgrades without upgrading the operators.
+		List bool `mapstructure:"list"`
+		// AssumeYes upgrades the operators without asking for confirmation.
+		AssumeYes bool `mapstructure:"assume-yes"`
+		// WaitTimeout is how long to wait for an upgraded operator to become ready.
+		WaitTimeout time.Duration `mapstructure:"wait-timeout"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+	}
+
+	// OperatorUpgrade describes a pending upgrade of a database operator.
+	OperatorUpgrade struct {
+		Namespace      string `json:"namespace"`
+		Operator       string `json:"operator"`
+		CurrentVersion string `json:"currentVersion"`
+		PendingVersion string `json:"pendingVersion"`
+		// CSVs lists every CSV of the install plan approved to upgrade the operator.
+		CSVs     []string `json:"csvs"`
+		Upgraded bool     `json:"upgraded"`
+
+		upgrade operators.PendingUpgrade
+		csv     string
+	}
+
+	// OutdatedCluster describes a database cluster whose CR version
+	// is older than the version of its operator.
+	OutdatedCluster struct {
+		Namespace       string `json:"namespace"`
+		Name            string `json:"name"`
+		Engine          string `json:"engine"`
+		CRVersion       string `json:"crVersion"`
+		OperatorVersion string `json:"operatorVersion"`
+	}
+
+	// OperatorsResponse is a response from the upgrade operators command.
+	OperatorsResponse struct {
+		Upgrades []OperatorUpgrade `json:"upgrades"`
+		// OutdatedClusters lists the database clusters which may need
+		// their CR version bumped after the upgrade of their operators.
+		OutdatedClusters []OutdatedCluster `json:"outdatedClusters,omitempty"`
+	}
+)
+
+func (r OperatorsResponse) String() string {
+	if len(r.Upgrades) == 0 {
+		return "There are no pending upgrades of the database operators"
+	}
+
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+	fmt.Fprintln(w, "NAMESPACE\tOPERATOR\tCURRENT VERSION\tPENDING VERSION\tCSV\tUPGRADED")
+	for _, u := range r.Upgrades {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\n",
+			u.Namespace, u.Operator, u.CurrentVersion, u.PendingVersion, strings.Join(u.CSVs, ","), u.Upgraded)
+	}
+	w.Flush() //nolint:errcheck,gosec
+
+	if len(r.OutdatedClusters) != 0 {
+		fmt.Fprintln(&b, "\nThe CR version of the following database clusters is older than the version of their operator:")
+		w = tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0) //nolint:gomnd
+		fmt.Fprintln(w, "NAMESPACE\tNAME\tENGINE\tCR VERSION\tOPERATOR VERSION")
+		for _, c := range r.OutdatedClusters {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Namespace, c.Name, c.Engine, c.CRVersion, c.OperatorVersion)
+		}
+		w.Flush() //nolint:errcheck,gosec
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// NewOperators returns a new Operators struct.
+func NewOperators(c OperatorsConfig, l *zap.SugaredLogger) (*Operators, error) {
+	cli := &Operators{
+		config: c,
+		l:      l.With("component", "upgrade/operators"),
+	}
+	if err := cli.config.validate(); err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+func (c OperatorsConfig) validate() error {
+	if c.Operator == "" {
+		return nil
+	}
+	if _, ok := operatorEngines[c.Operator]; !ok {
+		names := make([]string, 0, len(operatorEngines))
+		for op := range operatorEngines {
+			names = append(names, op)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown operator %s. Supported operators are %s", c.Operator, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// Run runs the upgrade operators command.
+func (o *Operators) Run(ctx context.Context) (*OperatorsResponse, error) {
+	upgrades, err := o.listUpgrades(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &OperatorsResponse{Upgrades: upgrades}
+	if o.config.List {
+		return res, nil
+	}
+
+	// The operators upgraded so far are reported along with an error.
+	approved := map[string]struct{}{}
+	for i := range res.Upgrades {
+		u := &res.Upgrades[i]
+		if err := o.upgrade(ctx, u, approved); err != nil {
+			return res, err
+		}
+		if !u.Upgraded {
+			continue
+		}
+		outdated, err := o.outdatedClusters(ctx, u.Namespace, u.Operator, u.PendingVersion)
+		if err != nil {
+			return res, err
+		}
+		res.OutdatedClusters = append(res.OutdatedClusters, outdated...)
+	}
+
+	return res, nil
+}
+
+// listUpgrades returns the pending upgrades of the database operators
+// in the namespaces managed by Everest.
+func (o *Operators) listUpgrades(ctx context.Context) ([]OperatorUpgrade, error) {
+	namespaces, err := o.kubeClient.GetDBNamespaces(ctx, o.config.SystemNamespace)
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+	if o.config.Namespace != "" {
+		found := false
+		for _, ns := range namespaces {
+			if ns == o.config.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("namespace %s is not managed by Everest", o.config.Namespace)
+		}
+		namespaces = []string{o.config.Namespace}
+	}
+
+	pending, err := operators.ListPendingUpgrades(ctx, o.kubeClient, namespaces, func(s olmv1alpha1.Subscription) bool {
+		_, ok := operatorEngines[s.Name]
+		return ok && (o.config.Operator == "" || s.Name == o.config.Operator)
+	})
+	if err != nil {
+		return nil, err
+	}
+	upgrades := make([]OperatorUpgrade, 0, len(pending))
+	for _, p := range pending {
+		s := p.Subscription
+		csv := s.Status.CurrentCSV
+		for _, name := range p.InstallPlan.Spec.ClusterServiceVersionNames {
+			if strings.HasPrefix(name, s.Name+".") {
+				csv = name
+				break
+			}
+		}
+		upgrades = append(upgrades, OperatorUpgrade{
+			Namespace:      s.Namespace,
+			Operator:       s.Name,
+			CurrentVersion: csvVersion(s.Status.InstalledCSV),
+			PendingVersion: csvVersion(csv),
+			CSVs:           p.CSVs(),
+			upgrade:        p,
+			csv:            csv,
+		})
+	}
+
+	return upgrades, nil
+}
+
+// upgrade approves the pending install plan of the operator after confirmation
+// and waits for the new CSV to succeed. The install plans approved already
+// for other operators are tracked in approved and not approved again.
+func (o *Operators) upgrade(ctx context.Context, u *OperatorUpgrade, approved map[string]struct{}) error {
+	plan := u.Namespace + "/" + u.upgrade.InstallPlan.Name
+	if _, ok := approved[plan]; !ok {
+		ok, err := operators.ApproveUpgrade(ctx, o.kubeClient, u.upgrade, upgradeMessage(u), o.config.AssumeYes)
+		if err != nil || !ok {
+			return err
+		}
+		approved[plan] = struct{}{}
+		if others := u.upgrade.OtherCSVs(u.csv); len(others) != 0 {
+			o.l.Warnf("Install plan %s in %s namespace upgrades %s as well", u.upgrade.InstallPlan.Name, u.Namespace, strings.Join(others, ", "))
+		}
+	}
+
+	o.l.Infof("Upgrading %s in %s namespace to %s", u.Operator, u.Namespace, u.PendingVersion)
+	o.l.Infof("Waiting for %s to succeed", u.csv)
+	waitCtx, cancel := context.WithTimeout(ctx, o.config.WaitTimeout)
+	defer cancel()
+	if err := o.kubeClient.WaitForCSV(waitCtx, u.Namespace, u.csv); err != nil {
+		return errors.Join(err, fmt.Errorf("%s has not succeeded in %s namespace", u.csv, u.Namespace))
+	}
+	o.l.Infof("%s has been upgraded in %s namespace", u.Operator, u.Namespace)
+	u.Upgraded = true
+
+	return nil
+}
+
+// upgradeMessage returns the confirmation of the upgrade of the operator
+// which lists the other CSVs installed by the same install plan.
+func upgradeMessage(u *OperatorUpgrade) string {
+	message := fmt.Sprintf("Upgrade %s in %s namespace from %s to %s", u.Operator, u.Namespace, u.CurrentVersion, u.PendingVersion)
+	if others := u.upgrade.OtherCSVs(u.csv); len(others) != 0 {
+		message += fmt.Sprintf(" along with %s from the same install plan %s", strings.Join(others, ", "), u.upgrade.InstallPlan.Name)
+	}
+	return message + "?"
+}
+
+// outdatedClusters returns the database clusters managed by the operator
+// whose CR version is older than the version of the operator.
+func (o *Operators) outdatedClusters(ctx context.Context, namespace, operator, operatorVersion string) ([]OutdatedCluster, error) {
+	ov, err := goversion.NewSemver(operatorVersion)
+	if err != nil {
+		o.l.Warnf("Could not parse version %q of %s. Skipping the check of the database clusters", operatorVersion, operator)
+		return nil, nil //nolint:nilerr
+	}
+
+	engine := operatorEngines[operator]
+	clusters, err := o.kubeClient.ListDatabaseClusters(ctx, namespace)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not list database clusters in %s namespace", namespace))
+	}
+	crVersions, err := o.kubeClient.GetEngineCRVersions(ctx, namespace, engine)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("could not get the CR versions of %s clusters in %s namespace", engine, namespace))
+	}
+
+	outdated := []OutdatedCluster{}
+	for _, db := range clusters.Items {
+		if db.Spec.Engine.Type != engine {
+			continue
+		}
+		crVersion := crVersions[db.Name]
+		if v, err := goversion.NewSemver(crVersion); err == nil && !v.LessThan(ov) {
+			continue
+		}
+		outdated = append(outdated, OutdatedCluster{
+			Namespace:       namespace,
+			Name:            db.Name,
+			Engine:          string(engine),
+			CRVersion:       crVersion,
+			OperatorVersion: operatorVersion,
+		})
+	}
+
+	return outdated, nil
+}
+
+// csvVersion returns the version part of a CSV name, e.g. 1.14.0 for
+// percona-xtradb-cluster-operator.v1.14.0.
+func csvVersion(name string) string {
+	if _, v, ok := strings.Cut(name, ".v"); ok {
+		return v
+	}
+	return name
+}
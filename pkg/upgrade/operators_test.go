@@ -0,0 +1,65 @@
+package upgrade
+
+import (
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/operators"
+)
+
+func TestCSVVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		csv  string
+		want string
+	}{
+		{name: "operator", csv: "percona-xtradb-cluster-operator.v1.14.0", want: "1.14.0"},
+		{name: "prerelease", csv: "percona-postgresql-operator.v2.3.1-rc1", want: "2.3.1-rc1"},
+		{name: "no version", csv: "percona-server-mongodb-operator", want: "percona-server-mongodb-operator"},
+		{name: "empty", csv: "", want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, csvVersion(tt.csv))
+		})
+	}
+}
+
+func TestOperatorsConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, OperatorsConfig{}.validate())
+	assert.NoError(t, OperatorsConfig{Operator: "percona-server-mongodb-operator"}.validate())
+	assert.Error(t, OperatorsConfig{Operator: "everest-operator"}.validate())
+}
+
+func TestUpgradeMessage(t *testing.T) {
+	t.Parallel()
+
+	u := &OperatorUpgrade{
+		Namespace:      "dev",
+		Operator:       "percona-server-mongodb-operator",
+		CurrentVersion: "1.15.0",
+		PendingVersion: "1.16.0",
+		csv:            "percona-server-mongodb-operator.v1.16.0",
+		upgrade: operators.PendingUpgrade{InstallPlan: &olmv1alpha1.InstallPlan{
+			ObjectMeta: metav1.ObjectMeta{Name: "install-abcde"},
+			Spec: olmv1alpha1.InstallPlanSpec{ClusterServiceVersionNames: []string{
+				"percona-server-mongodb-operator.v1.16.0",
+			}},
+		}},
+	}
+	assert.Equal(t, "Upgrade percona-server-mongodb-operator in dev namespace from 1.15.0 to 1.16.0?", upgradeMessage(u))
+
+	u.upgrade.InstallPlan.Spec.ClusterServiceVersionNames = append(u.upgrade.InstallPlan.Spec.ClusterServiceVersionNames,
+		"percona-xtradb-cluster-operator.v1.14.0")
+	assert.Equal(t, "Upgrade percona-server-mongodb-operator in dev namespace from 1.15.0 to 1.16.0 "+
+		"along with percona-xtradb-cluster-operator.v1.14.0 from the same install plan install-abcde?", upgradeMessage(u))
+}
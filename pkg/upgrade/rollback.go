@@ -0,0 +1,299 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// Rollback implements the logic of the upgrade rollback command.
+type Rollback struct {
+	config RollbackConfig
+	l      *zap.SugaredLogger
+
+	kubeClient *kubernetes.Kubernetes
+}
+
+// RollbackConfig stores configuration for the upgrade rollback command.
+type RollbackConfig struct {
+	// KubeconfigPath is a path to a kubeconfig
+	KubeconfigPath string `mapstructure:"kubeconfig"`
+	// AssumeYes rolls back without asking for confirmation.
+	AssumeYes bool `mapstructure:"assume-yes"`
+	// Force rolls back an upgrade which has completed.
+	Force bool `mapstructure:"force"`
+	// WaitTimeout is how long to wait for the previous deployments to become ready.
+	WaitTimeout time.Duration `mapstructure:"wait-timeout"`
+	// SystemNamespace is the namespace where everest is installed.
+	SystemNamespace string `mapstructure:"system-namespace"`
+}
+
+// NewRollback returns a new Rollback struct.
+func NewRollback(c RollbackConfig, l *zap.SugaredLogger) (*Rollback, error) {
+	cli := &Rollback{
+		config: c,
+		l:      l.With("component", "upgrade/rollback"),
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
+		return nil, err
+	}
+	cli.kubeClient = k
+
+	return cli, nil
+}
+
+// Run restores the state of Everest saved before the last upgrade.
+func (r *Rollback) Run(ctx context.Context) error {
+	s, err := loadSnapshot(ctx, r.kubeClient, r.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	if err := checkSnapshot(s, r.config.Force); err != nil {
+		return err
+	}
+
+	if !r.config.AssumeYes {
+		confirm := false
+		if err := survey.AskOne(&survey.Confirm{
+			Message: fmt.Sprintf("Roll Everest back to version %s saved at %s?", s.EverestVersion, s.CreatedAt),
+		}, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			r.l.Info("Exiting")
+			return nil
+		}
+	}
+
+	olm, err := install.LoadOLMState(ctx, r.kubeClient, r.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	r.kubeClient.SetOLMNamespace(olm.Namespace)
+
+	r.l.Infof("Restoring Percona Catalog %s", s.CatalogImage)
+	if err := r.kubeClient.InstallPerconaCatalog(ctx, s.CatalogImage); err != nil {
+		return errors.Join(err, errors.New("could not restore Percona Catalog"))
+	}
+	if err := r.restoreSubscriptions(ctx, s); err != nil {
+		return err
+	}
+	if err := r.restoreDeployments(ctx, s); err != nil {
+		return err
+	}
+
+	err = r.kubeClient.DeleteObject(&corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SnapshotConfigMapName,
+			Namespace: r.config.SystemNamespace,
+		},
+	})
+	if err != nil {
+		return errors.Join(err, errors.New("could not delete the state of Everest saved before the upgrade"))
+	}
+	r.l.Infof("Everest has been rolled back to version %s", s.EverestVersion)
+
+	return nil
+}
+
+// checkSnapshot refuses to roll back a completed upgrade unless forced.
+func checkSnapshot(s *snapshot, force bool) error {
+	if !s.Completed || force {
+		return nil
+	}
+	return fmt.Errorf("the upgrade from Everest %s started at %s has completed. Use --force to roll it back", s.EverestVersion, s.CreatedAt)
+}
+
+// subscriptionRollback is how a subscription saved in the snapshot is rolled back.
+type subscriptionRollback int
+
+const (
+	// restoreSubscription restores the spec of the subscription.
+	restoreSubscription subscriptionRollback = iota
+	// downgradeSubscription downgrades the operator to the saved CSV.
+	downgradeSubscription
+	// keepSubscription keeps the operator upgraded after the upgrade has completed.
+	keepSubscription
+)
+
+// subscriptionRollback returns how the current subscription is rolled back to the saved one.
+func (s *snapshot) subscriptionRollback(saved, current *olmv1alpha1.Subscription) subscriptionRollback {
+	if saved.Status.InstalledCSV == "" || current.Status.InstalledCSV == saved.Status.InstalledCSV {
+		return restoreSubscription
+	}
+	if s.Completed && s.UpgradedCSVs[subscriptionKey(saved)] != current.Status.InstalledCSV {
+		return keepSubscription
+	}
+	return downgradeSubscription
+}
+
+// restoreSubscriptions restores the spec of the subscriptions.
+// The operators upgraded by the upgrade are downgraded to the saved CSVs.
+func (r *Rollback) restoreSubscriptions(ctx context.Context, s *snapshot) error {
+	r.l.Info("Restoring subscriptions")
+	for i := range s.Subscriptions {
+		saved := &s.Subscriptions[i]
+		list, err := r.kubeClient.ListSubscriptions(ctx, saved.Namespace)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not list subscriptions in %s namespace", saved.Namespace))
+		}
+		var current *olmv1alpha1.Subscription
+		for i := range list.Items {
+			if list.Items[i].Name == saved.Name {
+				current = &list.Items[i]
+				break
+			}
+		}
+		if current == nil {
+			r.l.Warnf("Subscription %s in %s namespace does not exist anymore. Skipping it", saved.Name, saved.Namespace)
+			continue
+		}
+		switch s.subscriptionRollback(saved, current) {
+		case keepSubscription:
+			r.l.Warnf("%s in %s namespace has been upgraded to %s after the upgrade of Everest. Skipping it",
+				saved.Name, saved.Namespace, current.Status.InstalledCSV)
+			continue
+		case downgradeSubscription:
+			if err := r.downgrade(ctx, current, saved); err != nil {
+				return err
+			}
+			continue
+		case restoreSubscription:
+		}
+
+		current.Spec = saved.Spec
+		current.TypeMeta = metav1.TypeMeta{
+			APIVersion: olmv1alpha1.SchemeGroupVersion.String(),
+			Kind:       olmv1alpha1.SubscriptionKind,
+		}
+		if err := r.kubeClient.ApplyObject(current); err != nil {
+			return errors.Join(err, fmt.Errorf("could not restore %s subscription in %s namespace", saved.Name, saved.Namespace))
+		}
+	}
+	return nil
+}
+
+// downgrade reinstalls the operator of the subscription at the CSV saved before the upgrade.
+// OLM does not downgrade operators so the subscription and the installed CSV are deleted
+// and the subscription is recreated starting at the saved CSV. The CRDs and the custom
+// resources of the operator are kept. The Percona catalog is restored beforehand
+// so OLM does not upgrade the operator again if install plans are approved automatically.
+func (r *Rollback) downgrade(ctx context.Context, current, saved *olmv1alpha1.Subscription) error {
+	r.l.Infof("Downgrading %s in %s namespace from %s to %s",
+		saved.Name, saved.Namespace, current.Status.InstalledCSV, saved.Status.InstalledCSV)
+	current.TypeMeta = metav1.TypeMeta{
+		APIVersion: olmv1alpha1.SchemeGroupVersion.String(),
+		Kind:       olmv1alpha1.SubscriptionKind,
+	}
+	if err := r.kubeClient.DeleteObject(current); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Join(err, fmt.Errorf("could not delete %s subscription in %s namespace", current.Name, current.Namespace))
+	}
+	if current.Status.InstalledCSV != "" {
+		err := r.kubeClient.DeleteClusterServiceVersion(ctx, types.NamespacedName{
+			Name:      current.Status.InstalledCSV,
+			Namespace: current.Namespace,
+		})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Join(err, fmt.Errorf("could not delete %s CSV in %s namespace", current.Status.InstalledCSV, current.Namespace))
+		}
+	}
+
+	err := r.kubeClient.InstallOperator(ctx, kubernetes.InstallOperatorRequest{
+		Namespace:              saved.Namespace,
+		Name:                   saved.Name,
+		CatalogSource:          saved.Spec.CatalogSource,
+		CatalogSourceNamespace: saved.Spec.CatalogSourceNamespace,
+		Channel:                saved.Spec.Channel,
+		InstallPlanApproval:    saved.Spec.InstallPlanApproval,
+		StartingCSV:            saved.Status.InstalledCSV,
+		SubscriptionConfig:     saved.Spec.Config,
+	})
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("could not downgrade %s in %s namespace", saved.Name, saved.Namespace))
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, r.config.WaitTimeout)
+	defer cancel()
+	if err := r.kubeClient.WaitForCSV(waitCtx, saved.Namespace, saved.Status.InstalledCSV); err != nil {
+		return errors.Join(err, fmt.Errorf("%s has not succeeded in %s namespace", saved.Status.InstalledCSV, saved.Namespace))
+	}
+	return nil
+}
+
+// restoreDeployments restores the spec of the Everest deployments
+// and waits for them to become ready. The deployments owned by CSVs
+// are restored by OLM along with the subscriptions.
+func (r *Rollback) restoreDeployments(ctx context.Context, s *snapshot) error {
+	for _, saved := range s.Deployments {
+		d, err := r.kubeClient.GetDeployment(ctx, saved.Name, saved.Namespace)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not get %s deployment", saved.Name))
+		}
+		if ownedByCSV(d) {
+			continue
+		}
+		r.l.Infof("Restoring %s deployment", saved.Name)
+		d.Spec = saved.Spec
+		d.TypeMeta = metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		}
+		if err := r.kubeClient.ApplyObject(d); err != nil {
+			return errors.Join(err, fmt.Errorf("could not restore %s deployment", saved.Name))
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, r.config.WaitTimeout)
+	defer cancel()
+	for _, saved := range s.Deployments {
+		r.l.Infof("Waiting for %s deployment to be ready", saved.Name)
+		if err := r.kubeClient.WaitForRollout(waitCtx, saved.Name, saved.Namespace); err != nil {
+			return errors.Join(err, fmt.Errorf("%s deployment has not become ready", saved.Name))
+		}
+	}
+	return nil
+}
+
+// ownedByCSV returns true if the deployment is managed by OLM.
+func ownedByCSV(d *appsv1.Deployment) bool {
+	for _, o := range d.OwnerReferences {
+		if o.Kind == olmv1alpha1.ClusterServiceVersionKind {
+			return true
+		}
+	}
+	return false
+}
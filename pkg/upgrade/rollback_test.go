@@ -0,0 +1,106 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/percona/percona-everest-cli/pkg/install"
+)
+
+func TestCheckSnapshot(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkSnapshot(&snapshot{}, false))
+	assert.Error(t, checkSnapshot(&snapshot{Completed: true}, false))
+	assert.NoError(t, checkSnapshot(&snapshot{Completed: true}, true))
+}
+
+func TestSubscriptionRollback(t *testing.T) {
+	t.Parallel()
+
+	sub := func(csv string) *olmv1alpha1.Subscription {
+		return &olmv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "percona-server-mongodb-operator", Namespace: "dev"},
+			Status:     olmv1alpha1.SubscriptionStatus{InstalledCSV: csv},
+		}
+	}
+	upgraded := map[string]string{"dev/percona-server-mongodb-operator": "psmdb.v1.16.0"}
+
+	tests := []struct {
+		name     string
+		snapshot snapshot
+		saved    string
+		current  string
+		want     subscriptionRollback
+	}{
+		{name: "not upgraded", saved: "psmdb.v1.15.0", current: "psmdb.v1.15.0", want: restoreSubscription},
+		{name: "no saved csv", saved: "", current: "psmdb.v1.16.0", want: restoreSubscription},
+		{name: "unfinished upgrade", saved: "psmdb.v1.15.0", current: "psmdb.v1.16.0", want: downgradeSubscription},
+		{
+			name:     "upgraded by the upgrade",
+			snapshot: snapshot{Completed: true, UpgradedCSVs: upgraded},
+			saved:    "psmdb.v1.15.0",
+			current:  "psmdb.v1.16.0",
+			want:     downgradeSubscription,
+		},
+		{
+			name:     "upgraded later",
+			snapshot: snapshot{Completed: true, UpgradedCSVs: upgraded},
+			saved:    "psmdb.v1.15.0",
+			current:  "psmdb.v1.17.0",
+			want:     keepSubscription,
+		},
+		{
+			name:     "upgraded only later",
+			snapshot: snapshot{Completed: true, UpgradedCSVs: map[string]string{"dev/percona-server-mongodb-operator": "psmdb.v1.15.0"}},
+			saved:    "psmdb.v1.15.0",
+			current:  "psmdb.v1.16.0",
+			want:     keepSubscription,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.snapshot.subscriptionRollback(sub(tt.saved), sub(tt.current)))
+		})
+	}
+}
+
+func TestUpgradesSubscription(t *testing.T) {
+	t.Parallel()
+
+	u := &Upgrade{
+		config: Config{
+			SystemNamespace: "everest-system",
+			NamespacesList:  []string{"dev", "prod"},
+		},
+		monitoringNamespace: "everest-monitoring",
+		namespaceOperators:  install.NamespaceOperators{"prod": {PXC: true}},
+	}
+
+	assert.True(t, u.upgradesSubscription("everest-system", "everest-operator"))
+	assert.True(t, u.upgradesSubscription("everest-monitoring", "victoriametrics-operator"))
+	assert.True(t, u.upgradesSubscription("dev", "percona-server-mongodb-operator"))
+	assert.True(t, u.upgradesSubscription("prod", "percona-xtradb-cluster-operator"))
+	assert.False(t, u.upgradesSubscription("prod", "percona-server-mongodb-operator"))
+	assert.False(t, u.upgradesSubscription("staging", "percona-server-mongodb-operator"))
+}
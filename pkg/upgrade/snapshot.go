@@ -0,0 +1,279 @@
+// percona-everest-cli
+// Copyright (C) 2023 Percona LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+)
+
+// SnapshotConfigMapName is the name of the config map storing the state
+// of Everest captured before the upgrade.
+const SnapshotConfigMapName = "everest-upgrade-snapshot"
+
+const (
+	snapshotKeyCreatedAt      = "createdAt"
+	snapshotKeyEverestVersion = "everestVersion"
+	snapshotKeyCatalogImage   = "catalogImage"
+	snapshotKeySubscriptions  = "subscriptions"
+	snapshotKeyDeployments    = "deployments"
+	snapshotKeyCompleted      = "completed"
+	snapshotKeyUpgradedCSVs   = "upgradedCSVs"
+)
+
+// snapshot holds the objects touched by the upgrade as they were before it.
+type snapshot struct {
+	CreatedAt      string
+	EverestVersion string
+	CatalogImage   string
+	Subscriptions  []olmv1alpha1.Subscription
+	Deployments    []appsv1.Deployment
+	// Completed is true once the upgrade following the snapshot has succeeded.
+	Completed bool
+	// UpgradedCSVs maps the saved subscriptions to the CSVs installed
+	// when the upgrade has completed.
+	UpgradedCSVs map[string]string
+}
+
+// subscriptionKey returns the key of the subscription in UpgradedCSVs.
+func subscriptionKey(sub *olmv1alpha1.Subscription) string {
+	return types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}.String()
+}
+
+// saveSnapshot captures the catalog image, the subscriptions changed by the upgrade
+// and the Everest deployments in the snapshot config map.
+// The snapshot of an upgrade which has not completed is kept so that retrying
+// the upgrade does not replace the state of Everest before the first attempt.
+func (u *Upgrade) saveSnapshot(ctx context.Context) error {
+	if u.config.DryRun {
+		return nil
+	}
+
+	cm, err := u.kubeClient.GetConfigMap(ctx, SnapshotConfigMapName, u.config.SystemNamespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Join(err, errors.New("could not get the state of Everest saved before the upgrade"))
+	}
+	if err == nil {
+		prev, err := parseSnapshot(cm)
+		if err != nil {
+			u.l.Warnf("Replacing the invalid state of Everest saved before the upgrade: %s", err)
+		} else if !prev.Completed {
+			u.l.Infof("Keeping the state of Everest %s saved before the unfinished upgrade at %s", prev.EverestVersion, prev.CreatedAt)
+			return nil
+		}
+	}
+
+	u.l.Info("Saving the state of Everest before the upgrade")
+	s := snapshot{CreatedAt: time.Now().UTC().Format(time.RFC3339)}
+
+	catalog, err := u.kubeClient.GetPerconaCatalog(ctx)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the Percona catalog"))
+	}
+	s.CatalogImage = catalog.Spec.Image
+
+	seen := map[string]struct{}{}
+	for _, ns := range append([]string{u.config.SystemNamespace, u.monitoringNamespace}, u.config.NamespacesList...) {
+		if _, ok := seen[ns]; ok {
+			continue
+		}
+		seen[ns] = struct{}{}
+		subs, err := u.kubeClient.ListSubscriptions(ctx, ns)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not list subscriptions in %s namespace", ns))
+		}
+		for _, sub := range subs.Items {
+			if !u.upgradesSubscription(sub.Namespace, sub.Name) {
+				continue
+			}
+			s.Subscriptions = append(s.Subscriptions, olmv1alpha1.Subscription{
+				ObjectMeta: metav1.ObjectMeta{Name: sub.Name, Namespace: sub.Namespace},
+				Spec:       sub.Spec,
+				Status:     olmv1alpha1.SubscriptionStatus{InstalledCSV: sub.Status.InstalledCSV},
+			})
+		}
+	}
+
+	for _, name := range []string{kubernetes.PerconaEverestDeploymentName, kubernetes.EverestOperatorDeploymentName} {
+		d, err := u.kubeClient.GetDeployment(ctx, name, u.config.SystemNamespace)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not get %s deployment", name))
+		}
+		s.Deployments = append(s.Deployments, appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: d.Name, Namespace: d.Namespace},
+			Spec:       d.Spec,
+		})
+	}
+	if s.EverestVersion, err = u.kubeClient.GetEverestVersion(ctx, u.config.SystemNamespace); err != nil {
+		return errors.Join(err, errors.New("could not get the installed version of Everest"))
+	}
+
+	cm, err = s.configMap(u.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	if err := u.kubeClient.SetConfigMap(cm); err != nil {
+		return errors.Join(err, errors.New("could not save the state of Everest"))
+	}
+	return nil
+}
+
+// upgradesSubscription returns true if the upgrade changes the subscription.
+func (u *Upgrade) upgradesSubscription(namespace, name string) bool {
+	if namespace == u.config.SystemNamespace || namespace == u.monitoringNamespace {
+		return true
+	}
+	for _, ns := range u.config.NamespacesList {
+		if ns == namespace {
+			return u.namespaceOperators.Selected(namespace, name)
+		}
+	}
+	return false
+}
+
+// completeSnapshot marks the snapshot as taken before a successful upgrade
+// so that the next upgrade replaces it. The CSVs installed by the upgrade
+// are recorded to tell them from the ones upgraded later.
+func (u *Upgrade) completeSnapshot(ctx context.Context) error {
+	if u.config.DryRun {
+		return nil
+	}
+
+	s, err := loadSnapshot(ctx, u.kubeClient, u.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	s.Completed = true
+	s.UpgradedCSVs = make(map[string]string, len(s.Subscriptions))
+	listed := map[string]struct{}{}
+	for _, saved := range s.Subscriptions {
+		if _, ok := listed[saved.Namespace]; ok {
+			continue
+		}
+		listed[saved.Namespace] = struct{}{}
+		subs, err := u.kubeClient.ListSubscriptions(ctx, saved.Namespace)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("could not list subscriptions in %s namespace", saved.Namespace))
+		}
+		for i := range subs.Items {
+			if !u.upgradesSubscription(subs.Items[i].Namespace, subs.Items[i].Name) {
+				continue
+			}
+			s.UpgradedCSVs[subscriptionKey(&subs.Items[i])] = subs.Items[i].Status.InstalledCSV
+		}
+	}
+
+	cm, err := s.configMap(u.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	if err := u.kubeClient.SetConfigMap(cm); err != nil {
+		return errors.Join(err, errors.New("could not mark the upgrade as completed"))
+	}
+	return nil
+}
+
+// configMap returns the config map storing the snapshot.
+func (s snapshot) configMap(namespace string) (*corev1.ConfigMap, error) {
+	subs, err := json.Marshal(s.Subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := json.Marshal(s.Deployments)
+	if err != nil {
+		return nil, err
+	}
+	upgradedCSVs, err := json.Marshal(s.UpgradedCSVs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SnapshotConfigMapName,
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			snapshotKeyCreatedAt:      s.CreatedAt,
+			snapshotKeyEverestVersion: s.EverestVersion,
+			snapshotKeyCatalogImage:   s.CatalogImage,
+			snapshotKeySubscriptions:  string(subs),
+			snapshotKeyDeployments:    string(deployments),
+			snapshotKeyCompleted:      strconv.FormatBool(s.Completed),
+			snapshotKeyUpgradedCSVs:   string(upgradedCSVs),
+		},
+	}, nil
+}
+
+// loadSnapshot returns the snapshot stored in the system namespace.
+func loadSnapshot(ctx context.Context, k *kubernetes.Kubernetes, systemNamespace string) (*snapshot, error) {
+	cm, err := k.GetConfigMap(ctx, SnapshotConfigMapName, systemNamespace)
+	if err != nil && k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("there is no upgrade to roll back in %s namespace", systemNamespace)
+	}
+	if err != nil {
+		return nil, errors.Join(err, errors.New("could not get the state of Everest saved before the upgrade"))
+	}
+	return parseSnapshot(cm)
+}
+
+// parseSnapshot returns the snapshot stored in the config map.
+func parseSnapshot(cm *corev1.ConfigMap) (*snapshot, error) {
+	s := &snapshot{
+		CreatedAt:      cm.Data[snapshotKeyCreatedAt],
+		EverestVersion: cm.Data[snapshotKeyEverestVersion],
+		CatalogImage:   cm.Data[snapshotKeyCatalogImage],
+	}
+	if s.CatalogImage == "" {
+		return nil, fmt.Errorf("invalid %s config map: missing %s", cm.Name, snapshotKeyCatalogImage)
+	}
+	if v, ok := cm.Data[snapshotKeyCompleted]; ok {
+		completed, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("invalid %s in %s config map", snapshotKeyCompleted, cm.Name))
+		}
+		s.Completed = completed
+	}
+	if v, ok := cm.Data[snapshotKeyUpgradedCSVs]; ok {
+		if err := json.Unmarshal([]byte(v), &s.UpgradedCSVs); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("invalid %s in %s config map", snapshotKeyUpgradedCSVs, cm.Name))
+		}
+	}
+	if err := json.Unmarshal([]byte(cm.Data[snapshotKeySubscriptions]), &s.Subscriptions); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("invalid %s in %s config map", snapshotKeySubscriptions, cm.Name))
+	}
+	if err := json.Unmarshal([]byte(cm.Data[snapshotKeyDeployments]), &s.Deployments); err != nil {
+		return nil, errors.Join(err, fmt.Errorf("invalid %s in %s config map", snapshotKeyDeployments, cm.Name))
+	}
+	return s, nil
+}
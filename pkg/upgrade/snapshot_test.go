@@ -0,0 +1,65 @@
+package upgrade
+
+import (
+	"testing"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSnapshotConfigMap(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(1)
+	s := snapshot{
+		CreatedAt:      "2024-02-20T10:00:00Z",
+		EverestVersion: "0.6.0",
+		CatalogImage:   "docker.io/percona/everest-catalog:0.6.0",
+		Subscriptions: []olmv1alpha1.Subscription{{
+			ObjectMeta: metav1.ObjectMeta{Name: "everest-operator", Namespace: "everest-system"},
+			Spec:       &olmv1alpha1.SubscriptionSpec{Channel: "stable-v0"},
+			Status:     olmv1alpha1.SubscriptionStatus{InstalledCSV: "everest-operator.v0.6.0"},
+		}},
+		Deployments: []appsv1.Deployment{{
+			ObjectMeta: metav1.ObjectMeta{Name: "percona-everest", Namespace: "everest-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}},
+	}
+
+	cm, err := s.configMap("everest-system")
+	require.NoError(t, err)
+	assert.Equal(t, SnapshotConfigMapName, cm.Name)
+	assert.Equal(t, "everest-system", cm.Namespace)
+
+	got, err := parseSnapshot(cm)
+	require.NoError(t, err)
+	assert.Equal(t, s.CatalogImage, got.CatalogImage)
+	assert.Equal(t, s.EverestVersion, got.EverestVersion)
+	assert.False(t, got.Completed)
+	require.Len(t, got.Subscriptions, 1)
+	assert.Equal(t, "stable-v0", got.Subscriptions[0].Spec.Channel)
+	assert.Equal(t, "everest-operator.v0.6.0", got.Subscriptions[0].Status.InstalledCSV)
+	require.Len(t, got.Deployments, 1)
+	assert.Equal(t, replicas, *got.Deployments[0].Spec.Replicas)
+
+	s.Completed = true
+	s.UpgradedCSVs = map[string]string{"everest-system/everest-operator": "everest-operator.v0.7.0"}
+	cm, err = s.configMap("everest-system")
+	require.NoError(t, err)
+	got, err = parseSnapshot(cm)
+	require.NoError(t, err)
+	assert.True(t, got.Completed)
+	assert.Equal(t, s.UpgradedCSVs, got.UpgradedCSVs)
+
+	delete(cm.Data, snapshotKeyCompleted)
+	got, err = parseSnapshot(cm)
+	require.NoError(t, err)
+	assert.False(t, got.Completed)
+
+	delete(cm.Data, snapshotKeyCatalogImage)
+	_, err = parseSnapshot(cm)
+	require.Error(t, err)
+}
@@ -20,20 +20,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/url"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	goversion "github.com/hashicorp/go-version"
 	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/percona/percona-everest-cli/data"
+	"github.com/percona/percona-everest-cli/pkg/bundle"
 	"github.com/percona/percona-everest-cli/pkg/install"
 	"github.com/percona/percona-everest-cli/pkg/kubernetes"
+	"github.com/percona/percona-everest-cli/pkg/kubernetes/client"
+	"github.com/percona/percona-everest-cli/pkg/version"
 )
 
+// ErrToVersionAndBundle appears when both a target version and a manifest file or a bundle are provided.
+var ErrToVersionAndBundle = errors.New("--to-version cannot be used together with a manifest file or a bundle")
+
 type (
 	// Config defines configuration required for upgrade command.
 	Config struct {
@@ -47,6 +53,36 @@ type (
 		UpgradeOLM bool `mapstructure:"upgrade-olm"`
 		// SkipWizard skips wizard during installation.
 		SkipWizard bool `mapstructure:"skip-wizard"`
+		// CatalogImage is the image of the Everest OLM catalog.
+		// If empty, the image matching the CLI version is used.
+		CatalogImage string `mapstructure:"catalog-image"`
+		// DisableTelemetry disables telemetry in the upgraded operators.
+		DisableTelemetry bool `mapstructure:"disable-telemetry"`
+		// DryRun records the changes instead of applying them to the cluster.
+		DryRun bool `mapstructure:"dry-run"`
+		// ManifestFile is a path to a local Everest manifest
+		// used instead of downloading it.
+		ManifestFile string `mapstructure:"manifest-file"`
+		// Bundle is a path to an offline installation bundle
+		// created by `everestctl bundle create`.
+		Bundle string `mapstructure:"bundle"`
+		// RegistryMirror is a list of "source=mirror" pairs used to
+		// rewrite the images to a private registry.
+		RegistryMirror []string `mapstructure:"registry-mirror"`
+		// ImagePullSecret is the name of the secret used to pull the images.
+		ImagePullSecret string `mapstructure:"image-pull-secret"`
+		// NamespaceOperators is a raw mapping of namespaces to the operators
+		// upgraded in them. Defaults to the mapping stored by the installation.
+		NamespaceOperators string `mapstructure:"namespace-operators"`
+		// SystemNamespace is the namespace where everest is installed.
+		SystemNamespace string `mapstructure:"system-namespace"`
+		// ToVersion is the Everest release to upgrade to.
+		// If empty, the release matching the CLI version is used.
+		ToVersion string `mapstructure:"to-version"`
+
+		// Channel stores the channels the operators are switched to.
+		// Empty values keep the current channels.
+		Channel install.ChannelConfig
 	}
 	// Upgrade struct implements upgrade command.
 	Upgrade struct {
@@ -54,6 +90,14 @@ type (
 
 		config     Config
 		kubeClient *kubernetes.Kubernetes
+		// namespaceOperators limits the database operators upgraded in the mapped namespaces.
+		namespaceOperators install.NamespaceOperators
+		// monitoringNamespace is the monitoring namespace of the installation.
+		monitoringNamespace string
+		// registry is the private registry the images are pulled from.
+		registry kubernetes.RegistryConfig
+		// bundleVersion is the Everest release shipped in the bundle.
+		bundleVersion string
 	}
 )
 
@@ -64,21 +108,63 @@ func NewUpgrade(c Config, l *zap.SugaredLogger) (*Upgrade, error) {
 		l:      l.With("component", "upgrade"),
 	}
 
-	k, err := kubernetes.New(c.KubeconfigPath, cli.l)
+	b, err := bundle.Load(c.Bundle, c.ManifestFile)
 	if err != nil {
-		var u *url.Error
-		if errors.As(err, &u) {
-			cli.l.Error("Could not connect to Kubernetes. " +
-				"Make sure Kubernetes is running and is accessible from this computer/server.")
+		return nil, err
+	}
+	if c.ToVersion != "" {
+		if b != nil {
+			return nil, ErrToVersionAndBundle
+		}
+		v, err := goversion.NewSemver(c.ToVersion)
+		if err != nil || v.Prerelease() != "" {
+			return nil, fmt.Errorf("invalid version %q. Provide an Everest release, e.g. 0.7.0", c.ToVersion)
 		}
+		cli.config.ToVersion = v.String()
+	}
+	registry, err := kubernetes.NewRegistryConfig(c.RegistryMirror, c.ImagePullSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := kubernetes.Connect(c.KubeconfigPath, cli.l)
+	if err != nil {
 		return nil, err
 	}
+	if c.DryRun {
+		k.EnableDryRun()
+	}
+	k.SetRegistry(registry)
+	cli.registry = registry
+	if b != nil {
+		k.SetManifest(b.Manifest)
+		if cli.config.CatalogImage == "" {
+			cli.config.CatalogImage = b.Metadata.CatalogImage
+		}
+		if v, err := goversion.NewSemver(b.Metadata.Version); err == nil && v.Prerelease() == "" {
+			cli.bundleVersion = v.String()
+		}
+	}
+	if cli.config.ToVersion != "" {
+		k.SetManifestURL(version.ReleaseManifestURL(cli.config.ToVersion))
+		if cli.config.CatalogImage == "" {
+			cli.config.CatalogImage = version.ReleaseCatalogImage(cli.config.ToVersion)
+		}
+	}
 	cli.kubeClient = k
 	return cli, nil
 }
 
+// Plan returns the changes recorded in dry-run mode.
+func (u *Upgrade) Plan() *client.Plan {
+	return u.kubeClient.DryRunPlan()
+}
+
 // Run runs the operators installation process.
 func (u *Upgrade) Run(ctx context.Context) error {
+	if err := u.checkCompatibility(ctx); err != nil {
+		return err
+	}
 	if err := u.runEverestWizard(ctx); err != nil {
 		return err
 	}
@@ -87,11 +173,45 @@ func (u *Upgrade) Run(ctx context.Context) error {
 		return err
 	}
 	u.config.NamespacesList = l
-	if err := u.upgradeOLM(ctx); err != nil {
+	if err := u.loadNamespaceOperators(ctx); err != nil {
+		return err
+	}
+	olm, err := install.LoadOLMState(ctx, u.kubeClient, u.config.SystemNamespace)
+	if err != nil {
+		return err
+	}
+	u.kubeClient.SetOLMNamespace(olm.Namespace)
+	if u.monitoringNamespace, err = install.LoadMonitoringNamespace(ctx, u.kubeClient, u.config.SystemNamespace); err != nil {
+		return err
+	}
+	if err := u.saveSnapshot(ctx); err != nil {
+		return err
+	}
+	if err := u.upgrade(ctx, olm); err != nil {
+		if !u.config.DryRun {
+			u.l.Error("Upgrade has failed. Run `everestctl upgrade rollback` to restore the previous version of Everest")
+		}
+		return err
+	}
+	return u.completeSnapshot(ctx)
+}
+
+// upgrade upgrades OLM, the Percona catalog, the subscriptions and Everest.
+func (u *Upgrade) upgrade(ctx context.Context, olm install.OLMState) error {
+	if olm.Existing {
+		u.l.Infof("Everest uses the existing OLM installation. Skipping the upgrade of OLM")
+	} else if err := u.upgradeOLM(ctx); err != nil {
+		return err
+	}
+	if err := u.copyPullSecret(ctx); err != nil {
 		return err
 	}
 	u.l.Info("Upgrading Percona Catalog")
-	if err := u.kubeClient.InstallPerconaCatalog(ctx); err != nil {
+	catalogImage := u.config.CatalogImage
+	if catalogImage == "" {
+		catalogImage = version.CatalogImage()
+	}
+	if err := u.kubeClient.InstallPerconaCatalog(ctx, catalogImage); err != nil {
 		return err
 	}
 	u.l.Info("Percona Catalog has been upgraded")
@@ -100,17 +220,54 @@ func (u *Upgrade) Run(ctx context.Context) error {
 		return err
 	}
 	u.l.Info("Subscriptions have been patched")
+	if err := u.switchChannels(ctx); err != nil {
+		return err
+	}
 	u.l.Info("Upgrading Everest")
-	if err := u.kubeClient.InstallEverest(ctx, install.SystemNamespace); err != nil {
+	// The manifest ships the default service type. Keep the one chosen during the installation.
+	serviceType, err := u.kubeClient.GetEverestServiceType(ctx, u.config.SystemNamespace)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the type of the Everest service"))
+	}
+	u.kubeClient.SetEverestServiceType(serviceType)
+	if err := u.kubeClient.InstallEverest(ctx, u.config.SystemNamespace); err != nil {
 		return err
 	}
+	// The manifest binds the everest-admin cluster role in the system namespace only.
+	dbNamespaces, err := u.kubeClient.GetDBNamespaces(ctx, u.config.SystemNamespace)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+	err = u.kubeClient.UpdateClusterRoleBinding(ctx, install.EverestClusterRoleBindingName(u.config.SystemNamespace), dbNamespaces)
+	if err != nil {
+		return errors.Join(err, errors.New("could not update cluster role bindings for everest-admin"))
+	}
 	u.l.Info("Everest has been upgraded")
 	return nil
 }
 
+// copyPullSecret copies the image pull secret from the system namespace
+// into the OLM, monitoring and database namespaces.
+func (u *Upgrade) copyPullSecret(ctx context.Context) error {
+	if u.registry.PullSecret == "" {
+		return nil
+	}
+	dbNamespaces, err := u.kubeClient.GetDBNamespaces(ctx, u.config.SystemNamespace)
+	if err != nil {
+		return errors.Join(err, errors.New("could not get the namespaces managed by Everest"))
+	}
+	namespaces := append([]string{u.kubeClient.GetOLMNamespace(), u.monitoringNamespace}, dbNamespaces...)
+	u.l.Infof("Copying image pull secret %s to %s namespaces", u.registry.PullSecret, strings.Join(namespaces, ", "))
+	if err := u.kubeClient.CopySecret(ctx, u.registry.PullSecret, u.config.SystemNamespace, namespaces...); err != nil {
+		return errors.Join(err, fmt.Errorf("could not copy image pull secret %s from %s namespace",
+			u.registry.PullSecret, u.config.SystemNamespace))
+	}
+	return nil
+}
+
 func (u *Upgrade) runEverestWizard(ctx context.Context) error {
 	if !u.config.SkipWizard {
-		namespaces, err := u.kubeClient.GetDBNamespaces(ctx, install.SystemNamespace)
+		namespaces, err := u.kubeClient.GetDBNamespaces(ctx, u.config.SystemNamespace)
 		if err != nil {
 			return err
 		}
@@ -144,17 +301,17 @@ func (u *Upgrade) patchSubscriptions(ctx context.Context) error {
 			u.l.Warn(fmt.Sprintf("No subscriptions found in '%s' namespace", namespace))
 			continue
 		}
-		disableTelemetryEnvVar := "DISABLE_TELEMETRY"
-		disableTelemetry, ok := os.LookupEnv(disableTelemetryEnvVar)
-		if !ok || disableTelemetry != "true" {
-			disableTelemetry = "false"
-		}
+		disableTelemetry := strconv.FormatBool(u.config.DisableTelemetry)
 		for _, subscription := range subList.Items {
+			if !u.namespaceOperators.Selected(namespace, subscription.Name) {
+				u.l.Infof("Skipping %s subscription in '%s' namespace not selected in namespace operators", subscription.Name, namespace)
+				continue
+			}
 			u.l.Info(fmt.Sprintf("Patching %s subscription in '%s' namespace", subscription.Name, subscription.Namespace))
 			subscription := subscription
 			for i := range subscription.Spec.Config.Env {
 				env := subscription.Spec.Config.Env[i]
-				if env.Name == disableTelemetryEnvVar {
+				if env.Name == install.DisableTelemetryEnvVar {
 					env.Value = disableTelemetry
 					subscription.Spec.Config.Env[i] = env
 				}
@@ -167,10 +324,64 @@ func (u *Upgrade) patchSubscriptions(ctx context.Context) error {
 	return nil
 }
 
+// switchChannels validates the requested channels against the Percona catalog
+// and updates the existing subscriptions in place.
+func (u *Upgrade) switchChannels(ctx context.Context) error {
+	channels := []install.OperatorChannel{}
+	for _, ch := range u.config.Channel.OperatorChannels(u.config.SystemNamespace, u.monitoringNamespace, u.config.NamespacesList) {
+		if u.namespaceOperators.Selected(ch.Namespace, ch.Operator) {
+			channels = append(channels, ch)
+		}
+	}
+	validated := make(map[string]struct{}, len(channels))
+	for _, ch := range channels {
+		if _, ok := validated[ch.Operator]; ok {
+			continue
+		}
+		err := u.kubeClient.ValidateOperatorChannel(ctx, ch.Operator, ch.Channel)
+		if err != nil && u.config.DryRun && k8serrors.IsNotFound(err) {
+			u.l.Warnf("Channel %s of %s cannot be validated in dry-run mode", ch.Channel, ch.Operator)
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+		validated[ch.Operator] = struct{}{}
+	}
+
+	for _, ch := range channels {
+		if err := u.kubeClient.SwitchSubscriptionChannel(ctx, ch.Namespace, ch.Operator, ch.Channel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadNamespaceOperators parses the provided namespace operators
+// or loads the ones stored by the installation.
+func (u *Upgrade) loadNamespaceOperators(ctx context.Context) error {
+	if u.config.NamespaceOperators == "" {
+		m, err := install.LoadNamespaceOperators(ctx, u.kubeClient, u.config.SystemNamespace)
+		if err != nil {
+			return err
+		}
+		u.namespaceOperators = m
+		return nil
+	}
+
+	m, err := install.ParseNamespaceOperators(u.config.NamespaceOperators)
+	if err != nil {
+		return err
+	}
+	u.namespaceOperators = m
+	return nil
+}
+
 func (u *Upgrade) upgradeOLM(ctx context.Context) error {
 	csv, err := u.kubeClient.GetClusterServiceVersion(ctx, types.NamespacedName{
 		Name:      "packageserver",
-		Namespace: kubernetes.OLMNamespace,
+		Namespace: u.kubeClient.GetOLMNamespace(),
 	})
 	if err != nil {
 		return err
@@ -69,6 +69,22 @@ func ManifestURL() string {
 	return url
 }
 
+// ReleaseCatalogImage returns the catalog image of the given Everest release.
+func ReleaseCatalogImage(v string) string {
+	return fmt.Sprintf(releaseCatalogImage, v)
+}
+
+// ReleaseManifestURL returns the manifest URL of the given Everest release.
+func ReleaseManifestURL(v string) string {
+	return fmt.Sprintf(releaseManifestURL, v)
+}
+
+// IsRelease returns true if the CLI is a release build.
+func IsRelease() bool {
+	v, err := goversion.NewSemver(Version)
+	return Version != "" && err == nil && v.Prerelease() == ""
+}
+
 // FullVersionInfo returns full version report.
 func FullVersionInfo() string {
 	out := []string{